@@ -2,6 +2,7 @@ package v1
 
 import (
 	"io"
+	"io/fs"
 
 	docv1 "github.com/naivary/codemark/api/doc/v1"
 	infov1 "github.com/naivary/codemark/api/info/v1"
@@ -27,9 +28,21 @@ type Generator interface {
 	ConfigDoc() map[string]docv1.Config
 }
 
+// ConfigSchemer is an optional interface of a Generator shipping a JSON Schema
+// of its configuration. The config of the generator is validated against the
+// schema before Generate is called.
+type ConfigSchemer interface {
+	// ConfigSchema returns the file system containing the JSON Schemas of the
+	// config and the path of the root schema in it. References between the
+	// schemas are resolved by their `$id` without any network access.
+	ConfigSchema() (fs.FS, string)
+}
+
 type Artifact struct {
 	// Name of the Artifact. Make sure the name can be used as a file name
 	// because it might be written to the filesystem (including the extension).
+	// The name has to be relative because it is resolved by the outputer e.g.
+	// against the output directory.
 	Name string
 
 	// The actual data of the artifact created by interpreting the markers.
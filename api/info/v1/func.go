@@ -1,12 +1,32 @@
 package v1
 
-import "go/ast"
+import (
+	"go/ast"
+	"go/types"
+)
 
 type FuncInfo struct {
 	Decl *ast.FuncDecl
 	Opts Options
+
+	Params  map[types.Object]*ParamInfo
+	Results map[types.Object]*ParamInfo
 }
 
 func (f *FuncInfo) Options() Options {
 	return f.Opts
 }
+
+// ParamInfo is the information of a parameter or result of a function. The
+// object of an unnamed parameter or result is the corresponding variable of the
+// signature.
+type ParamInfo struct {
+	Field *ast.Field
+	// Ident is nil if the parameter or result is unnamed.
+	Ident *ast.Ident
+	Opts  Options
+}
+
+func (p *ParamInfo) Options() Options {
+	return p.Opts
+}
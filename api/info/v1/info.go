@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"go/token"
 	"go/types"
 
 	"golang.org/x/tools/go/packages"
@@ -15,6 +16,10 @@ type Project = map[*packages.Package]*Information
 type Filename = string
 
 type Information struct {
+	// Fset is the file set of the package which allows to resolve the
+	// positions of the objects.
+	Fset *token.FileSet
+
 	Structs map[types.Object]*StructInfo
 	Ifaces  map[types.Object]*IfaceInfo
 	Aliases map[types.Object]*AliasInfo
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"cmp"
+	"go/token"
+	"go/types"
+	"iter"
+	"maps"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Packages returns the packages of the project sorted by their ID. This allows
+// to iterate over a project in a stable order.
+func Packages(proj Project) []*packages.Package {
+	return slices.SortedFunc(maps.Keys(proj), func(a, b *packages.Package) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// ByPos returns an iterator over `infos` sorted by the position of the objects
+// in the source code. Objects of different files are sorted by their
+// filename. `fset` is the file set used to load the objects. If `fset` is nil
+// the objects are sorted by their raw position which is only stable for
+// objects of the same file e.g. the fields of a struct.
+func ByPos[V any](fset *token.FileSet, infos map[types.Object]V) iter.Seq2[types.Object, V] {
+	objs := slices.SortedFunc(maps.Keys(infos), func(a, b types.Object) int {
+		return comparePos(fset, a, b)
+	})
+	return ordered(objs, infos)
+}
+
+// ByName returns an iterator over `infos` sorted by the name of the objects.
+// Objects with the same name are sorted by their position.
+func ByName[V any](infos map[types.Object]V) iter.Seq2[types.Object, V] {
+	objs := slices.SortedFunc(maps.Keys(infos), func(a, b types.Object) int {
+		if c := cmp.Compare(a.Name(), b.Name()); c != 0 {
+			return c
+		}
+		return comparePos(nil, a, b)
+	})
+	return ordered(objs, infos)
+}
+
+// All returns an iterator over all the infos of the package which are
+// identifiable by a types.Object sorted by their position in the source code.
+func (i *Information) All() iter.Seq2[types.Object, Info] {
+	all := make(map[types.Object]Info, i.len())
+	collect(all, i.Structs)
+	collect(all, i.Ifaces)
+	collect(all, i.Aliases)
+	collect(all, i.Named)
+	collect(all, i.Consts)
+	collect(all, i.Vars)
+	collect(all, i.Imports)
+	collect(all, i.Funcs)
+	return ByPos(i.Fset, all)
+}
+
+func (i *Information) len() int {
+	return len(i.Structs) + len(i.Ifaces) + len(i.Aliases) + len(i.Named) + len(i.Consts) + len(i.Vars) + len(i.Imports) + len(i.Funcs)
+}
+
+func collect[V Info](all map[types.Object]Info, infos map[types.Object]V) {
+	for obj, info := range infos {
+		all[obj] = info
+	}
+}
+
+func ordered[V any](objs []types.Object, infos map[types.Object]V) iter.Seq2[types.Object, V] {
+	return func(yield func(types.Object, V) bool) {
+		for _, obj := range objs {
+			if !yield(obj, infos[obj]) {
+				return
+			}
+		}
+	}
+}
+
+func comparePos(fset *token.FileSet, a, b types.Object) int {
+	if fset == nil {
+		return cmp.Compare(a.Pos(), b.Pos())
+	}
+	posA, posB := fset.Position(a.Pos()), fset.Position(b.Pos())
+	if c := cmp.Compare(posA.Filename, posB.Filename); c != 0 {
+		return c
+	}
+	return cmp.Compare(posA.Offset, posB.Offset)
+}
@@ -17,6 +17,8 @@ const (
 	TargetAlias
 	TargetIfaceSig // Interface Signature
 	TargetStruct
+	TargetFuncParam  // Parameter of a function or method
+	TargetFuncResult // Result of a function or method
 	TargetAny
 )
 
@@ -46,6 +48,10 @@ func (t Target) String() string {
 		return "IfaceSig"
 	case TargetStruct:
 		return "Struct"
+	case TargetFuncParam:
+		return "FuncParam"
+	case TargetFuncResult:
+		return "FuncResult"
 	case TargetAny:
 		return "Any"
 	default:
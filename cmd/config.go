@@ -4,6 +4,7 @@ import (
 	"github.com/goccy/go-yaml"
 
 	"github.com/naivary/codemark/internal/config"
+	"github.com/naivary/codemark/loader"
 )
 
 type cliConfig struct {
@@ -26,3 +27,18 @@ func newConfig(cfgFile string) (*cliConfig, error) {
 	err = yaml.Unmarshal(data, &c)
 	return &c, err
 }
+
+func newLoaderConfig(cfgFile string) (*loader.Config, error) {
+	const configSection = "loader"
+	c := loader.Config{}
+	cfg, err := config.ReadIn(cfgFile, configSection)
+	if err != nil {
+		return nil, err
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &c)
+	return &c, err
+}
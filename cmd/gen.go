@@ -7,35 +7,56 @@ import (
 
 	convv1 "github.com/naivary/codemark/api/converter/v1"
 	"github.com/naivary/codemark/generator"
+	"github.com/naivary/codemark/loader"
 	"github.com/naivary/codemark/outputer"
 )
 
 type genCmd struct {
 	outputers []string
+	gens      []string
+
+	// loader options overwriting the loader section of the config file
+	tests     bool
+	buildTags []string
+	goos      string
+	goarch    string
 }
 
-func makeGenCmd(cfg *cliConfig, genMngr *generator.Manager, outMngr *outputer.Manager, convs []convv1.Converter) *cobra.Command {
+func makeGenCmd(
+	cfg *cliConfig,
+	loaderCfg *loader.Config,
+	genMngr *generator.Manager,
+	outMngr *outputer.Manager,
+	convs []convv1.Converter,
+) *cobra.Command {
 	g := &genCmd{}
 	cmd := &cobra.Command{
 		Use:     "generate [pattern]",
 		Short:   "generate the artifacts for the given pattern",
 		Aliases: []string{"gen"},
-		RunE:    g.runE(cfg, genMngr, outMngr, convs),
+		RunE:    g.runE(cfg, loaderCfg, genMngr, outMngr, convs),
 	}
 	cmd.Flags().
 		StringSliceVarP(&g.outputers, "out", "o", nil, "define one or multiple ouptuter for each domain in the syntax of `domain:outputerName` e.g. `openapi:stdout`")
+	cmd.Flags().
+		StringSliceVarP(&g.gens, "gen", "g", nil, "domains of the generators to run e.g. `openapi,docs`. By default the openapi generator, your own generators and the generators configured in the config file are run")
+	cmd.Flags().BoolVar(&g.tests, "tests", false, "include the test files of the packages")
+	cmd.Flags().StringSliceVar(&g.buildTags, "tags", nil, "additional build tags to consider while loading the packages e.g. `enterprise`")
+	cmd.Flags().StringVar(&g.goos, "goos", "", "target operating system to load the packages for")
+	cmd.Flags().StringVar(&g.goarch, "goarch", "", "target architecture to load the packages for")
 	return cmd
 }
 
 func (g *genCmd) runE(
 	cfg *cliConfig,
+	loaderCfg *loader.Config,
 	genMngr *generator.Manager,
 	outMngr *outputer.Manager,
 	convs []convv1.Converter,
 ) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		pattern := args[0]
-		artifacts, err := genMngr.Generate(convs, pattern)
+		artifacts, err := genMngr.Generate(convs, g.loaderConfig(cmd, loaderCfg), pattern, g.gens...)
 		if err != nil {
 			return err
 		}
@@ -50,6 +71,26 @@ func (g *genCmd) runE(
 	}
 }
 
+// loaderConfig returns the loader configuration of the config file with the
+// explicitly set flags taking precedence.
+func (g *genCmd) loaderConfig(cmd *cobra.Command, loaderCfg *loader.Config) *loader.Config {
+	c := *loaderCfg
+	flags := cmd.Flags()
+	if flags.Changed("tests") {
+		c.Tests = g.tests
+	}
+	if flags.Changed("tags") {
+		c.BuildTags = g.buildTags
+	}
+	if flags.Changed("goos") {
+		c.GOOS = g.goos
+	}
+	if flags.Changed("goarch") {
+		c.GOARCH = g.goarch
+	}
+	return &c
+}
+
 func (g *genCmd) outputerMap(cfg *cliConfig, domains []string) map[string]string {
 	const sep = ":"
 	res := make(map[string]string, len(g.outputers))
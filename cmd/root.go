@@ -35,12 +35,16 @@ func Exec(gens []genv1.Generator, outs []outv1.Outputer, convs []convv1.Converte
 	if err != nil {
 		return InternalErr, err
 	}
+	loaderCfg, err := newLoaderConfig(cfgFile)
+	if err != nil {
+		return InternalErr, err
+	}
 	genMngr, outMngr, err := makeManager(cfgFile, gens, outs)
 	if err != nil {
 		return InternalErr, err
 	}
 	rootCmd.AddCommand(
-		makeGenCmd(cfg, genMngr, outMngr, convs),
+		makeGenCmd(cfg, loaderCfg, genMngr, outMngr, convs),
 		makeExplainCmd(genMngr, outMngr),
 	)
 	err = rootCmd.Execute()
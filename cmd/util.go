@@ -6,7 +6,12 @@ import (
 	genv1 "github.com/naivary/codemark/api/generator/v1"
 	outv1 "github.com/naivary/codemark/api/outputer/v1"
 	"github.com/naivary/codemark/generator"
+	"github.com/naivary/codemark/internal/generator/crd"
+	"github.com/naivary/codemark/internal/generator/docs"
 	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/internal/generator/proto"
+	"github.com/naivary/codemark/internal/generator/typescript"
+	"github.com/naivary/codemark/internal/generator/validate"
 	outimpl "github.com/naivary/codemark/internal/outputer"
 	"github.com/naivary/codemark/outputer"
 )
@@ -32,6 +37,20 @@ func newGenManager(cfgFile string, gens []genv1.Generator) (*generator.Manager,
 			return nil, err
 		}
 	}
+	// optional generators are only run if they are selected using `--gen` or
+	// configured in the config file.
+	optionalGens := []genv1.Generator{
+		mustInit(crd.New),
+		mustInit(typescript.New),
+		mustInit(proto.New),
+		mustInit(validate.New),
+		mustInit(docs.New),
+	}
+	for _, gen := range optionalGens {
+		if err := mngr.AddOptional(gen); err != nil {
+			return nil, err
+		}
+	}
 	return mngr, nil
 }
 
@@ -137,6 +137,9 @@ func (m *Manager) builtin(rtype reflect.Type) convv1.Converter {
 	if rtypeutil.IsBool(rtype) {
 		return NewBool()
 	}
+	if isTextUnmarshaler(rtype) {
+		return NewText()
+	}
 	if rtypeutil.IsString(rtype) {
 		return NewString()
 	}
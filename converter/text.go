@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	convv1 "github.com/naivary/codemark/api/converter/v1"
+	"github.com/naivary/codemark/marker"
+	"github.com/naivary/codemark/rtypeutil"
+)
+
+var _ convv1.Converter = (*textConverter)(nil)
+
+var _textUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// textConverter converts string, integer and float markers to string types
+// implementing encoding.TextUnmarshaler. The literal of the marker is passed
+// to UnmarshalText which allows options to keep the exact value of a marker
+// e.g. integers which cannot be represented by a float64.
+type textConverter struct {
+	name string
+}
+
+func NewText() convv1.Converter {
+	return &textConverter{
+		name: "text",
+	}
+}
+
+func (t *textConverter) Name() string {
+	return NewName(_codemark, t.name)
+}
+
+// SupportedTypes returns no types because every string type implementing
+// encoding.TextUnmarshaler is supported.
+func (t *textConverter) SupportedTypes() []reflect.Type {
+	return []reflect.Type{}
+}
+
+func (t *textConverter) CanConvert(m marker.Marker, to reflect.Type) error {
+	if m.Kind != marker.STRING && m.Kind != marker.INT && m.Kind != marker.FLOAT {
+		return fmt.Errorf(
+			"marker kind of `%s` cannot be converted to a text. valid options are: %s, %s, %s",
+			m.Kind,
+			marker.STRING,
+			marker.INT,
+			marker.FLOAT,
+		)
+	}
+	if !isTextUnmarshaler(to) {
+		return fmt.Errorf("type does not implement encoding.TextUnmarshaler: %v", to)
+	}
+	return nil
+}
+
+func (t *textConverter) Convert(m marker.Marker, to reflect.Type) (reflect.Value, error) {
+	text := textOf(m)
+	out := reflect.New(rtypeutil.Deref(to))
+	if err := out.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+		return _rvzero, fmt.Errorf("`%s` cannot be converted to `%v`: %w", m.String(), to, err)
+	}
+	if rtypeutil.IsPointer(to) {
+		return out, nil
+	}
+	return out.Elem(), nil
+}
+
+// textOf returns the literal of a number marker as written in the comment or
+// the value of a string marker. Number markers which have not been parsed have
+// no literal and are formatted instead.
+func textOf(m marker.Marker) string {
+	if m.Literal != "" {
+		return m.Literal
+	}
+	switch m.Kind {
+	case marker.INT:
+		return strconv.FormatInt(m.Value.Int(), 10)
+	case marker.FLOAT:
+		return strconv.FormatFloat(m.Value.Float(), 'g', -1, 64)
+	}
+	return m.Value.String()
+}
+
+// isTextUnmarshaler reports whether `rtype` is a string type whose pointer
+// implements encoding.TextUnmarshaler.
+func isTextUnmarshaler(rtype reflect.Type) bool {
+	rtype = rtypeutil.Deref(rtype)
+	return rtype.Kind() == reflect.String && reflect.PointerTo(rtype).Implements(_textUnmarshaler)
+}
@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/naivary/codemark/marker"
+	"github.com/naivary/codemark/rtypeutil"
+)
+
+// literal is a string type keeping the literal of a number marker.
+type literal string
+
+func (l *literal) UnmarshalText(text []byte) error {
+	if _, err := strconv.ParseFloat(string(text), 64); err != nil {
+		return errors.New("not a number")
+	}
+	*l = literal(text)
+	return nil
+}
+
+func TestTextConverter(t *testing.T) {
+	tests := []struct {
+		name    string
+		marker  marker.Marker
+		to      reflect.Type
+		isValid bool
+		want    string
+	}{
+		{
+			name:    "exact integer",
+			marker:  marker.New("codemark:test:text", marker.INT, reflect.ValueOf(int64(9007199254740993))),
+			to:      reflect.TypeFor[literal](),
+			isValid: true,
+			want:    "9007199254740993",
+		},
+		{
+			name:    "float",
+			marker:  marker.New("codemark:test:text", marker.FLOAT, reflect.ValueOf(0.95)),
+			to:      reflect.TypeFor[*literal](),
+			isValid: true,
+			want:    "0.95",
+		},
+		{
+			name:    "float literal",
+			marker:  marker.Marker{Ident: "codemark:test:text", Kind: marker.FLOAT, Value: reflect.ValueOf(0.95), Literal: "0.950"},
+			to:      reflect.TypeFor[literal](),
+			isValid: true,
+			want:    "0.950",
+		},
+		{
+			name:    "string",
+			marker:  marker.New("codemark:test:text", marker.STRING, reflect.ValueOf("1e3")),
+			to:      reflect.TypeFor[literal](),
+			isValid: true,
+			want:    "1e3",
+		},
+		{
+			name:    "rejected by UnmarshalText",
+			marker:  marker.New("codemark:test:text", marker.STRING, reflect.ValueOf("five")),
+			to:      reflect.TypeFor[literal](),
+			isValid: false,
+		},
+		{
+			name:    "unsupported kind",
+			marker:  marker.New("codemark:test:text", marker.BOOL, reflect.ValueOf(true)),
+			to:      reflect.TypeFor[literal](),
+			isValid: false,
+		},
+	}
+	conv := NewText()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := conv.CanConvert(tc.marker, tc.to)
+			var v reflect.Value
+			if err == nil {
+				v, err = conv.Convert(tc.marker, tc.to)
+			}
+			if !tc.isValid && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !tc.isValid {
+				return
+			}
+			if err != nil {
+				t.Fatalf("err occured: %s\n", err)
+			}
+			if v.Type() != tc.to {
+				t.Fatalf("type is not correct. got: %v; want: %v\n", v.Type(), tc.to)
+			}
+			if got := rtypeutil.DerefValue(v).String(); got != tc.want {
+				t.Errorf("value is not correct. got: %v; want: %v\n", got, tc.want)
+			}
+		})
+	}
+}
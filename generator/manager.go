@@ -18,13 +18,18 @@ type domain = string
 type Manager struct {
 	gens map[domain]genv1.Generator
 
+	// optional generators are only run if they are selected or configured in
+	// the config file.
+	optional map[domain]bool
+
 	cfg map[string]any
 }
 
 func NewManager(cfgFile string, gens ...genv1.Generator) (*Manager, error) {
 	const configSection = "gens"
 	mngr := &Manager{
-		gens: make(map[domain]genv1.Generator, len(gens)),
+		gens:     make(map[domain]genv1.Generator, len(gens)),
+		optional: make(map[domain]bool),
 	}
 	cfg, err := config.ReadIn(cfgFile, configSection)
 	if err != nil {
@@ -48,17 +53,38 @@ func (m *Manager) Gens() map[domain]genv1.Generator {
 	return m.gens
 }
 
-func (m *Manager) Generate(convs []convv1.Converter, pattern string) (map[domain][]*genv1.Artifact, error) {
+// Generate loads the packages matching `pattern` using `loaderCfg` and
+// generates the artifacts of the generators of `domains`. If no domains are
+// given all generators are run except the optional ones which are not
+// configured in the config file.
+func (m *Manager) Generate(
+	convs []convv1.Converter,
+	loaderCfg *loader.Config,
+	pattern string,
+	domains ...string,
+) (map[domain][]*genv1.Artifact, error) {
+	gens, err := m.selectGens(domains)
+	if err != nil {
+		return nil, err
+	}
+	// the configs are validated before loading the packages to fail fast.
+	for _, gen := range gens {
+		if err := validateConfig(gen, m.configFor(gen)); err != nil {
+			return nil, fmt.Errorf("config of generator `%s` is invalid: %w", gen.Domain().Name, err)
+		}
+	}
+	// the markers of all domains can occur in the loaded packages, not only
+	// the ones of the selected generators.
 	reg, err := m.merge(m.allGens())
 	if err != nil {
 		return nil, err
 	}
-	info, err := loader.Load(reg, convs, pattern)
+	info, err := loader.LoadWithConfig(reg, convs, loaderCfg, pattern)
 	if err != nil {
 		return nil, err
 	}
-	output := make(map[domain][]*genv1.Artifact)
-	for _, gen := range m.gens {
+	output := make(map[domain][]*genv1.Artifact, len(gens))
+	for _, gen := range gens {
 		artifacts, err := gen.Generate(info, m.configFor(gen))
 		if err != nil {
 			return nil, err
@@ -85,11 +111,45 @@ func (m *Manager) Add(gen genv1.Generator) error {
 	return nil
 }
 
+// AddOptional adds `gen` as an optional generator which is only run if it is
+// selected or has a section in the config file.
+func (m *Manager) AddOptional(gen genv1.Generator) error {
+	if err := m.Add(gen); err != nil {
+		return err
+	}
+	m.optional[gen.Domain().Name] = true
+	return nil
+}
+
 // allGens returns all managed generators.
 func (m *Manager) allGens() []genv1.Generator {
 	return slices.Collect(maps.Values(m.gens))
 }
 
+// selectGens returns the generators of `domains` or, if none are given, all
+// generators which are not optional or are configured.
+func (m *Manager) selectGens(domains []string) ([]genv1.Generator, error) {
+	gens := make([]genv1.Generator, 0, len(m.gens))
+	if len(domains) > 0 {
+		for _, domain := range slices.Compact(slices.Sorted(slices.Values(domains))) {
+			gen, err := m.Get(domain)
+			if err != nil {
+				return nil, err
+			}
+			gens = append(gens, gen)
+		}
+		return gens, nil
+	}
+	for _, domain := range slices.Sorted(maps.Keys(m.gens)) {
+		_, isConfigured := m.cfg[domain]
+		if m.optional[domain] && !isConfigured {
+			continue
+		}
+		gens = append(gens, m.gens[domain])
+	}
+	return gens, nil
+}
+
 // configFor returns the configuration part in the found codemark.yaml for
 // `gen`.
 func (m *Manager) configFor(gen genv1.Generator) map[string]any {
@@ -100,6 +160,17 @@ func (m *Manager) configFor(gen genv1.Generator) map[string]any {
 	return make(map[string]any)
 }
 
+// validateConfig validates `cfg` against the config schema of `gen` if the
+// generator ships one.
+func validateConfig(gen genv1.Generator, cfg map[string]any) error {
+	schemer, isSchemer := gen.(genv1.ConfigSchemer)
+	if !isSchemer {
+		return nil
+	}
+	fsys, root := schemer.ConfigSchema()
+	return config.Validate(fsys, root, cfg)
+}
+
 // merge returns a registry containing all the options defined in everything
 // generator in `gens`.
 func (m *Manager) merge(gens []genv1.Generator) (regv1.Registry, error) {
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+var _ jsonschema.URLLoader = (*fsLoader)(nil)
+
+// SchemaRoot is the name of the root JSON Schema of the config of a builtin
+// generator.
+const SchemaRoot = "config.json"
+
+// Schemas returns the file system of the "schemas" directory of `fsys`. The
+// builtin generators embed the JSON Schemas of their config in this directory
+// with SchemaRoot as the root schema.
+func Schemas(fsys fs.FS) (fs.FS, error) {
+	return fs.Sub(fsys, "schemas")
+}
+
+// fsLoader loads JSON Schemas of a file system by their `$id`. It never
+// accesses the network.
+type fsLoader struct {
+	schemas map[string]any
+}
+
+func (l *fsLoader) Load(url string) (any, error) {
+	schema, found := l.schemas[url]
+	if !found {
+		return nil, fmt.Errorf("schema not found in the local schemas: %s", url)
+	}
+	return schema, nil
+}
+
+// newFSLoader returns a loader for all the JSON Schemas in `fsys` and the ids
+// of the schemas indexed by their path. Schemas without an `$id` are
+// identified by their path.
+func newFSLoader(fsys fs.FS) (*fsLoader, map[string]string, error) {
+	loader := &fsLoader{schemas: make(map[string]any)}
+	ids := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) != ".json" {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		schema, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("schema is not valid JSON: %s: %w", name, err)
+		}
+		id := "file:///" + name
+		if obj, isObj := schema.(map[string]any); isObj {
+			if schemaID, isString := obj["$id"].(string); isString {
+				id = schemaID
+			}
+		}
+		loader.schemas[id] = schema
+		ids[name] = id
+		return nil
+	})
+	return loader, ids, err
+}
+
+// Validate validates `cfg` against the JSON Schema `root` of `fsys`.
+// References to other schemas are resolved using the schemas of `fsys`.
+func Validate(fsys fs.FS, root string, cfg map[string]any) error {
+	loader, ids, err := newFSLoader(fsys)
+	if err != nil {
+		return err
+	}
+	id, found := ids[root]
+	if !found {
+		return fmt.Errorf("root schema of config not found: %s", root)
+	}
+	c := jsonschema.NewCompiler()
+	c.UseLoader(loader)
+	schema, err := c.Compile(id)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		cfg = make(map[string]any)
+	}
+	// the config is encoded as JSON to convert the values to the types
+	// expected by the validator e.g. float64 for numbers.
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return schema.Validate(inst)
+}
@@ -0,0 +1,40 @@
+package crd
+
+import (
+	"embed"
+
+	"github.com/goccy/go-yaml"
+)
+
+// _configSchemas contains config.json, the JSON Schema of `config` which
+// validates the inferRequired option.
+//
+//go:embed schemas/*.json
+var _configSchemas embed.FS
+
+func newConfig(cfg map[string]any) (*config, error) {
+	c := config{
+		InferRequired: true,
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &c)
+	return &c, err
+}
+
+// +openapi:schema:description="config options for the crd generator"
+type config struct {
+	InferRequired bool `yaml:"inferRequired"`
+}
+
+// schemaConfig returns the config of the openapi generator used to build the
+// schemas of the kinds.
+func (c *config) schemaConfig() map[string]any {
+	return map[string]any{
+		"schema": map[string]any{
+			"inferRequired": c.InferRequired,
+		},
+	}
+}
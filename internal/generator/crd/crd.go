@@ -0,0 +1,390 @@
+package crd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/types"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"golang.org/x/tools/go/packages"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	genv1 "github.com/naivary/codemark/api/generator/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	configer "github.com/naivary/codemark/internal/config"
+	"github.com/naivary/codemark/internal/generator/openapi"
+)
+
+const _domain = "crd"
+
+var (
+	_ genv1.Generator     = (*crdGenerator)(nil)
+	_ genv1.ConfigSchemer = (*crdGenerator)(nil)
+)
+
+func New() (genv1.Generator, error) {
+	gen := &crdGenerator{}
+	reg, err := newRegistry(kindResourcer{}, printerResourcer{}, subresourceResourcer{}, gen.validation)
+	if err != nil {
+		return nil, err
+	}
+	gen.reg = reg
+	gen.configSchemas, err = configer.Schemas(_configSchemas)
+	if err != nil {
+		return nil, err
+	}
+	return gen, nil
+}
+
+type crdGenerator struct {
+	reg regv1.Registry
+
+	// configSchemas are the JSON Schemas of the config.
+	configSchemas fs.FS
+
+	validation validationResourcer
+}
+
+func (g *crdGenerator) Domain() docv1.Domain {
+	return docv1.Domain{
+		Name: _domain,
+		Desc: "Generate Kubernetes CustomResourceDefinitions",
+	}
+}
+
+func (g *crdGenerator) ConfigSchema() (fs.FS, string) {
+	return g.configSchemas, configer.SchemaRoot
+}
+
+func (g *crdGenerator) Resources() map[string]*docv1.Resource {
+	return map[string]*docv1.Resource{
+		_kindResource:        {Desc: "Mark a struct as a version of the kind of a CustomResourceDefinition"},
+		_printerResource:     {Desc: "Define the additional printer columns of a version"},
+		_subresourceResource: {Desc: "Enable the status and scale subresources of a version"},
+		_validationResource:  {Desc: "Kubernetes specific validation of the OpenAPI v3 schema in addition to the openapi markers"},
+	}
+}
+
+func (g *crdGenerator) Registry() regv1.Registry {
+	return g.reg
+}
+
+func (g *crdGenerator) ConfigDoc() map[string]docv1.Config {
+	return map[string]docv1.Config{
+		"inferRequired": {
+			Default:     true,
+			Description: `Infers which fields are required like the inferRequired option of the openapi generator. Every field which is not a pointer and not tagged with omitempty or omitzero in its json struct tag is required. The required marker of a field takes precedence.`,
+		},
+	}
+}
+
+func (g *crdGenerator) Generate(proj infov1.Project, config map[string]any) ([]*genv1.Artifact, error) {
+	cfg, err := newConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := g.versions(proj)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	roots := make([]*types.TypeName, 0, len(versions))
+	for _, version := range versions {
+		roots = append(roots, version.obj)
+	}
+	builder, err := openapi.NewSchemaBuilder(proj, cfg.schemaConfig(), g.validation.hooks(), roots...)
+	if err != nil {
+		return nil, err
+	}
+	crds := make([]*customResourceDefinition, 0)
+	for _, version := range versions {
+		schema, err := builder.Schema(version.obj)
+		if err != nil {
+			return nil, fmt.Errorf("schema of kind `%s` is invalid: %w", version.kind, err)
+		}
+		props, err := structuralOf(schema)
+		if err != nil {
+			return nil, fmt.Errorf("schema of kind `%s` is invalid: %w", version.kind, err)
+		}
+		version.Schema = versionSchema{OpenAPIV3Schema: rootOf(props)}
+		idx := slices.IndexFunc(crds, func(crd *customResourceDefinition) bool {
+			return crd.Spec.Group == version.group && crd.Spec.Names.Kind == version.kind
+		})
+		if idx == -1 {
+			crds = append(crds, newCustomResourceDefinition(version))
+			continue
+		}
+		if err := crds[idx].add(version); err != nil {
+			return nil, err
+		}
+	}
+	artifacts := make([]*genv1.Artifact, 0, len(crds))
+	for _, crd := range crds {
+		if err := crd.isValid(); err != nil {
+			return nil, err
+		}
+		artifact, err := newArtifact(crd)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+// versions returns the versions of all the kinds of the project.
+func (g *crdGenerator) versions(proj infov1.Project) ([]*kindVersion, error) {
+	versions := make([]*kindVersion, 0)
+	for _, pkg := range infov1.Packages(proj) {
+		for obj, info := range infov1.ByPos(pkg.Fset, proj[pkg].Structs) {
+			opts := optionsOf(info, _kindResource)
+			if len(opts) == 0 {
+				continue
+			}
+			version, err := newKindVersion(pkg, obj.(*types.TypeName), info, opts)
+			if err != nil {
+				return nil, fmt.Errorf("kind `%s` is invalid: %w", obj.Name(), err)
+			}
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+// rootOf adds the properties every Kubernetes object has to the schema
+// `props` of a kind.
+func rootOf(props map[string]any) map[string]any {
+	props["type"] = "object"
+	properties, isMap := props["properties"].(map[string]any)
+	if !isMap {
+		properties = make(map[string]any)
+		props["properties"] = properties
+	}
+	properties["apiVersion"] = map[string]any{"type": "string"}
+	properties["kind"] = map[string]any{"type": "string"}
+	properties["metadata"] = map[string]any{"type": "object"}
+	return props
+}
+
+// kindVersion is a version of a kind defined by a struct with kind markers.
+type kindVersion struct {
+	obj *types.TypeName
+
+	group      string
+	kind       string
+	plural     string
+	shortNames []string
+	categories []string
+	scope      Scope
+	isStorage  bool
+
+	Name                     string          `json:"name"`
+	Served                   bool            `json:"served"`
+	Storage                  bool            `json:"storage"`
+	Schema                   versionSchema   `json:"schema"`
+	Subresources             *subresources   `json:"subresources,omitempty"`
+	AdditionalPrinterColumns []printerColumn `json:"additionalPrinterColumns,omitempty"`
+}
+
+type versionSchema struct {
+	OpenAPIV3Schema map[string]any `json:"openAPIV3Schema"`
+}
+
+func newKindVersion(pkg *packages.Package, obj *types.TypeName, info *infov1.StructInfo, opts []any) (*kindVersion, error) {
+	group, isDefined := optionOf[Group](opts)
+	if !isDefined {
+		return nil, errors.New("group is required")
+	}
+	if err := group.validate(); err != nil {
+		return nil, err
+	}
+	version := Version(pkg.Name)
+	if v, isDefined := optionOf[Version](opts); isDefined {
+		version = v
+	}
+	if err := version.validate(); err != nil {
+		return nil, err
+	}
+	scope := NamespacedScope
+	if s, isDefined := optionOf[Scope](opts); isDefined {
+		scope = s
+	}
+	if err := scope.validate(); err != nil {
+		return nil, err
+	}
+	plural := Plural(pluralOf(obj.Name()))
+	if p, isDefined := optionOf[Plural](opts); isDefined {
+		plural = p
+	}
+	served := Served(true)
+	if s, isDefined := optionOf[Served](opts); isDefined {
+		served = s
+	}
+	shortNames, _ := optionOf[ShortNames](opts)
+	categories, _ := optionOf[Categories](opts)
+	storage, _ := optionOf[Storage](opts)
+	kv := &kindVersion{
+		obj:        obj,
+		group:      string(group),
+		kind:       obj.Name(),
+		plural:     string(plural),
+		shortNames: shortNames,
+		categories: categories,
+		scope:      scope,
+		isStorage:  bool(storage),
+		Name:       string(version),
+		Served:     bool(served),
+	}
+	for _, col := range optionsOfType[Column](optionsOf(info, _printerResource)) {
+		column, err := col.column()
+		if err != nil {
+			return nil, err
+		}
+		kv.AdditionalPrinterColumns = append(kv.AdditionalPrinterColumns, column)
+	}
+	return kv, kv.setSubresources(optionsOf(info, _subresourceResource))
+}
+
+func (k *kindVersion) setSubresources(opts []any) error {
+	subs := subresources{}
+	if status, _ := optionOf[Status](opts); status {
+		subs.Status = &struct{}{}
+	}
+	if scale, isDefined := optionOf[Scale](opts); isDefined {
+		sub, err := scale.subresource()
+		if err != nil {
+			return err
+		}
+		subs.Scale = sub
+	}
+	if subs.Status != nil || subs.Scale != nil {
+		k.Subresources = &subs
+	}
+	return nil
+}
+
+type customResourceDefinition struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   objectMeta     `json:"metadata"`
+	Spec       definitionSpec `json:"spec"`
+}
+
+type objectMeta struct {
+	Name string `json:"name"`
+}
+
+type definitionSpec struct {
+	Group    string         `json:"group"`
+	Names    definitionName `json:"names"`
+	Scope    Scope          `json:"scope"`
+	Versions []*kindVersion `json:"versions"`
+}
+
+type definitionName struct {
+	Kind       string   `json:"kind"`
+	ListKind   string   `json:"listKind"`
+	Plural     string   `json:"plural"`
+	Singular   string   `json:"singular"`
+	ShortNames []string `json:"shortNames,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+func newCustomResourceDefinition(version *kindVersion) *customResourceDefinition {
+	return &customResourceDefinition{
+		APIVersion: "apiextensions.k8s.io/v1",
+		Kind:       "CustomResourceDefinition",
+		Metadata: objectMeta{
+			Name: version.plural + "." + version.group,
+		},
+		Spec: definitionSpec{
+			Group: version.group,
+			Names: definitionName{
+				Kind:       version.kind,
+				ListKind:   version.kind + "List",
+				Plural:     version.plural,
+				Singular:   strings.ToLower(version.kind),
+				ShortNames: version.shortNames,
+				Categories: version.categories,
+			},
+			Scope:    version.scope,
+			Versions: []*kindVersion{version},
+		},
+	}
+}
+
+// add adds `version` to the versions of the definition. The names and the
+// scope have to be the same for every version of a kind.
+func (c *customResourceDefinition) add(version *kindVersion) error {
+	first := c.Spec.Versions[0]
+	if slices.ContainsFunc(c.Spec.Versions, func(v *kindVersion) bool { return v.Name == version.Name }) {
+		return fmt.Errorf("version `%s` of kind `%s` is defined more than once: %s and %s", version.Name, version.kind, first.obj.Pkg().Path(), version.obj.Pkg().Path())
+	}
+	if version.plural != first.plural || version.scope != first.scope ||
+		!slices.Equal(version.shortNames, first.shortNames) || !slices.Equal(version.categories, first.categories) {
+		return fmt.Errorf("versions `%s` and `%s` of kind `%s` have different names or scope", first.Name, version.Name, version.kind)
+	}
+	c.Spec.Versions = append(c.Spec.Versions, version)
+	return nil
+}
+
+// isValid checks that exactly one version is the storage version. A single
+// version is always the storage version.
+func (c *customResourceDefinition) isValid() error {
+	versions := c.Spec.Versions
+	if len(versions) == 1 {
+		versions[0].Storage = true
+		return nil
+	}
+	storages := make([]string, 0, 1)
+	for _, version := range versions {
+		version.Storage = version.isStorage
+		if version.isStorage {
+			storages = append(storages, version.Name)
+		}
+	}
+	if len(storages) != 1 {
+		return fmt.Errorf("kind `%s` needs exactly one storage version but has %d: %v", c.Spec.Names.Kind, len(storages), storages)
+	}
+	return nil
+}
+
+// newArtifact returns the CustomResourceDefinition as YAML. The keys are
+// sorted alphabetically like the manifests of the Kubernetes API.
+func newArtifact(crd *customResourceDefinition) (*genv1.Artifact, error) {
+	data, err := json.Marshal(crd)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var manifest any
+	if err := dec.Decode(&manifest); err != nil {
+		return nil, err
+	}
+	// maps are encoded with sorted keys
+	data, err = json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	var ordered any
+	if err := yaml.UnmarshalWithOptions(data, &ordered, yaml.UseOrderedMap()); err != nil {
+		return nil, err
+	}
+	out, err := yaml.MarshalWithOptions(ordered, yaml.Indent(2), yaml.IndentSequence(true))
+	if err != nil {
+		return nil, err
+	}
+	return &genv1.Artifact{
+		Name: fmt.Sprintf("%s_%s.yaml", crd.Spec.Group, crd.Spec.Names.Plural),
+		Data: bytes.NewBuffer(out),
+	}, nil
+}
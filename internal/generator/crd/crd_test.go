@@ -0,0 +1,325 @@
+package crd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+func TestGenerator_CustomResourceDefinition(t *testing.T) {
+	files := map[string]string{
+		"api/v1/types.go": `package v1
+
+// +crd:kind:group="example.com"
+// +crd:kind:shortNames=["db"]
+// +crd:printer:column="Replicas:integer:.spec.replicas"
+// +crd:subresource:status
+// +crd:subresource:scale=".spec.replicas:.status.replicas"
+type Database struct {
+	Spec   DatabaseSpec
+	Status *DatabaseStatus
+}
+
+// +crd:validation:rule="self.minReplicas <= self.replicas"
+type DatabaseSpec struct {
+	// +openapi:schema:minimum=1
+	// +openapi:schema:description="number of instances"
+	Replicas int64
+	// +openapi:schema:exclusiveMinimum=0
+	MinReplicas int64
+	Engine Engine
+	// +crd:validation:listType="map"
+	// +crd:validation:listMapKeys=["name"]
+	Users []User ` + "`json:\"users,omitempty\"`" + `
+	// +crd:validation:intOrString
+	Port Port
+	// +crd:validation:preserveUnknownFields
+	Config map[string]any ` + "`json:\"config,omitempty\"`" + `
+}
+
+type Port struct {
+	Value string
+}
+
+type User struct {
+	Name string
+}
+
+type Engine string
+
+const (
+	EnginePostgres Engine = "postgres"
+	EngineMySQL    Engine = "mysql"
+)
+
+type DatabaseStatus struct {
+	Replicas int64
+}
+`,
+	}
+	want := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: databases.example.com
+spec:
+  group: example.com
+  names:
+    kind: Database
+    listKind: DatabaseList
+    plural: databases
+    shortNames:
+      - db
+    singular: database
+  scope: Namespaced
+  versions:
+    - additionalPrinterColumns:
+        - jsonPath: .spec.replicas
+          name: Replicas
+          type: integer
+      name: v1
+      schema:
+        openAPIV3Schema:
+          properties:
+            apiVersion:
+              type: string
+            kind:
+              type: string
+            metadata:
+              type: object
+            spec:
+              properties:
+                config:
+                  additionalProperties: {}
+                  type: object
+                  x-kubernetes-preserve-unknown-fields: true
+                engine:
+                  enum:
+                    - postgres
+                    - mysql
+                  type: string
+                minReplicas:
+                  exclusiveMinimum: true
+                  minimum: 0
+                  type: integer
+                port:
+                  x-kubernetes-int-or-string: true
+                replicas:
+                  description: number of instances
+                  minimum: 1
+                  type: integer
+                users:
+                  items:
+                    properties:
+                      name:
+                        type: string
+                    required:
+                      - name
+                    type: object
+                  type: array
+                  x-kubernetes-list-map-keys:
+                    - name
+                  x-kubernetes-list-type: map
+              required:
+                - replicas
+                - minReplicas
+                - engine
+                - port
+              type: object
+              x-kubernetes-validations:
+                - rule: self.minReplicas <= self.replicas
+            status:
+              properties:
+                replicas:
+                  type: integer
+              required:
+                - replicas
+              type: object
+          required:
+            - spec
+          type: object
+      served: true
+      storage: true
+      subresources:
+        scale:
+          specReplicasPath: .spec.replicas
+          statusReplicasPath: .status.replicas
+        status: {}
+`
+	artifacts, err := loadertest.Generate(t, New, files, nil, openapi.New)
+	if err != nil {
+		t.Fatalf("err occurred: %v", err)
+	}
+	if len(artifacts) != 1 {
+		t.Fatalf("expected one artifact but got %d", len(artifacts))
+	}
+	if artifacts[0].Name != "example.com_databases.yaml" {
+		t.Fatalf("unexpected artifact: %s", artifacts[0].Name)
+	}
+	got, err := io.ReadAll(artifacts[0].Data)
+	if err != nil {
+		t.Fatalf("err occurred: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("crd is not equal\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerator_Versions(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		isValid  bool
+		versions []string
+	}{
+		{
+			name:     "storage",
+			isValid:  true,
+			versions: []string{"name: v1", "name: v2beta1"},
+			files: map[string]string{
+				"api/v1/types.go": `package v1
+
+// +crd:kind:group="example.com"
+// +crd:kind:storage
+type Backup struct {
+	Path string
+}
+`,
+				"api/v2beta1/types.go": `package v2beta1
+
+// +crd:kind:group="example.com"
+// +crd:kind:served=false
+type Backup struct {
+	Location string
+}
+`,
+			},
+		},
+		{
+			name:    "missing storage",
+			isValid: false,
+			files: map[string]string{
+				"api/v1/types.go": `package v1
+
+// +crd:kind:group="example.com"
+type Backup struct {}
+`,
+				"api/v2/types.go": `package v2
+
+// +crd:kind:group="example.com"
+type Backup struct {}
+`,
+			},
+		},
+		{
+			name:    "different scope",
+			isValid: false,
+			files: map[string]string{
+				"api/v1/types.go": `package v1
+
+// +crd:kind:group="example.com"
+// +crd:kind:storage
+type Backup struct {}
+`,
+				"api/v2/types.go": `package v2
+
+// +crd:kind:group="example.com"
+// +crd:kind:scope="Cluster"
+type Backup struct {}
+`,
+			},
+		},
+		{
+			name:    "invalid version",
+			isValid: false,
+			files: map[string]string{
+				"api/types.go": `package api
+
+// +crd:kind:group="example.com"
+type Backup struct {}
+`,
+			},
+		},
+		{
+			name:    "recursive",
+			isValid: false,
+			files: map[string]string{
+				"api/v1/types.go": `package v1
+
+// +crd:kind:group="example.com"
+type Tree struct {
+	Root Node
+}
+
+type Node struct {
+	Children []Node
+}
+`,
+			},
+		},
+		{
+			name:    "unsupported keyword",
+			isValid: false,
+			files: map[string]string{
+				"api/v1/types.go": `package v1
+
+// +crd:kind:group="example.com"
+// +openapi:schema:if="Kind=card"
+// +openapi:schema:then=["Number"]
+type Payment struct {
+	Kind   string
+	Number string
+}
+`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, tc.files, nil, openapi.New)
+			if !tc.isValid && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !tc.isValid {
+				return
+			}
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			if len(artifacts) != 1 {
+				t.Fatalf("expected one artifact but got %d", len(artifacts))
+			}
+			data, err := io.ReadAll(artifacts[0].Data)
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			manifest := string(data)
+			for _, version := range tc.versions {
+				if !strings.Contains(manifest, version) {
+					t.Fatalf("version is missing: %s\n%s", version, manifest)
+				}
+			}
+			if strings.Count(manifest, "storage: true") != 1 {
+				t.Fatalf("expected exactly one storage version\n%s", manifest)
+			}
+		})
+	}
+}
+
+func TestPluralOf(t *testing.T) {
+	tests := map[string]string{
+		"Database": "databases",
+		"Policy":   "policies",
+		"Gateway":  "gateways",
+		"Ingress":  "ingresses",
+		"Box":      "boxes",
+		"Patch":    "patches",
+	}
+	for kind, want := range tests {
+		if got := pluralOf(kind); got != want {
+			t.Errorf("plural of %s: got %s want %s", kind, got, want)
+		}
+	}
+}
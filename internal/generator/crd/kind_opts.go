@@ -0,0 +1,137 @@
+package crd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+)
+
+const _kindResource = "kind"
+
+// _versionRegExp matches the versions of the Kubernetes API e.g. v1, v1beta2
+// or v2alpha1.
+var _versionRegExp = regexp.MustCompile(`^v[1-9][0-9]*((alpha|beta)[1-9][0-9]*)?$`)
+
+// _groupRegExp matches a DNS subdomain e.g. example.com.
+var _groupRegExp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+type kindResourcer struct{}
+
+func (k kindResourcer) Options() []*optionv1.Option {
+	return []*optionv1.Option{
+		mustMakeOpt(_kindResource, Group(""), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_kindResource, Version(""), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_kindResource, Plural(""), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_kindResource, ShortNames(nil), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_kindResource, Categories(nil), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_kindResource, Scope(""), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_kindResource, Storage(false), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_kindResource, Served(false), _unique, optionv1.TargetStruct),
+	}
+}
+
+type Group string
+
+func (g Group) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "API group of the custom resource e.g. example.com. Marks the struct as the kind of a CustomResourceDefinition",
+	}
+}
+
+func (g Group) validate() error {
+	if !_groupRegExp.MatchString(string(g)) {
+		return fmt.Errorf("group has to be a lowercase DNS subdomain: %s", g)
+	}
+	return nil
+}
+
+type Version string
+
+func (v Version) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Version of the API e.g. v1 or v1beta1. Defaults to the name of the package",
+	}
+}
+
+func (v Version) validate() error {
+	if !_versionRegExp.MatchString(string(v)) {
+		return fmt.Errorf("version has to be a Kubernetes API version e.g. v1 or v1beta1: %s", v)
+	}
+	return nil
+}
+
+type Plural string
+
+func (p Plural) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Plural name of the resource used in the URL e.g. /apis/<group>/<version>/<plural>. Defaults to the lowercase kind with a plural suffix",
+	}
+}
+
+type ShortNames []string
+
+func (s ShortNames) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Short names of the resource e.g. kubectl get <short name>",
+	}
+}
+
+type Categories []string
+
+func (c Categories) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Categories of the resource e.g. all which can be used by kubectl get <category>",
+	}
+}
+
+type Scope string
+
+const (
+	NamespacedScope Scope = "Namespaced"
+	ClusterScope    Scope = "Cluster"
+)
+
+func (s Scope) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Scope of the resource. Valid options are Namespaced and Cluster. Defaults to Namespaced",
+	}
+}
+
+func (s Scope) validate() error {
+	if s != NamespacedScope && s != ClusterScope {
+		return fmt.Errorf("scope has to be %s or %s: %s", NamespacedScope, ClusterScope, s)
+	}
+	return nil
+}
+
+type Storage bool
+
+func (s Storage) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Marks the version as the storage version. Exactly one version of a kind has to be the storage version if the kind has more than one version. A single version is always the storage version",
+	}
+}
+
+type Served bool
+
+func (s Served) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Whether the version is served by the API server. Defaults to true",
+	}
+}
+
+// pluralOf returns the default plural name of `kind`.
+func pluralOf(kind string) string {
+	name := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
@@ -0,0 +1,58 @@
+package crd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+)
+
+const _printerResource = "printer"
+
+// _columnTypes are the types of an additional printer column.
+var _columnTypes = []string{"integer", "number", "string", "boolean", "date"}
+
+type printerResourcer struct{}
+
+func (p printerResourcer) Options() []*optionv1.Option {
+	return []*optionv1.Option{
+		mustMakeOpt(_printerResource, Column(""), _repetable, optionv1.TargetStruct),
+	}
+}
+
+// printerColumn is an additional printer column of a version.
+type printerColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	JSONPath string `json:"jsonPath"`
+}
+
+// Column is an additional printer column in the format
+// `<name>:<type>:<jsonPath>` e.g. `Replicas:integer:.spec.replicas`.
+type Column string
+
+func (c Column) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Additional column shown by kubectl get in the format <name>:<type>:<jsonPath> e.g. Replicas:integer:.spec.replicas. The type is one of integer, number, string, boolean and date. Can be used more than once",
+	}
+}
+
+func (c Column) column() (printerColumn, error) {
+	parts := strings.SplitN(string(c), ":", 3)
+	if len(parts) != 3 {
+		return printerColumn{}, fmt.Errorf("column has to be in the format <name>:<type>:<jsonPath>: %s", c)
+	}
+	col := printerColumn{Name: parts[0], Type: parts[1], JSONPath: parts[2]}
+	if col.Name == "" {
+		return printerColumn{}, fmt.Errorf("name of column cannot be empty: %s", c)
+	}
+	if !slices.Contains(_columnTypes, col.Type) {
+		return printerColumn{}, fmt.Errorf("type of column `%s` has to be one of %v: %s", col.Name, _columnTypes, col.Type)
+	}
+	if !strings.HasPrefix(col.JSONPath, ".") {
+		return printerColumn{}, fmt.Errorf("json path of column `%s` has to start with a dot: %s", col.Name, col.JSONPath)
+	}
+	return col, nil
+}
@@ -0,0 +1,227 @@
+package crd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/naivary/codemark/internal/generator/openapi"
+)
+
+// _kubernetesExtensionPrefix is the prefix of the vendor extensions which
+// are part of the schema of a CustomResourceDefinition.
+const _kubernetesExtensionPrefix = "x-kubernetes-"
+
+// _unsupportedKeywords are keywords of JSON Schema 2020-12 which cannot be
+// expressed by the schema of a CustomResourceDefinition.
+var _unsupportedKeywords = []string{
+	"$ref",
+	"if",
+	"then",
+	"else",
+	"dependentRequired",
+	"patternProperties",
+	"discriminator",
+}
+
+// _droppedKeywords are keywords which are valid but have no meaning for a
+// CustomResourceDefinition.
+var _droppedKeywords = []string{
+	"$id",
+	"$schema",
+	"$defs",
+	"readOnly",
+	"writeOnly",
+	"deprecated",
+	"contentMediaType",
+}
+
+// structuralOf converts `schema` to the OpenAPI v3 schema of a
+// CustomResourceDefinition. The schema has to be self-contained.
+func structuralOf(schema *openapi.Schema) (map[string]any, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var props map[string]any
+	if err := dec.Decode(&props); err != nil {
+		return nil, err
+	}
+	if err := toStructural(props, ""); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// toStructural converts the schema `props` in place. `path` is the path of
+// the schema used in errors.
+func toStructural(props map[string]any, path string) error {
+	for _, keyword := range _unsupportedKeywords {
+		if _, isDefined := props[keyword]; isDefined {
+			return fmt.Errorf("keyword `%s` at `%s` is not supported by CustomResourceDefinitions", keyword, pathOf(path))
+		}
+	}
+	for key := range props {
+		if slices.Contains(_droppedKeywords, key) {
+			delete(props, key)
+		}
+		if strings.HasPrefix(key, "x-") && !strings.HasPrefix(key, _kubernetesExtensionPrefix) {
+			delete(props, key)
+		}
+	}
+	if err := toStructuralChildren(props, path); err != nil {
+		return err
+	}
+	toNullable(props)
+	if value, isDefined := props["const"]; isDefined {
+		delete(props, "const")
+		props["enum"] = []any{value}
+	}
+	if examples, isList := props["examples"].([]any); isList {
+		delete(props, "examples")
+		if len(examples) > 0 {
+			props["example"] = examples[0]
+		}
+	}
+	for _, keyword := range []string{"Minimum", "Maximum"} {
+		exclusive := "exclusive" + keyword
+		if value, isDefined := props[exclusive]; isDefined {
+			props[strings.ToLower(keyword)] = value
+			props[exclusive] = true
+		}
+	}
+	if props["contentEncoding"] == "base64" {
+		props["format"] = "byte"
+	}
+	delete(props, "contentEncoding")
+	if isFalse, isBool := props["additionalProperties"].(bool); isBool && !isFalse {
+		delete(props, "additionalProperties")
+	}
+	return flattenAllOf(props, path)
+}
+
+func toStructuralChildren(props map[string]any, path string) error {
+	if properties, isMap := props["properties"].(map[string]any); isMap {
+		for name, prop := range properties {
+			if err := toStructural(prop.(map[string]any), path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"items", "additionalProperties", "not"} {
+		if child, isMap := props[keyword].(map[string]any); isMap {
+			if err := toStructural(child, path+"."+keyword); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		children, _ := props[keyword].([]any)
+		for i, child := range children {
+			if err := toStructural(child.(map[string]any), fmt.Sprintf("%s.%s[%d]", path, keyword, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// toNullable replaces the ways JSON Schema allows null by `nullable`.
+func toNullable(props map[string]any) {
+	if list, isList := props["type"].([]any); isList {
+		types := slices.DeleteFunc(list, func(typ any) bool { return typ == "null" })
+		if len(types) < len(list) {
+			props["nullable"] = true
+		}
+		if len(types) == 1 {
+			props["type"] = types[0]
+		}
+	}
+	if enum, isList := props["enum"].([]any); isList && slices.Contains(enum, nil) {
+		props["enum"] = slices.DeleteFunc(enum, func(value any) bool { return value == nil })
+		props["nullable"] = true
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		branches, _ := props[keyword].([]any)
+		if len(branches) != 2 {
+			continue
+		}
+		idx := slices.IndexFunc(branches, func(branch any) bool {
+			return reflect.DeepEqual(branch, map[string]any{"type": "null"})
+		})
+		if idx == -1 {
+			continue
+		}
+		delete(props, keyword)
+		for key, value := range branches[1-idx].(map[string]any) {
+			if _, isDefined := props[key]; !isDefined {
+				props[key] = value
+			}
+		}
+		props["nullable"] = true
+	}
+}
+
+// flattenAllOf merges the schemas of `allOf` into `props` because the schema
+// of a CustomResourceDefinition has to define the type of every value
+// outside of `allOf`.
+func flattenAllOf(props map[string]any, path string) error {
+	branches, _ := props["allOf"].([]any)
+	delete(props, "allOf")
+	for _, branch := range branches {
+		for key, value := range branch.(map[string]any) {
+			current, isDefined := props[key]
+			switch {
+			case !isDefined:
+				props[key] = value
+			case key == "required":
+				props[key] = mergeRequired(current, value)
+			case key == "properties":
+				if err := mergeProperties(current.(map[string]any), value.(map[string]any), path); err != nil {
+					return err
+				}
+			case !reflect.DeepEqual(current, value):
+				return fmt.Errorf("keyword `%s` at `%s` is defined with different values and cannot be merged", key, pathOf(path))
+			}
+		}
+	}
+	return nil
+}
+
+func mergeRequired(a, b any) []any {
+	required := slices.Clone(a.([]any))
+	for _, name := range b.([]any) {
+		if !slices.Contains(required, name) {
+			required = append(required, name)
+		}
+	}
+	return required
+}
+
+func mergeProperties(to, from map[string]any, path string) error {
+	for name, prop := range from {
+		current, isDefined := to[name]
+		if !isDefined {
+			to[name] = prop
+			continue
+		}
+		merged := map[string]any{"allOf": []any{current, prop}}
+		if err := flattenAllOf(merged, path+"."+name); err != nil {
+			return err
+		}
+		to[name] = merged
+	}
+	return nil
+}
+
+func pathOf(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
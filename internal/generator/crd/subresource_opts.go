@@ -0,0 +1,66 @@
+package crd
+
+import (
+	"fmt"
+	"strings"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+)
+
+const _subresourceResource = "subresource"
+
+type subresourceResourcer struct{}
+
+func (s subresourceResourcer) Options() []*optionv1.Option {
+	return []*optionv1.Option{
+		mustMakeOpt(_subresourceResource, Status(false), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_subresourceResource, Scale(""), _unique, optionv1.TargetStruct),
+	}
+}
+
+type subresources struct {
+	Status *struct{}         `json:"status,omitempty"`
+	Scale  *scaleSubresource `json:"scale,omitempty"`
+}
+
+type scaleSubresource struct {
+	SpecReplicasPath   string `json:"specReplicasPath"`
+	StatusReplicasPath string `json:"statusReplicasPath"`
+	LabelSelectorPath  string `json:"labelSelectorPath,omitempty"`
+}
+
+type Status bool
+
+func (s Status) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Enables the status subresource. The status is updated using /status and ignored by updates of the main resource",
+	}
+}
+
+// Scale enables the scale subresource in the format
+// `<specReplicasPath>:<statusReplicasPath>[:<labelSelectorPath>]`.
+type Scale string
+
+func (s Scale) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Enables the scale subresource in the format <specReplicasPath>:<statusReplicasPath>[:<labelSelectorPath>] e.g. .spec.replicas:.status.replicas",
+	}
+}
+
+func (s Scale) subresource() (*scaleSubresource, error) {
+	parts := strings.Split(string(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("scale has to be in the format <specReplicasPath>:<statusReplicasPath>[:<labelSelectorPath>]: %s", s)
+	}
+	for _, path := range parts {
+		if !strings.HasPrefix(path, ".") {
+			return nil, fmt.Errorf("path of scale has to start with a dot: %s", path)
+		}
+	}
+	scale := &scaleSubresource{SpecReplicasPath: parts[0], StatusReplicasPath: parts[1]}
+	if len(parts) == 3 {
+		scale.LabelSelectorPath = parts[2]
+	}
+	return scale, nil
+}
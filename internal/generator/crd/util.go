@@ -0,0 +1,80 @@
+package crd
+
+import (
+	"fmt"
+	"reflect"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/optionutil"
+	"github.com/naivary/codemark/registry"
+)
+
+const (
+	_unique    = true
+	_repetable = false
+)
+
+// optioner is a resource which defines options.
+type optioner interface {
+	Options() []*optionv1.Option
+}
+
+func newRegistry(resources ...optioner) (regv1.Registry, error) {
+	reg := registry.InMemory()
+	for _, resource := range resources {
+		for _, opt := range resource.Options() {
+			if err := reg.Define(opt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return reg, nil
+}
+
+func mustMakeOpt(resource string, output any, isUnique bool, targets ...optionv1.Target) *optionv1.Option {
+	rtype := reflect.TypeOf(output)
+	doc := output.(openapi.Docer[docv1.Option]).Doc()
+	name := openapi.CamelCase.Format(rtype.Name())
+	ident := fmt.Sprintf("%s:%s:%s", _domain, resource, name)
+	opt := optionutil.MustMake(ident, rtype, &doc, isUnique, targets...)
+	return &opt
+}
+
+// optionsOf returns the options of `info` which belong to `resource` of the
+// crd domain.
+func optionsOf(info infov1.Info, resource string) []any {
+	opts := make([]any, 0)
+	for ident, values := range info.Options() {
+		if optionutil.DomainOf(ident) != _domain || optionutil.ResourceOf(ident) != resource {
+			continue
+		}
+		opts = append(opts, values...)
+	}
+	return opts
+}
+
+// optionOf returns the first option of type `T` in `opts`.
+func optionOf[T any](opts []any) (T, bool) {
+	for _, opt := range opts {
+		if o, isT := opt.(T); isT {
+			return o, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// optionsOfType returns all the options of type `T` in `opts`.
+func optionsOfType[T any](opts []any) []T {
+	res := make([]T, 0)
+	for _, opt := range opts {
+		if o, isT := opt.(T); isT {
+			res = append(res, o)
+		}
+	}
+	return res
+}
@@ -0,0 +1,216 @@
+package crd
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"slices"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+	"github.com/naivary/codemark/internal/generator/openapi"
+)
+
+const _validationResource = "validation"
+
+type validationResourcer struct{}
+
+func (v validationResourcer) Options() []*optionv1.Option {
+	return []*optionv1.Option{
+		mustMakeOpt(_validationResource, ListType(""), _unique, optionv1.TargetField),
+		mustMakeOpt(_validationResource, ListMapKeys(nil), _unique, optionv1.TargetField),
+		mustMakeOpt(_validationResource, MapType(""), _unique, optionv1.TargetStruct, optionv1.TargetField),
+		mustMakeOpt(_validationResource, PreserveUnknownFields(false), _unique, optionv1.TargetStruct, optionv1.TargetField),
+		mustMakeOpt(_validationResource, EmbeddedResource(false), _unique, optionv1.TargetField),
+		mustMakeOpt(_validationResource, IntOrString(false), _unique, optionv1.TargetField),
+		mustMakeOpt(_validationResource, Rule(""), _repetable, optionv1.TargetStruct, optionv1.TargetField),
+	}
+}
+
+// hooks returns the hooks applying the validation options to the schemas
+// built from the openapi markers.
+func (v validationResourcer) hooks() openapi.Hooks {
+	return openapi.Hooks{
+		Struct: func(_ types.Object, info *infov1.StructInfo, schema *openapi.Schema) error {
+			return v.apply(schema, optionsOf(info, _validationResource))
+		},
+		Field: func(_ types.Object, info *infov1.FieldInfo, schema *openapi.Schema) error {
+			return v.apply(schema, optionsOf(info, _validationResource))
+		},
+	}
+}
+
+func (v validationResourcer) apply(schema *openapi.Schema, opts []any) error {
+	listType, isListType := optionOf[ListType](opts)
+	if keys, isDefined := optionOf[ListMapKeys](opts); isDefined && listType != MapList {
+		return fmt.Errorf("listMapKeys can only be used with listType=%s: %v", MapList, keys)
+	}
+	if isListType && listType == MapList {
+		if _, isDefined := optionOf[ListMapKeys](opts); !isDefined {
+			return fmt.Errorf("listType=%s requires listMapKeys", MapList)
+		}
+	}
+	for _, opt := range opts {
+		var err error
+		switch o := opt.(type) {
+		case ListType:
+			err = o.apply(schema)
+		case ListMapKeys:
+			err = o.apply(schema)
+		case MapType:
+			err = o.apply(schema)
+		case PreserveUnknownFields:
+			o.apply(schema)
+		case EmbeddedResource:
+			o.apply(schema)
+		case IntOrString:
+			o.apply(schema)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	rules := optionsOfType[Rule](opts)
+	if len(rules) == 0 {
+		return nil
+	}
+	validations := make([]validationRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule == "" {
+			return errors.New("rule cannot be empty")
+		}
+		validations = append(validations, validationRule{Rule: string(rule)})
+	}
+	setExtension(schema, "x-kubernetes-validations", validations)
+	return nil
+}
+
+func setExtension(schema *openapi.Schema, key string, value any) {
+	if schema.Extensions == nil {
+		schema.Extensions = make(map[string]any)
+	}
+	schema.Extensions[key] = value
+}
+
+// validationRule is a CEL validation rule of a schema.
+type validationRule struct {
+	Rule string `json:"rule"`
+}
+
+type ListType string
+
+const (
+	AtomicList ListType = "atomic"
+	SetList    ListType = "set"
+	MapList    ListType = "map"
+)
+
+func (l ListType) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Topology of the list used by server-side apply. Valid options are atomic, set and map. map requires listMapKeys",
+	}
+}
+
+func (l ListType) apply(schema *openapi.Schema) error {
+	if !slices.Contains([]ListType{AtomicList, SetList, MapList}, l) {
+		return fmt.Errorf("listType has to be one of %s, %s or %s: %s", AtomicList, SetList, MapList, l)
+	}
+	setExtension(schema, "x-kubernetes-list-type", string(l))
+	return nil
+}
+
+type ListMapKeys []string
+
+func (l ListMapKeys) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Properties of the items identifying an item of a list with listType=map",
+	}
+}
+
+func (l ListMapKeys) apply(schema *openapi.Schema) error {
+	if len(l) == 0 {
+		return errors.New("listMapKeys cannot be empty")
+	}
+	setExtension(schema, "x-kubernetes-list-map-keys", []string(l))
+	return nil
+}
+
+type MapType string
+
+const (
+	AtomicMap   MapType = "atomic"
+	GranularMap MapType = "granular"
+)
+
+func (m MapType) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Topology of the map or struct used by server-side apply. Valid options are atomic and granular",
+	}
+}
+
+func (m MapType) apply(schema *openapi.Schema) error {
+	if m != AtomicMap && m != GranularMap {
+		return fmt.Errorf("mapType has to be %s or %s: %s", AtomicMap, GranularMap, m)
+	}
+	setExtension(schema, "x-kubernetes-map-type", string(m))
+	return nil
+}
+
+type PreserveUnknownFields bool
+
+func (p PreserveUnknownFields) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Unknown fields of the object are not pruned by the API server",
+	}
+}
+
+func (p PreserveUnknownFields) apply(schema *openapi.Schema) {
+	if p {
+		setExtension(schema, "x-kubernetes-preserve-unknown-fields", true)
+	}
+}
+
+type EmbeddedResource bool
+
+func (e EmbeddedResource) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "The object is a Kubernetes resource with apiVersion, kind and metadata",
+	}
+}
+
+func (e EmbeddedResource) apply(schema *openapi.Schema) {
+	if e {
+		setExtension(schema, "x-kubernetes-embedded-resource", true)
+	}
+}
+
+type IntOrString bool
+
+func (i IntOrString) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "The value is either an integer or a string e.g. a port or percentage. The schema of the go type is replaced",
+	}
+}
+
+func (i IntOrString) apply(schema *openapi.Schema) {
+	if !i {
+		return
+	}
+	*schema = openapi.Schema{
+		Title:      schema.Title,
+		Desc:       schema.Desc,
+		Default:    schema.Default,
+		Extensions: schema.Extensions,
+	}
+	setExtension(schema, "x-kubernetes-int-or-string", true)
+}
+
+// Rule is a CEL validation rule e.g. `self.minReplicas <= self.maxReplicas`.
+type Rule string
+
+func (r Rule) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "CEL expression validating the value e.g. self.minReplicas <= self.maxReplicas. Can be used more than once",
+	}
+}
@@ -0,0 +1,33 @@
+package docs
+
+import (
+	"embed"
+
+	"github.com/goccy/go-yaml"
+)
+
+// _configSchemas contains config.json validating the options of `config` e.g.
+// that packagePage is a filename without a directory.
+//
+//go:embed schemas/*.json
+var _configSchemas embed.FS
+
+func newConfig(cfg map[string]any) (*config, error) {
+	c := config{
+		PackagePage: "index.md",
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &c)
+	return &c, err
+}
+
+// +openapi:schema:description="config options for the docs generator"
+type config struct {
+	InferRequired bool `yaml:"inferRequired"`
+	// +openapi:schema:pattern="^[^/]+$"
+	PackagePage string `yaml:"packagePage"`
+	Template    string `yaml:"template"`
+}
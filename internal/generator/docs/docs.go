@@ -0,0 +1,138 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"io/fs"
+	"strings"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	genv1 "github.com/naivary/codemark/api/generator/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	configer "github.com/naivary/codemark/internal/config"
+	"github.com/naivary/codemark/internal/source"
+)
+
+const _domain = "docs"
+
+var (
+	_ genv1.Generator     = (*docsGenerator)(nil)
+	_ genv1.ConfigSchemer = (*docsGenerator)(nil)
+)
+
+func New() (genv1.Generator, error) {
+	reg, err := newRegistry()
+	if err != nil {
+		return nil, err
+	}
+	configSchemas, err := configer.Schemas(_configSchemas)
+	if err != nil {
+		return nil, err
+	}
+	return &docsGenerator{reg: reg, configSchemas: configSchemas}, nil
+}
+
+type docsGenerator struct {
+	reg regv1.Registry
+
+	// configSchemas are the JSON Schemas of the config.
+	configSchemas fs.FS
+}
+
+func (g *docsGenerator) Domain() docv1.Domain {
+	return docv1.Domain{
+		Name: _domain,
+		Desc: "Generate Markdown reference documentation of structs",
+	}
+}
+
+func (g *docsGenerator) ConfigSchema() (fs.FS, string) {
+	return g.configSchemas, configer.SchemaRoot
+}
+
+func (g *docsGenerator) Resources() map[string]*docv1.Resource {
+	return map[string]*docv1.Resource{
+		_structResource: {Desc: "Render a struct on its own page or exclude it from the documentation"},
+		_fieldResource:  {Desc: "Exclude a field from the documentation"},
+	}
+}
+
+func (g *docsGenerator) Registry() regv1.Registry {
+	return g.reg
+}
+
+func (g *docsGenerator) ConfigDoc() map[string]docv1.Config {
+	return map[string]docv1.Config{
+		"inferRequired": {
+			Default:     false,
+			Description: `Infers which fields are required like schema.inferRequired of the openapi generator and has the same default. Set both to the same value so the documentation agrees with the schemas. Every field which is not a pointer and not tagged with omitempty or omitzero in its json struct tag is required. The required marker of a field takes precedence.`,
+		},
+		"packagePage": {
+			Default:     "index.md",
+			Description: `Filename of the page of a package which is written to the directory of the package. A struct page with the same name is an error.`,
+		},
+		"template": {
+			Default:     "",
+			Description: `Path of a text/template file overriding the layout of the pages. The template is executed with a Document for every package and every struct marked with +docs:struct:page. The functions "join" (strings.Join) and "cell" (escapes a markdown table cell) are available. If empty the default layout is used.`,
+		},
+	}
+}
+
+func (g *docsGenerator) Generate(proj infov1.Project, config map[string]any) ([]*genv1.Artifact, error) {
+	cfg, err := newConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := newTemplate(cfg.Template)
+	if err != nil {
+		return nil, fmt.Errorf("template is invalid: %w", err)
+	}
+	b := &builder{
+		cfg:       cfg,
+		locations: make(map[*types.TypeName]location),
+		structs:   make(map[*types.TypeName]*infov1.StructInfo),
+		expanding: make(map[*types.TypeName]bool),
+	}
+	for _, info := range proj {
+		for obj, sinfo := range info.Structs {
+			b.structs[obj.(*types.TypeName)] = sinfo
+		}
+	}
+	type pkgPages struct {
+		info  *infov1.Information
+		pages []*Document
+	}
+	all := make([]pkgPages, 0, len(proj))
+	for _, pkg := range infov1.Packages(proj) {
+		dir, err := source.PackageDirOf(pkg)
+		if err != nil {
+			return nil, err
+		}
+		pages, err := b.pagesOf(pkg, proj[pkg], dir)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, pkgPages{info: proj[pkg], pages: pages})
+	}
+	artifacts := make([]*genv1.Artifact, 0)
+	for _, p := range all {
+		for _, page := range p.pages {
+			b.build(page, p.info)
+			if len(page.Structs) == 0 && len(page.Pages) == 0 {
+				continue
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, page); err != nil {
+				return nil, fmt.Errorf("page `%s` cannot be rendered: %w", page.name, err)
+			}
+			data := strings.TrimRight(buf.String(), "\n") + "\n"
+			artifacts = append(artifacts, &genv1.Artifact{
+				Name: page.name,
+				Data: bytes.NewBufferString(data),
+			})
+		}
+	}
+	return artifacts, nil
+}
@@ -0,0 +1,298 @@
+package docs
+
+import (
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+const _configFiles = `// Package config contains the configuration of the server.
+package config
+
+import "loadertest/storage"
+
+// Config is the root configuration.
+type Config struct {
+	// Name of the server.
+	// +openapi:schema:minLength=3
+	// +openapi:schema:pattern="^[a-z|-]+$"
+	Name string ` + "`json:\"name\"`" + `
+	// Port to listen on.
+	// +openapi:schema:minimum=1
+	// +openapi:schema:maximum=65535
+	// +openapi:schema:default="8080"
+	Port int ` + "`json:\"port,omitempty\"`" + `
+	// +openapi:schema:enum=["debug", "info"]
+	// +openapi:schema:description="Level of the logs"
+	Level *string ` + "`json:\"level\"`" + `
+	TLS *TLS ` + "`json:\"tls\"`" + `
+	Backends []storage.Backend ` + "`json:\"backends\"`" + `
+	// +openapi:schema:deprecated
+	Legacy bool ` + "`json:\"legacy\"`" + `
+	// +docs:field:exclude
+	Internal string
+	Secret string ` + "`json:\"-\"`" + `
+}
+
+// TLS configures the certificates.
+type TLS struct {
+	// +openapi:schema:required
+	Cert string ` + "`json:\"cert,omitempty\"`" + `
+}
+
+// +docs:struct:exclude
+type Hidden struct {
+	Name string
+}
+`
+
+const _storageFiles = `package storage
+
+// Backend is a storage backend.
+//
+// +docs:struct:page
+type Backend struct {
+	// Multi line
+	// description.
+	URL string ` + "`json:\"url\"`" + `
+}
+`
+
+func TestGenerator_Files(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		cfg     map[string]any
+		isValid bool
+		want    map[string]string
+	}{
+		{
+			name:    "default template",
+			isValid: true,
+			cfg:     map[string]any{"inferRequired": true},
+			files: map[string]string{
+				"config/config.go":   _configFiles,
+				"storage/storage.go": _storageFiles,
+			},
+			want: map[string]string{
+				"config/index.md": `<!-- Code generated by codemark. DO NOT EDIT. -->
+
+# config
+
+Package config contains the configuration of the server.
+
+## Config
+
+Config is the root configuration.
+
+| Field | Type | Default | Required | Constraints | Description |
+| ----- | ---- | ------- | -------- | ----------- | ----------- |
+| ` + "`name`" + ` | ` + "`string`" + ` |  | yes | minLength: 3<br>pattern: ` + "`^[a-z\\|-]+$`" + ` | Name of the server. |
+| ` + "`port`" + ` | ` + "`int`" + ` | ` + "`8080`" + ` | no | minimum: 1<br>maximum: 65535 | Port to listen on. |
+| ` + "`level`" + ` | ` + "`*string`" + ` |  | no | enum: ` + "`debug`, `info`" + ` | Level of the logs |
+| ` + "`tls`" + ` | [` + "`*TLS`" + `](#tls) |  | no |  |  |
+| ` + "`backends`" + ` | [` + "`[]storage.Backend`" + `](../storage/Backend.md) |  | yes |  |  |
+| ` + "`legacy`" + ` | ` + "`bool`" + ` |  | yes |  | **Deprecated.** |
+
+## TLS
+
+TLS configures the certificates.
+
+| Field | Type | Default | Required | Constraints | Description |
+| ----- | ---- | ------- | -------- | ----------- | ----------- |
+| ` + "`cert`" + ` | ` + "`string`" + ` |  | yes |  |  |
+`,
+				"storage/index.md": `<!-- Code generated by codemark. DO NOT EDIT. -->
+
+# storage
+
+## Types
+
+- [Backend](Backend.md)
+`,
+				"storage/Backend.md": `<!-- Code generated by codemark. DO NOT EDIT. -->
+
+# Backend
+
+Backend is a storage backend.
+
+| Field | Type | Default | Required | Constraints | Description |
+| ----- | ---- | ------- | -------- | ----------- | ----------- |
+| ` + "`url`" + ` | ` + "`string`" + ` |  | yes |  | Multi line<br>description. |
+`,
+			},
+		},
+		{
+			name:    "embedded structs",
+			isValid: true,
+			cfg:     map[string]any{"inferRequired": true},
+			files: map[string]string{
+				"api/user.go": `package api
+
+type Meta struct {
+	// Name of the object.
+	// +openapi:schema:minLength=1
+	Name string ` + "`json:\"name\"`" + `
+	*Base
+}
+
+type Base struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+type Spec struct {
+	Replicas int ` + "`json:\"replicas\"`" + `
+}
+
+// User is a user.
+type User struct {
+	Meta
+	Spec ` + "`json:\"spec\"`" + `
+	// Email of the user.
+	Email string ` + "`json:\"email\"`" + `
+}
+`,
+			},
+			want: map[string]string{
+				"api/index.md": `<!-- Code generated by codemark. DO NOT EDIT. -->
+
+# api
+
+## Meta
+
+| Field | Type | Default | Required | Constraints | Description |
+| ----- | ---- | ------- | -------- | ----------- | ----------- |
+| ` + "`name`" + ` | ` + "`string`" + ` |  | yes | minLength: 1 | Name of the object. |
+| ` + "`id`" + ` | ` + "`string`" + ` |  | no |  |  |
+
+## Base
+
+| Field | Type | Default | Required | Constraints | Description |
+| ----- | ---- | ------- | -------- | ----------- | ----------- |
+| ` + "`id`" + ` | ` + "`string`" + ` |  | yes |  |  |
+
+## Spec
+
+| Field | Type | Default | Required | Constraints | Description |
+| ----- | ---- | ------- | -------- | ----------- | ----------- |
+| ` + "`replicas`" + ` | ` + "`int`" + ` |  | yes |  |  |
+
+## User
+
+User is a user.
+
+| Field | Type | Default | Required | Constraints | Description |
+| ----- | ---- | ------- | -------- | ----------- | ----------- |
+| ` + "`name`" + ` | ` + "`string`" + ` |  | yes | minLength: 1 | Name of the object. |
+| ` + "`id`" + ` | ` + "`string`" + ` |  | no |  |  |
+| ` + "`spec`" + ` | [` + "`Spec`" + `](#spec) |  | yes |  |  |
+| ` + "`email`" + ` | ` + "`string`" + ` |  | yes |  | Email of the user. |
+`,
+			},
+		},
+		{
+			name:    "custom template",
+			isValid: true,
+			files: map[string]string{
+				"storage/storage.go": _storageFiles,
+			},
+			cfg: map[string]any{
+				"inferRequired": false,
+				"packagePage":   "overview.md",
+				"template": writeTemplate(t, `{{ .Kind }} {{ .Title }}
+{{- range .Structs }}{{ range .Fields }}
+{{ .Name }} {{ .Required }}
+{{- end }}{{ end }}`),
+			},
+			want: map[string]string{
+				"storage/overview.md": "package storage\n",
+				"storage/Backend.md":  "struct Backend\nurl false\n",
+			},
+		},
+		{
+			name:    "package page named like a struct page",
+			isValid: false,
+			files: map[string]string{
+				"storage/storage.go": _storageFiles,
+			},
+			cfg: map[string]any{
+				"packagePage": "backend.md",
+			},
+		},
+		{
+			name:    "package page in another directory",
+			isValid: false,
+			files: map[string]string{
+				"storage/storage.go": _storageFiles,
+			},
+			cfg: map[string]any{
+				"packagePage": "../README.md",
+			},
+		},
+		{
+			name:    "invalid template",
+			isValid: false,
+			files: map[string]string{
+				"storage/storage.go": _storageFiles,
+			},
+			cfg: map[string]any{
+				"template": writeTemplate(t, "{{ .Title "),
+			},
+		},
+		{
+			name:    "missing template",
+			isValid: false,
+			files: map[string]string{
+				"storage/storage.go": _storageFiles,
+			},
+			cfg: map[string]any{
+				"template": filepath.Join(t.TempDir(), "missing.tmpl"),
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, tc.files, tc.cfg, openapi.New)
+			if !tc.isValid && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !tc.isValid {
+				return
+			}
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			got := make(map[string]string, len(artifacts))
+			for _, artifact := range artifacts {
+				data, err := io.ReadAll(artifact.Data)
+				if err != nil {
+					t.Fatalf("err occurred: %v", err)
+				}
+				got[artifact.Name] = string(data)
+			}
+			if !slices.Equal(slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(tc.want))) {
+				t.Fatalf("files are not equal. got: %v; want: %v", slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(tc.want)))
+			}
+			for name, want := range tc.want {
+				if got[name] != want {
+					t.Errorf("file %s is not equal\ngot:\n%s\nwant:\n%s", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+// writeTemplate writes `text` into a temporary file and returns its path.
+func writeTemplate(t *testing.T, text string) string {
+	path := filepath.Join(t.TempDir(), "page.md.tmpl")
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		t.Fatalf("err occurred: %v", err)
+	}
+	return path
+}
@@ -0,0 +1,425 @@
+package docs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/internal/source"
+)
+
+const (
+	_packageKind = "package"
+	_structKind  = "struct"
+)
+
+// Document is the data a template is executed with. A document is a page
+// of a package or a single struct marked with +docs:struct:page.
+type Document struct {
+	// Kind of the page which is either "package" or "struct".
+	Kind string
+	// Title of the page which is the name of the package or struct.
+	Title string
+	// Doc is the doc comment of the package or struct without markers.
+	Doc string
+	// Pages are the links to the struct pages of a package page.
+	Pages []Link
+	// Structs documented on the page. A struct page contains exactly one
+	// struct.
+	Structs []*Struct
+
+	// name of the artifact
+	name string
+}
+
+// Link is a link to another page.
+type Link struct {
+	Name string
+	URL  string
+}
+
+// Struct is a documented struct.
+type Struct struct {
+	Name string
+	// Anchor of the struct on a package page.
+	Anchor     string
+	Doc        string
+	Deprecated bool
+	Fields     []*Field
+}
+
+// Field is a documented field of a struct.
+type Field struct {
+	// Name of the field in its json encoding.
+	Name string
+	// Type of the field as markdown code which is linked to the page of the
+	// referenced struct if it is documented.
+	Type string
+	// Default value of the openapi default marker.
+	Default     string
+	Required    bool
+	Deprecated  bool
+	Constraints []string
+	// Description of the openapi description marker or the doc comment.
+	Description string
+}
+
+// location is the location of a documented struct.
+type location struct {
+	page   *Document
+	anchor string
+}
+
+// builder builds the pages of a project.
+type builder struct {
+	cfg       *config
+	locations map[*types.TypeName]location
+	// structs are the infos of all the structs of the project which are
+	// needed for embedded structs.
+	structs map[*types.TypeName]*infov1.StructInfo
+	// expanding are the embedded structs which are currently promoted to
+	// detect recursive embedding.
+	expanding map[*types.TypeName]bool
+}
+
+// pagesOf returns the package page of `pkg` and the struct pages of the
+// structs marked with +docs:struct:page. `dir` is the directory of the
+// package relative to the root of the module. The structs are only collected
+// and documented by build.
+func (b *builder) pagesOf(pkg *packages.Package, info *infov1.Information, dir string) ([]*Document, error) {
+	pkgPage := &Document{
+		Kind:  _packageKind,
+		Title: pkg.Name,
+		Doc:   packageDocOf(pkg),
+		name:  path.Join(dir, b.cfg.PackagePage),
+	}
+	pages := []*Document{pkgPage}
+	for obj, sinfo := range infov1.ByPos(pkg.Fset, info.Structs) {
+		if !obj.Exported() {
+			continue
+		}
+		if exclude, _ := optionOf[Exclude](sinfo.Options(), _structResource); exclude {
+			continue
+		}
+		tn := obj.(*types.TypeName)
+		if isPage, _ := optionOf[Page](sinfo.Options(), _structResource); isPage {
+			// compared case insensitive because of case insensitive file
+			// systems
+			if strings.EqualFold(obj.Name()+".md", b.cfg.PackagePage) {
+				return nil, fmt.Errorf(
+					"page of struct `%s` has the same name as the page of package `%s`. Change the packagePage config",
+					obj.Name(),
+					pkg.PkgPath,
+				)
+			}
+			page := &Document{
+				Kind:  _structKind,
+				Title: obj.Name(),
+				Doc:   source.SpecDocOf(sinfo.Decl, sinfo.Spec.Doc),
+				name:  path.Join(dir, obj.Name()+".md"),
+			}
+			pkgPage.Pages = append(pkgPage.Pages, Link{Name: obj.Name(), URL: obj.Name() + ".md"})
+			b.locations[tn] = location{page: page}
+			pages = append(pages, page)
+			continue
+		}
+		b.locations[tn] = location{page: pkgPage, anchor: strings.ToLower(obj.Name())}
+	}
+	return pages, nil
+}
+
+// build documents the structs located on `page`.
+func (b *builder) build(page *Document, info *infov1.Information) {
+	for obj, sinfo := range infov1.ByPos(info.Fset, info.Structs) {
+		loc, isDocumented := b.locations[obj.(*types.TypeName)]
+		if !isDocumented || loc.page != page {
+			continue
+		}
+		page.Structs = append(page.Structs, b.structOf(page, obj.(*types.TypeName), sinfo))
+	}
+}
+
+func (b *builder) structOf(page *Document, obj *types.TypeName, sinfo *infov1.StructInfo) *Struct {
+	s := &Struct{
+		Name:   obj.Name(),
+		Anchor: b.locations[obj].anchor,
+		Doc:    source.SpecDocOf(sinfo.Decl, sinfo.Spec.Doc),
+	}
+	if desc, isDefined := schemaOptionOf[openapi.Description](sinfo.Options()); isDefined {
+		s.Doc = string(desc)
+	}
+	deprecated, _ := schemaOptionOf[openapi.Deprecated](sinfo.Options())
+	s.Deprecated = bool(deprecated)
+	s.Fields = b.fieldsOf(page, obj.Pkg(), obj.Type().Underlying().(*types.Struct), sinfo, false)
+	return s
+}
+
+// fieldsOf returns the documented fields of `strct` whose types are qualified
+// relative to `pkg`. The fields of embedded structs without a json name are
+// promoted like encoding/json does. `sinfo` is nil for structs outside of the
+// project. If `isOptional` is true the fields are promoted through a pointer
+// and only required if they are marked as required.
+func (b *builder) fieldsOf(page *Document, pkg *types.Package, strct *types.Struct, sinfo *infov1.StructInfo, isOptional bool) []*Field {
+	fields := make([]*Field, 0, strct.NumFields())
+	for i := range strct.NumFields() {
+		field := strct.Field(i)
+		var finfo *infov1.FieldInfo
+		if sinfo != nil {
+			finfo = sinfo.Fields[field]
+		}
+		if exclude, _ := optionOf[Exclude](fieldOptionsOf(finfo), _fieldResource); exclude {
+			continue
+		}
+		tag := reflect.StructTag(strct.Tag(i))
+		name, tagOpts, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" && tagOpts == "" {
+			continue
+		}
+		if field.Embedded() && name == "" {
+			if embedded, obj := embeddedOf(field.Type()); embedded != nil && !b.expanding[obj] {
+				_, isPointer := field.Type().(*types.Pointer)
+				b.expanding[obj] = true
+				fields = append(fields, b.fieldsOf(page, pkg, embedded, b.structs[obj], isOptional || isPointer)...)
+				delete(b.expanding, obj)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		f := &Field{
+			Name: field.Name(),
+			Type: b.typeOf(page, pkg, field.Type()),
+		}
+		if name != "" && name != "-" {
+			f.Name = name
+		}
+		f = fieldOf(f, field, finfo, tagOpts, b.cfg)
+		if _, isDefined := schemaOptionOf[openapi.Required](fieldOptionsOf(finfo)); isOptional && !isDefined {
+			f.Required = false
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// embeddedOf returns the struct and its type name of the embedded field of
+// type `typ` if it is a struct. Other embedded types are encoded like a field
+// named after the type.
+func embeddedOf(typ types.Type) (*types.Struct, *types.TypeName) {
+	if ptr, isPointer := typ.(*types.Pointer); isPointer {
+		typ = ptr.Elem()
+	}
+	named, isNamed := types.Unalias(typ).(*types.Named)
+	if !isNamed {
+		return nil, nil
+	}
+	strct, isStruct := named.Underlying().(*types.Struct)
+	if !isStruct {
+		return nil, nil
+	}
+	return strct, named.Origin().Obj()
+}
+
+func fieldOf(f *Field, field *types.Var, finfo *infov1.FieldInfo, tagOpts string, cfg *config) *Field {
+	opts := fieldOptionsOf(finfo)
+	if def, isDefined := schemaOptionOf[openapi.Default](opts); isDefined {
+		f.Default = string(def)
+	}
+	f.Required = isRequired(field, opts, tagOpts, cfg)
+	deprecated, _ := schemaOptionOf[openapi.Deprecated](opts)
+	f.Deprecated = bool(deprecated)
+	f.Constraints = constraintsOf(opts)
+	if finfo != nil && finfo.Field != nil {
+		f.Description = source.DocOf(finfo.Field.Doc)
+	}
+	if desc, isDefined := schemaOptionOf[openapi.Description](opts); isDefined {
+		f.Description = string(desc)
+	}
+	return f
+}
+
+// fieldOptionsOf returns the options of `finfo` which is nil for fields of
+// structs outside of the project.
+func fieldOptionsOf(finfo *infov1.FieldInfo) infov1.Options {
+	if finfo == nil {
+		return nil
+	}
+	return finfo.Options()
+}
+
+// isRequired reports whether the field is required. The required marker takes
+// precedence. Otherwise every field which is not a pointer and not tagged
+// with omitempty or omitzero is required if `cfg.InferRequired` is true.
+func isRequired(field *types.Var, opts infov1.Options, tagOpts string, cfg *config) bool {
+	if required, isDefined := schemaOptionOf[openapi.Required](opts); isDefined {
+		return bool(required)
+	}
+	if !cfg.InferRequired {
+		return false
+	}
+	if _, isPointer := field.Type().Underlying().(*types.Pointer); isPointer {
+		return false
+	}
+	for opt := range strings.SplitSeq(tagOpts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			return false
+		}
+	}
+	return true
+}
+
+// _constraints are the openapi schema options which are documented as
+// constraints of a field in the listed order.
+var _constraints = []string{
+	schemaIdentOf[openapi.Format](),
+	schemaIdentOf[openapi.MinLength](),
+	schemaIdentOf[openapi.MaxLength](),
+	schemaIdentOf[openapi.Pattern](),
+	schemaIdentOf[openapi.Minimum](),
+	schemaIdentOf[openapi.Maximum](),
+	schemaIdentOf[openapi.ExclusiveMinimum](),
+	schemaIdentOf[openapi.ExclusiveMaximum](),
+	schemaIdentOf[openapi.MultipleOf](),
+	schemaIdentOf[openapi.Enum](),
+	schemaIdentOf[openapi.MinItems](),
+	schemaIdentOf[openapi.MaxItems](),
+	schemaIdentOf[openapi.UniqueItems](),
+}
+
+// constraintsOf returns the constraints of `opts` formatted as
+// `<keyword>: <value>`.
+func constraintsOf(opts infov1.Options) []string {
+	constraints := make([]string, 0)
+	for _, ident := range _constraints {
+		values, isDefined := opts.Get(ident)
+		if !isDefined {
+			continue
+		}
+		keyword := ident[strings.LastIndex(ident, ":")+1:]
+		constraints = append(constraints, fmt.Sprintf("%s: %s", keyword, formatValue(values[0])))
+	}
+	return constraints
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case openapi.Enum:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			values = append(values, formatValue(elem))
+		}
+		return strings.Join(values, ", ")
+	case openapi.Pattern:
+		return "`" + string(v) + "`"
+	case nil:
+		return "null"
+	case string:
+		if v == "nil" {
+			return "null"
+		}
+		return "`" + v + "`"
+	}
+	return fmt.Sprint(v)
+}
+
+// typeOf returns the markdown of `typ` used on `page`. The type is linked to
+// the documentation of the struct it references e.g. the element type of a
+// slice.
+func (b *builder) typeOf(page *Document, pkg *types.Package, typ types.Type) string {
+	code := "`" + types.TypeString(typ, func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	}) + "`"
+	named := referencedNamedOf(typ)
+	if named == nil {
+		return code
+	}
+	loc, isDocumented := b.locations[named.Origin().Obj()]
+	if !isDocumented {
+		return code
+	}
+	return fmt.Sprintf("[%s](%s)", code, linkOf(page, loc))
+}
+
+// referencedNamedOf returns the named type referenced by `typ` through
+// pointers, lists and the values of maps.
+func referencedNamedOf(typ types.Type) *types.Named {
+	for {
+		switch t := types.Unalias(typ).(type) {
+		case *types.Named:
+			return t
+		case *types.Pointer:
+			typ = t.Elem()
+		case *types.Slice:
+			typ = t.Elem()
+		case *types.Array:
+			typ = t.Elem()
+		case *types.Map:
+			typ = t.Elem()
+		default:
+			return nil
+		}
+	}
+}
+
+// linkOf returns the relative link from `page` to `loc`.
+func linkOf(page *Document, loc location) string {
+	anchor := ""
+	if loc.anchor != "" {
+		anchor = "#" + loc.anchor
+	}
+	if loc.page == page {
+		return anchor
+	}
+	return relPath(path.Dir(page.name), loc.page.name) + anchor
+}
+
+// relPath returns the slash separated path of `target` relative to the
+// directory `base`.
+func relPath(base, target string) string {
+	baseParts := splitPath(base)
+	targetParts := splitPath(target)
+	i := 0
+	for i < len(baseParts) && i < len(targetParts)-1 && baseParts[i] == targetParts[i] {
+		i++
+	}
+	parts := slices.Repeat([]string{".."}, len(baseParts)-i)
+	return path.Join(append(parts, targetParts[i:]...)...)
+}
+
+func splitPath(p string) []string {
+	p = path.Clean(p)
+	if p == "." {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// packageDocOf returns the package doc comment of `pkg`. The doc comments of
+// the files are concatenated in the order of their filenames.
+func packageDocOf(pkg *packages.Package) string {
+	files := slices.SortedFunc(slices.Values(pkg.Syntax), func(a, b *ast.File) int {
+		return strings.Compare(pkg.Fset.Position(a.Package).Filename, pkg.Fset.Position(b.Package).Filename)
+	})
+	docs := make([]string, 0)
+	for _, file := range files {
+		if file.Doc == nil {
+			continue
+		}
+		if doc := source.DocOf(file.Doc); doc != "" {
+			docs = append(docs, doc)
+		}
+	}
+	return strings.Join(docs, "\n\n")
+}
@@ -0,0 +1,40 @@
+package docs
+
+import (
+	_ "embed"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// _defaultTemplate is the layout of the pages if no template is configured.
+//
+//go:embed templates/page.md.tmpl
+var _defaultTemplate string
+
+// _funcs are the functions available in the templates beside the builtin
+// functions of text/template.
+var _funcs = template.FuncMap{
+	"join": strings.Join,
+	"cell": cell,
+}
+
+// newTemplate returns the template of the pages. If `path` is empty the
+// default template is used.
+func newTemplate(path string) (*template.Template, error) {
+	text := _defaultTemplate
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+	return template.New("page").Funcs(_funcs).Parse(text)
+}
+
+// cell escapes `s` to be used in the cell of a markdown table.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+}
@@ -2,9 +2,12 @@ package openapi
 
 import (
 	"errors"
+	"fmt"
 	"go/types"
+	"slices"
 
 	docv1 "github.com/naivary/codemark/api/doc/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
 	"github.com/naivary/codemark/marker"
 	"github.com/naivary/codemark/typeutil"
 )
@@ -47,3 +50,126 @@ func (e Enum) assign(schema *Schema) {
 	}
 	schema.Enum = e
 }
+
+// Exclude excludes a constant from the values of the enum generated for its
+// type. Excluding a named type disables the enum of its constants e.g. for
+// types accepting values beside their constants.
+type Exclude bool
+
+func (e Exclude) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Excludes the constant from the enum generated for its type. On a named type no enum is generated from its constants",
+	}
+}
+
+// applyEnumValues assigns the values of the enum `ref` to the schema. If any
+// value has a description the values are additionally listed in a `oneOf` to
+// document them.
+func applyEnumValues(schema *Schema, ref *typeRef) {
+	schema.Type = jsonTypeOf(ref.obj.Type())
+	hasDesc := false
+	for _, value := range ref.enum.values {
+		schema.Enum = append(schema.Enum, value.value)
+		hasDesc = hasDesc || value.desc != ""
+	}
+	if !hasDesc {
+		return
+	}
+	for _, value := range ref.enum.values {
+		schema.OneOf = append(schema.OneOf, &Schema{
+			Const: value.value,
+			Desc:  value.desc,
+		})
+	}
+}
+
+// Nullable defines whether null is an allowed value of the field. Only fields
+// which can be nil e.g. pointers, slices and maps can be nullable.
+type Nullable bool
+
+func (n Nullable) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Allows null as value of a pointer, slice, map or interface field. Overwrites the nullable config of the schema",
+	}
+}
+
+// Inline defines whether the schema of the struct referenced by the field is
+// inlined instead of being referenced using `$ref`.
+type Inline bool
+
+func (i Inline) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Inlines the schema of the struct referenced by the field instead of using $ref. Recursive and generic structs are always referenced. Overwrites the inline config of the schema",
+	}
+}
+
+// isInline reports whether the field inlines the schemas of the structs it
+// references. The marker of the field takes precedence over the config.
+func isInline(finfo *infov1.FieldInfo, cfg *config) bool {
+	if finfo == nil {
+		return cfg.Schema.Inline
+	}
+	opts, isDefined := finfo.Options().Get("openapi:schema:inline")
+	if !isDefined {
+		return cfg.Schema.Inline
+	}
+	return bool(opts[0].(Inline))
+}
+
+// isNullable reports whether null is an allowed value of the field `obj`. The
+// marker of the field takes precedence over the config.
+func isNullable(obj types.Object, finfo *infov1.FieldInfo, cfg *config) (bool, error) {
+	canBeNil := isNilable(obj.Type())
+	opts, isDefined := finfo.Options().Get("openapi:schema:nullable")
+	if !isDefined {
+		return cfg.Schema.Nullable && canBeNil, nil
+	}
+	nullable := bool(opts[0].(Nullable))
+	if nullable && !canBeNil {
+		return false, fmt.Errorf("nullable can only be applied to pointer, slice, map or interface fields: %s", obj.Name())
+	}
+	return nullable, nil
+}
+
+// newNullableSchema returns the schema allowing null in addition to `schema`.
+// The type of schemas with a type is extended by null. All the others are
+// combined with null using `oneOf`.
+func newNullableSchema(schema *Schema) *Schema {
+	if schema.Type == "" {
+		// an empty schema allows any value including null
+		if schema.Ref == "" && len(schema.OneOf) == 0 {
+			return schema
+		}
+		nullable := &Schema{
+			OneOf: []*Schema{schema, {Type: nullType}},
+		}
+		// the annotations describe the field and not only one of the
+		// alternatives.
+		moveAnnotations(schema, nullable)
+		return nullable
+	}
+	schema.Nullable = true
+	if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, nil) {
+		schema.Enum = append(schema.Enum, nil)
+	}
+	return schema
+}
+
+// moveAnnotations moves the annotations of `from` to `to`.
+func moveAnnotations(from, to *Schema) {
+	to.Title, from.Title = from.Title, ""
+	to.Desc, from.Desc = from.Desc, ""
+	to.Examples, from.Examples = from.Examples, nil
+	to.Default, from.Default = from.Default, nil
+	to.Deprecated, from.Deprecated = from.Deprecated, false
+	to.ReadOnly, from.ReadOnly = from.ReadOnly, false
+	to.WriteOnly, from.WriteOnly = from.WriteOnly, false
+}
+
+func isNilable(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface:
+		return true
+	}
+	return false
+}
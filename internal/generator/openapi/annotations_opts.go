@@ -1,7 +1,9 @@
 package openapi
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"go/types"
 
 	docv1 "github.com/naivary/codemark/api/doc/v1"
@@ -116,10 +118,12 @@ type Default string
 
 func (d Default) Doc() docv1.Option {
 	return docv1.Option{
-		Desc: "default value fot this field",
+		Desc: "Default value of the field converted to the type of the field e.g. 5 for integers",
 	}
 }
 
+// apply sets the default converted to the type of the schema e.g. `5` for
+// integers. Defaults of arrays are written as JSON e.g. `["a", "b"]`.
 func (d Default) apply(schema *Schema) error {
 	str := string(d)
 	if len(str) == 0 {
@@ -128,6 +132,18 @@ func (d Default) apply(schema *Schema) error {
 	if schema.Type == objectType {
 		return errors.New("default annotation cannot be set for objects")
 	}
-	schema.Default = str
+	if schema.Type == arrayType {
+		var value []any
+		if err := json.Unmarshal([]byte(str), &value); err != nil {
+			return fmt.Errorf("default of an array has to be a JSON array: %s", str)
+		}
+		schema.Default = value
+		return nil
+	}
+	value, err := valueOf(schema, str)
+	if err != nil {
+		return fmt.Errorf("default is invalid: %w", err)
+	}
+	schema.Default = value
 	return nil
 }
@@ -57,7 +57,7 @@ func (m MaxItems) apply(schema *Schema) error {
 		schema.MaxItems = maxItems
 		return nil
 	}
-	if schema.AdditionalProperties.Type == arrayType {
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Type == arrayType {
 		schema.AdditionalProperties.MaxItems = maxItems
 		return nil
 	}
@@ -75,11 +75,11 @@ func (u UniqueItems) apply(schema *Schema) error {
 	if !uniqueItems {
 		return errors.New("by default uniqueItems will be false. Remove the marker so the amount of markers is not increased unnecessarily")
 	}
-	if schema.Type != arrayType {
+	if schema.Type == arrayType {
 		schema.UniqueItems = uniqueItems
 		return nil
 	}
-	if schema.AdditionalProperties.Type == arrayType {
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Type == arrayType {
 		schema.AdditionalProperties.UniqueItems = uniqueItems
 		return nil
 	}
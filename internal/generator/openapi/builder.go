@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+)
+
+// Hooks are called while building the schemas. They allow other generators to
+// apply their own markers to the schemas built from the openapi markers.
+type Hooks struct {
+	// Struct is called after the options of the struct `obj` are applied to
+	// its schema.
+	Struct func(obj types.Object, info *infov1.StructInfo, schema *Schema) error
+	// Field is called after the options of the field `obj` are applied to its
+	// schema.
+	Field func(obj types.Object, info *infov1.FieldInfo, schema *Schema) error
+}
+
+// SchemaBuilder builds self-contained schemas of the types of a project
+// without creating artifacts. It allows other generators to reuse the schemas
+// e.g. as the `openAPIV3Schema` of a CustomResourceDefinition.
+type SchemaBuilder struct {
+	schemas *schemaResourcer
+	cfg     *config
+	res     *resolver
+}
+
+// NewSchemaBuilder returns a builder for the schemas of `roots` and every type
+// reachable from them. `config` is a config of the openapi generator.
+func NewSchemaBuilder(proj infov1.Project, config map[string]any, hooks Hooks, roots ...*types.TypeName) (*SchemaBuilder, error) {
+	cfg, err := newConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	isRoot := func(obj *types.TypeName, _ infov1.Info) bool {
+		return slices.Contains(roots, obj)
+	}
+	res, err := newResolver(proj, cfg, isRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaBuilder{
+		schemas: &schemaResourcer{resource: _schemaResource, hooks: hooks},
+		cfg:     cfg,
+		res:     res.embedded(_defsRef),
+	}, nil
+}
+
+// Schema returns the schema of the root `obj`. Every reference to another
+// schema is replaced by the referenced schema which fails for recursive
+// types.
+func (b *SchemaBuilder) Schema(obj *types.TypeName) (*Schema, error) {
+	ref, err := b.res.lookup(obj)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := b.schemas.schemaOf(ref, b.cfg, b.res)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.dereference(schema, []string{ref.name}); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// dereference replaces every reference of `schema` and its sub-schemas by the
+// referenced schema. `path` are the names of the schemas which are currently
+// dereferenced.
+func (b *SchemaBuilder) dereference(schema *Schema, path []string) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, b.res.refPrefix)
+		if slices.Contains(path, name) {
+			return fmt.Errorf("schema `%s` is recursive and cannot be inlined: %s", name, strings.Join(append(path, name), " -> "))
+		}
+		idx := slices.IndexFunc(b.res.refs, func(ref *typeRef) bool { return ref.name == name })
+		if idx == -1 {
+			return fmt.Errorf("schema is not resolvable: %s", schema.Ref)
+		}
+		referenced, err := b.schemas.schemaOf(b.res.refs[idx], b.cfg, b.res)
+		if err != nil {
+			return err
+		}
+		if err := b.dereference(referenced, append(path, name)); err != nil {
+			return err
+		}
+		merged, err := mergeRef(*schema, *referenced)
+		if err != nil {
+			return err
+		}
+		*schema = merged
+		return nil
+	}
+	subs := []*Schema{schema.Items, schema.AdditionalProperties, schema.Not, schema.If, schema.Then, schema.Else}
+	subs = append(subs, schema.OneOf...)
+	subs = append(subs, schema.AnyOf...)
+	subs = append(subs, schema.AllOf...)
+	for _, prop := range schema.Properties {
+		subs = append(subs, prop)
+	}
+	for _, sub := range subs {
+		if err := b.dereference(sub, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeRef returns the schema `referenced` replacing the reference `ref`. The
+// annotations and extensions next to the reference are added to the
+// referenced schema. All the other keywords next to the reference are
+// combined with the referenced schema using `allOf`.
+func mergeRef(ref, referenced Schema) (Schema, error) {
+	ref.Ref = ""
+	siblings := Schema{}
+	moveAnnotations(&ref, &siblings)
+	siblings.Extensions, ref.Extensions = ref.Extensions, nil
+	isEmpty, err := isEmptySchema(ref)
+	if err != nil {
+		return Schema{}, err
+	}
+	if !isEmpty {
+		ref.AllOf = append(ref.AllOf, &referenced)
+		referenced = ref
+	}
+	annotated := referenced
+	overlayAnnotations(&annotated, &siblings)
+	return annotated, nil
+}
+
+func isEmptySchema(schema Schema) (bool, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return false, err
+	}
+	return string(data) == "{}", nil
+}
+
+// overlayAnnotations sets the annotations and extensions of `from` which are
+// defined to `to`.
+func overlayAnnotations(to, from *Schema) {
+	if from.Title != "" {
+		to.Title = from.Title
+	}
+	if from.Desc != "" {
+		to.Desc = from.Desc
+	}
+	if from.Examples != nil {
+		to.Examples = from.Examples
+	}
+	if from.Default != nil {
+		to.Default = from.Default
+	}
+	to.Deprecated = to.Deprecated || from.Deprecated
+	to.ReadOnly = to.ReadOnly || from.ReadOnly
+	to.WriteOnly = to.WriteOnly || from.WriteOnly
+	for key, value := range from.Extensions {
+		setExtension(to, key, value)
+	}
+}
@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"slices"
+
+	genv1 "github.com/naivary/codemark/api/generator/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+)
+
+// BundleMode defines whether and how the schemas are bundled into a single
+// artifact.
+type BundleMode string
+
+const (
+	// NoBundle generates one artifact per schema.
+	NoBundle BundleMode = "none"
+	// DefsBundle collects all schemas into the `$defs` of a single JSON
+	// Schema.
+	DefsBundle BundleMode = "defs"
+	// ComponentsBundle collects all schemas into the `components.schemas` of
+	// an OpenAPI document.
+	ComponentsBundle BundleMode = "components"
+)
+
+const _defsRef = "#/$defs/"
+
+// isRoot reports whether the type `obj` is selected as a root of the bundle.
+// A root can be selected by its name e.g. `User` or by its qualified name e.g.
+// `example.com/api/models.User`.
+func (b bundleConfig) isRoot(obj *types.TypeName, _ infov1.Info) bool {
+	return slices.Contains(b.Roots, obj.Name()) || slices.Contains(b.Roots, qualifiedName(obj))
+}
+
+// bundle returns a single artifact containing every schema known by the
+// resolver. The references between the schemas are rewritten to point into
+// the bundle.
+func (g *openAPIGenerator) bundle(schemas *schemaResourcer, cfg *config, res *resolver) (*genv1.Artifact, error) {
+	bundle := cfg.Schema.Bundle
+	if err := bundle.hasRoots(res); err != nil {
+		return nil, err
+	}
+	prefix := _defsRef
+	if bundle.Mode == ComponentsBundle {
+		prefix = _componentsRef
+	}
+	res = res.embedded(prefix)
+	defs := make(map[string]*Schema, len(res.refs))
+	for _, ref := range res.schemas() {
+		if _, exists := defs[ref.name]; exists {
+			return nil, fmt.Errorf("schema name is not unique in bundle: %s", ref.name)
+		}
+		schema, err := schemas.schemaOf(ref, cfg, res)
+		if err != nil {
+			return nil, err
+		}
+		defs[ref.name] = schema
+	}
+	id, err := id(bundle.Filename, cfg)
+	if err != nil {
+		return nil, err
+	}
+	filename := filepath.Base(id)
+	if bundle.Mode == ComponentsBundle {
+		doc := newDocument(cfg)
+		doc.Paths = nil
+		doc.Components.Schemas = defs
+		return newArtifact(filename, cfg.Output, doc)
+	}
+	root := Schema{
+		ID:    id,
+		Draft: cfg.Schema.Draft,
+		Defs:  defs,
+	}
+	return newArtifact(filename, cfg.Output, root)
+}
+
+// hasRoots checks that every selected root is part of the bundle.
+func (b bundleConfig) hasRoots(res *resolver) error {
+	for _, root := range b.Roots {
+		isFound := slices.ContainsFunc(res.refs, func(ref *typeRef) bool {
+			return root == ref.obj.Name() || root == qualifiedName(ref.obj)
+		})
+		if !isFound {
+			return fmt.Errorf("root of bundle is not a struct of the project: %s", root)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package openapi
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+func TestGenerator_Bundle(t *testing.T) {
+	files := map[string]string{
+		"api/api.go": `package api
+
+import (
+	"loadertest/billing"
+	"loadertest/shipping"
+)
+
+// +openapi:schema:title="customer"
+type Customer struct {
+	Billing  billing.Address
+	Shipping []*shipping.Address
+}
+
+// +openapi:schema:title="order"
+type Order struct {
+	ID string
+}
+
+type Unused struct {
+	Name string
+}
+`,
+		"billing/address.go":  "package billing\n\ntype Address struct {\n\tIBAN string\n}\n",
+		"shipping/address.go": "package shipping\n\ntype Address struct {\n\tStreet string\n}\n",
+	}
+	tests := []struct {
+		name     string
+		bundle   map[string]any
+		isValid  bool
+		filename string
+		defs     []string
+		refs     map[string]string
+	}{
+		{
+			name:     "defs",
+			bundle:   map[string]any{"mode": "defs"},
+			isValid:  true,
+			filename: "bundle.json",
+			defs:     []string{"BillingAddress", "Customer", "Order", "ShippingAddress"},
+			refs: map[string]string{
+				"billing":  "#/$defs/BillingAddress",
+				"shipping": "#/$defs/ShippingAddress",
+			},
+		},
+		{
+			name:     "components",
+			bundle:   map[string]any{"mode": "components", "filename": "components"},
+			isValid:  true,
+			filename: "components.json",
+			defs:     []string{"BillingAddress", "Customer", "Order", "ShippingAddress"},
+			refs: map[string]string{
+				"billing":  "#/components/schemas/BillingAddress",
+				"shipping": "#/components/schemas/ShippingAddress",
+			},
+		},
+		{
+			name:     "roots",
+			bundle:   map[string]any{"mode": "defs", "roots": []string{"loadertest/api.Customer", "Unused"}},
+			isValid:  true,
+			filename: "bundle.json",
+			defs:     []string{"BillingAddress", "Customer", "ShippingAddress", "Unused"},
+			refs: map[string]string{
+				"billing":  "#/$defs/BillingAddress",
+				"shipping": "#/$defs/ShippingAddress",
+			},
+		},
+		{
+			name:    "unknown root",
+			bundle:  map[string]any{"mode": "defs", "roots": []string{"Product"}},
+			isValid: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := map[string]any{"schema": map[string]any{"bundle": tc.bundle}}
+			artifacts, err := loadertest.Generate(t, New, files, cfg)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			if len(artifacts) != 1 || artifacts[0].Name != tc.filename {
+				t.Fatalf("expected a single artifact named %s. got: %d artifacts", tc.filename, len(artifacts))
+			}
+			var defs map[string]*Schema
+			if tc.bundle["mode"] == "components" {
+				var doc Document
+				err = json.NewDecoder(artifacts[0].Data).Decode(&doc)
+				defs = doc.Components.Schemas
+			} else {
+				var bundle Schema
+				err = json.NewDecoder(artifacts[0].Data).Decode(&bundle)
+				defs = bundle.Defs
+			}
+			if err != nil {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if got := slices.Sorted(maps.Keys(defs)); !slices.Equal(got, tc.defs) {
+				t.Fatalf("schemas of bundle are not equal.\n want: %v\n got: %v", tc.defs, got)
+			}
+			customer := defs["Customer"]
+			if customer.ID != "" || customer.Draft != "" {
+				t.Errorf("bundled schema should not have an $id or $schema: %s, %s", customer.ID, customer.Draft)
+			}
+			got := map[string]string{
+				"billing":  customer.Properties["billing"].Ref,
+				"shipping": customer.Properties["shipping"].Items.Ref,
+			}
+			if !maps.Equal(got, tc.refs) {
+				t.Errorf("refs are not equal.\n want: %v\n got: %v", tc.refs, got)
+			}
+		})
+	}
+}
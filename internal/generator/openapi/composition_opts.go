@@ -0,0 +1,309 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+)
+
+// If is the condition of a conditional schema in the format `<field>=<value>`
+// e.g. `Kind=card`. The condition holds if the field is present and has the
+// value.
+type If string
+
+func (i If) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Condition in the format <field>=<value> e.g. Kind=card. The fields of then are required if the condition holds and the fields of else otherwise",
+	}
+}
+
+func (i If) apply(schema *Schema, cfg *config, info *infov1.StructInfo) error {
+	cond, err := newCondition(string(i), schema, cfg, info)
+	if err != nil {
+		return err
+	}
+	if schema.If != nil {
+		return errors.New("if can only be defined once")
+	}
+	schema.If = cond
+	return nil
+}
+
+type Then []string
+
+func (t Then) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Fields which are required if the condition of if holds",
+	}
+}
+
+func (t Then) apply(schema *Schema, cfg *config, info *infov1.StructInfo) error {
+	required, err := requiredOf("then", t, schema, cfg, info)
+	if err != nil {
+		return err
+	}
+	if schema.Then != nil {
+		return errors.New("then can only be defined once")
+	}
+	schema.Then = required
+	return nil
+}
+
+type Else []string
+
+func (e Else) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Fields which are required if the condition of if does not hold",
+	}
+}
+
+func (e Else) apply(schema *Schema, cfg *config, info *infov1.StructInfo) error {
+	required, err := requiredOf("else", e, schema, cfg, info)
+	if err != nil {
+		return err
+	}
+	if schema.Else != nil {
+		return errors.New("else can only be defined once")
+	}
+	schema.Else = required
+	return nil
+}
+
+// AllOf is a conditional in the format `<field>=<value>:<field>,<field>`
+// e.g. `Kind=card:CardNumber,CVC`. Every conditional is added to the `allOf`
+// of the schema which allows to define more than one condition.
+type AllOf string
+
+func (a AllOf) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Conditional in the format <field>=<value>:<field>,<field> e.g. Kind=card:CardNumber,CVC. Can be used more than once",
+	}
+}
+
+func (a AllOf) apply(schema *Schema, cfg *config, info *infov1.StructInfo) error {
+	i := strings.LastIndex(string(a), ":")
+	if i == -1 {
+		return fmt.Errorf("allOf has to be in the format <field>=<value>:<field>,<field>: %s", a)
+	}
+	cond, err := newCondition(string(a[:i]), schema, cfg, info)
+	if err != nil {
+		return err
+	}
+	then, err := requiredOf("allOf", strings.Split(string(a[i+1:]), ","), schema, cfg, info)
+	if err != nil {
+		return err
+	}
+	schema.AllOf = append(schema.AllOf, &Schema{If: cond, Then: then})
+	return nil
+}
+
+// Not are fields which are mutually exclusive. An object is invalid if all of
+// the fields are present.
+type Not []string
+
+func (n Not) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Fields which are not allowed to be present together e.g. [\"IBAN\", \"CardNumber\"]",
+	}
+}
+
+func (n Not) apply(schema *Schema, cfg *config, info *infov1.StructInfo) error {
+	required, err := requiredOf("not", n, schema, cfg, info)
+	if err != nil {
+		return err
+	}
+	schema.Not = required
+	return nil
+}
+
+// Const is a constant value of a field in the format `<field>=<value>` e.g.
+// `Version=v1`.
+type Const string
+
+func (c Const) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Constant value of a field in the format <field>=<value> e.g. Version=v1. Can be used more than once",
+	}
+}
+
+func (c Const) apply(schema *Schema, cfg *config, info *infov1.StructInfo) error {
+	prop, value, err := parseFieldValue("const", string(c), schema, cfg, info)
+	if err != nil {
+		return err
+	}
+	schema.Properties[prop].Const = value
+	return nil
+}
+
+type MinProperties int64
+
+func (m MinProperties) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Minimum number of properties of the object",
+	}
+}
+
+func (m MinProperties) apply(schema *Schema) error {
+	if m < 0 {
+		return fmt.Errorf("minProperties cannot be negative: %d", m)
+	}
+	schema.MinProperties = int64(m)
+	return nil
+}
+
+type MaxProperties int64
+
+func (m MaxProperties) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Maximum number of properties of the object",
+	}
+}
+
+func (m MaxProperties) apply(schema *Schema) error {
+	if m < 0 {
+		return fmt.Errorf("maxProperties cannot be negative: %d", m)
+	}
+	schema.MaxProperties = int64(m)
+	return nil
+}
+
+type AdditionalProperties bool
+
+func (a AdditionalProperties) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Allows properties which are not defined by the struct. Set to false to reject unknown properties",
+	}
+}
+
+func (a AdditionalProperties) apply(schema *Schema) error {
+	if a {
+		schema.AdditionalProperties = nil
+		return nil
+	}
+	schema.AdditionalProperties = &Schema{False: true}
+	return nil
+}
+
+// isValidObject checks if the object keywords of `schema` can be satisfied.
+func isValidObject(schema *Schema, info *infov1.StructInfo) error {
+	if schema.If == nil && (schema.Then != nil || schema.Else != nil) {
+		return fmt.Errorf("then and else cannot be used without if in struct `%s`", info.Spec.Name.Name)
+	}
+	if schema.If != nil && schema.Then == nil && schema.Else == nil {
+		return fmt.Errorf("if has to be used with then or else in struct `%s`", info.Spec.Name.Name)
+	}
+	if schema.MaxProperties != 0 && schema.MinProperties > schema.MaxProperties {
+		return fmt.Errorf(
+			"minProperties cannot be greater than maxProperties: %d > %d in struct `%s`",
+			schema.MinProperties,
+			schema.MaxProperties,
+			info.Spec.Name.Name,
+		)
+	}
+	isClosed := schema.AdditionalProperties != nil && schema.AdditionalProperties.False
+	if isClosed && schema.MinProperties > int64(len(schema.Properties)) {
+		return fmt.Errorf(
+			"minProperties is greater than the number of properties of struct `%s` which does not allow additional properties: %d",
+			info.Spec.Name.Name,
+			schema.MinProperties,
+		)
+	}
+	return nil
+}
+
+// newCondition returns the schema of the condition `<field>=<value>`.
+func newCondition(cond string, schema *Schema, cfg *config, info *infov1.StructInfo) (*Schema, error) {
+	prop, value, err := parseFieldValue("if", cond, schema, cfg, info)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{
+		Properties: map[string]*Schema{prop: {Const: value}},
+		Required:   []string{prop},
+	}, nil
+}
+
+// requiredOf returns a schema requiring the properties of `fields`.
+func requiredOf(keyword string, fields []string, schema *Schema, cfg *config, info *infov1.StructInfo) (*Schema, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s cannot be empty", keyword)
+	}
+	required := make([]string, 0, len(fields))
+	for _, field := range fields {
+		prop, err := propertyOf(keyword, strings.TrimSpace(field), schema, cfg, info)
+		if err != nil {
+			return nil, err
+		}
+		required = append(required, prop)
+	}
+	return &Schema{Required: required}, nil
+}
+
+// propertyOf returns the name of the property of the field `field`.
+func propertyOf(keyword, field string, schema *Schema, cfg *config, info *infov1.StructInfo) (string, error) {
+	prop := cfg.Schema.Formats.Property.Format(field)
+	if _, exists := schema.Properties[prop]; !exists || !info.HasField(field) {
+		return "", fmt.Errorf(
+			"the field you are trying to reference in %s does not exist in your struct or is not exported: field `%s` in struct `%s`",
+			keyword,
+			field,
+			info.Spec.Name.Name,
+		)
+	}
+	return prop, nil
+}
+
+// parseFieldValue parses `<field>=<value>` and returns the property of the
+// field and the value converted to the type of the property.
+func parseFieldValue(keyword, s string, schema *Schema, cfg *config, info *infov1.StructInfo) (string, any, error) {
+	field, raw, found := strings.Cut(s, "=")
+	if !found {
+		return "", nil, fmt.Errorf("%s has to be in the format <field>=<value>: %s", keyword, s)
+	}
+	prop, err := propertyOf(keyword, strings.TrimSpace(field), schema, cfg, info)
+	if err != nil {
+		return "", nil, err
+	}
+	value, err := valueOf(schema.Properties[prop], strings.TrimSpace(raw))
+	if err != nil {
+		return "", nil, fmt.Errorf("value of field `%s` in %s is invalid: %w", field, keyword, err)
+	}
+	return prop, value, nil
+}
+
+// valueOf converts `raw` to a value of the type of `schema`. Values of
+// properties without a type e.g. references are parsed as JSON and fall back
+// to a string.
+func valueOf(schema *Schema, raw string) (any, error) {
+	if raw == "" {
+		return nil, errors.New("value cannot be empty")
+	}
+	switch schema.Type {
+	case stringType:
+		if unquoted, err := strconv.Unquote(raw); err == nil {
+			return unquoted, nil
+		}
+		return raw, nil
+	case integerType:
+		return strconv.ParseInt(raw, 10, 64)
+	case numberType:
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, err
+		}
+		return json.Number(raw), nil
+	case booleanType:
+		return strconv.ParseBool(raw)
+	case "":
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return raw, nil
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("value can only be defined for strings, numbers and booleans: %s", raw)
+}
@@ -1,28 +1,55 @@
 package openapi
 
 import (
-	"bytes"
+	"embed"
+	"fmt"
 
 	"github.com/goccy/go-yaml"
-	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	configer "github.com/naivary/codemark/internal/config"
 )
 
+// _configSchemas are the JSON Schemas of the config generated from the config
+// structs of this file by the go:generate directive in openapi.go.
+//
+//go:embed schemas/*.json
+var _configSchemas embed.FS
+
 func newConfig(cfg map[string]any) (*config, error) {
 	c := config{
+		Document: documentConfig{
+			Title:   "API",
+			Version: "0.0.0",
+		},
+		Output: outputConfig{
+			Format: JSONOutput,
+			Indent: 0,
+			Order:  KeywordOrder,
+		},
 		Schema: schemaConfig{
-			Draft:     "https://json-schema.org/draft/2020-12/schema",
-			IDBaseURL: "",
+			Draft:        "https://json-schema.org/draft/2020-12/schema",
+			IDBaseURL:    "",
+			Dependencies: IncludeDependencies,
+			Bundle: bundleConfig{
+				Mode:     NoBundle,
+				Filename: "bundle",
+			},
 			Formats: schemaFormats{
 				Property: CamelCase,
 				Filename: SnakeCase,
 			},
 		},
 	}
-	data, err := yaml.Marshal(cfg)
+	// the config is validated here too because the generator is not only
+	// used through the manager e.g. by the crd generator.
+	schemas, err := configer.Schemas(_configSchemas)
 	if err != nil {
 		return nil, err
 	}
-	err = validateConfig(data)
+	if err := configer.Validate(schemas, configer.SchemaRoot, cfg); err != nil {
+		return nil, fmt.Errorf("config is invalid: %w", err)
+	}
+	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -30,33 +57,34 @@ func newConfig(cfg map[string]any) (*config, error) {
 	return &c, err
 }
 
-func validateConfig(data []byte) error {
-	json, err := yaml.YAMLToJSON(data)
-	if err != nil {
-		return err
-	}
-	r := bytes.NewReader(json)
-	c := jsonschema.NewCompiler()
-	c.UseLoader(jsonschema.SchemeURLLoader{
-		"https": newHTTPURLLoader(false),
-	})
-	const schemaFileURL = "https://raw.githubusercontent.com/naivary/codemark/refs/heads/main/internal/generator/openapi/schemas/config.json"
-	schm, err := c.Compile(schemaFileURL)
-	if err != nil {
-		return err
-	}
-	inst, err := jsonschema.UnmarshalJSON(r)
-	if err != nil {
-		return err
-	}
-	return schm.Validate(inst)
-}
-
 // +openapi:schema:description="config options for the openapi generator"
 type config struct {
+	Document documentConfig `yaml:"document"`
+
+	Output outputConfig `yaml:"output"`
+
 	Schema schemaConfig `yaml:"schema"`
 }
 
+// +openapi:schema:description="encoding of the generated artifacts"
+type outputConfig struct {
+	// +openapi:schema:enum=["json", "yaml"]
+	Format OutputFormat `yaml:"format"`
+
+	// +openapi:schema:minimum=0
+	Indent int `yaml:"indent"`
+
+	// +openapi:schema:enum=["keyword", "alphabetical"]
+	Order KeyOrder `yaml:"order"`
+}
+
+// +openapi:schema:description="config options for the assembled OpenAPI document"
+type documentConfig struct {
+	Title string `yaml:"title"`
+
+	Version string `yaml:"version"`
+}
+
 // +openapi:schema:description="config options for the schema model of openapi"
 type schemaConfig struct {
 	// +openapi:schema:enum=["https://json-schema.org/draft/2020-12/schema"]
@@ -64,7 +92,31 @@ type schemaConfig struct {
 
 	IDBaseURL string `yaml:"idBaseURL"`
 
+	// +openapi:schema:enum=["include", "error"]
+	Dependencies Dependencies `yaml:"dependencies"`
+
 	Formats schemaFormats `yaml:"formats"`
+
+	Nullable bool `yaml:"nullable"`
+
+	InferRequired bool `yaml:"inferRequired"`
+
+	Inline bool `yaml:"inline"`
+
+	Types map[string]*Schema `yaml:"types"`
+
+	Bundle bundleConfig `yaml:"bundle"`
+
+	Extensions extensionsConfig `yaml:"extensions"`
+}
+
+// +openapi:schema:description="vendor extensions added to every schema of a named type"
+type extensionsConfig struct {
+	GoPackage bool `yaml:"goPackage"`
+
+	GoType bool `yaml:"goType"`
+
+	Values map[string]any `yaml:"values"`
 }
 
 // +openapi:schema:description="available formats for the property and filename"
@@ -74,3 +126,13 @@ type schemaFormats struct {
 	// +openapi:schema:enum=["snake_case"]
 	Filename NamingConvention `yaml:"filename"`
 }
+
+// +openapi:schema:description="bundling of all the schemas into a single artifact"
+type bundleConfig struct {
+	// +openapi:schema:enum=["none", "defs", "components"]
+	Mode BundleMode `yaml:"mode"`
+
+	Filename string `yaml:"filename"`
+
+	Roots []string `yaml:"roots"`
+}
@@ -0,0 +1,144 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+const (
+	_openAPIVersion = "3.1.0"
+	_documentName   = "openapi"
+	_componentsRef  = "#/components/schemas/"
+	_mediaTypeJSON  = "application/json"
+)
+
+// Document is an OpenAPI 3.1 document.
+type Document struct {
+	OpenAPI           string              `json:"openapi"`
+	Info              DocumentInfo        `json:"info"`
+	JSONSchemaDialect string              `json:"jsonSchemaDialect,omitzero"`
+	Paths             map[string]PathItem `json:"paths,omitzero"`
+	Components        Components          `json:"components,omitzero"`
+}
+
+type DocumentInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitzero"`
+}
+
+// PathItem contains the operations of a path indexed by the lowercase HTTP
+// method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string               `json:"operationId,omitzero"`
+	Summary     string               `json:"summary,omitzero"`
+	Desc        string               `json:"description,omitzero"`
+	Tags        []string             `json:"tags,omitzero"`
+	Deprecated  bool                 `json:"deprecated,omitzero"`
+	Parameters  []*Parameter         `json:"parameters,omitzero"`
+	RequestBody *RequestBody         `json:"requestBody,omitzero"`
+	Responses   map[string]*Response `json:"responses,omitzero"`
+
+	// path and method are not part of the operation object but are needed to
+	// place the operation in the document.
+	path   string
+	method string
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Desc     string  `json:"description,omitzero"`
+	Required bool    `json:"required,omitzero"`
+	Schema   *Schema `json:"schema,omitzero"`
+}
+
+type RequestBody struct {
+	Desc     string                `json:"description,omitzero"`
+	Required bool                  `json:"required,omitzero"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Desc    string                `json:"description"`
+	Content map[string]*MediaType `json:"content,omitzero"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitzero"`
+}
+
+func newDocument(cfg *config) *Document {
+	return &Document{
+		OpenAPI: _openAPIVersion,
+		Info: DocumentInfo{
+			Title:   cfg.Document.Title,
+			Version: cfg.Document.Version,
+		},
+		JSONSchemaDialect: cfg.Schema.Draft,
+		Paths:             make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+		},
+	}
+}
+
+// addOperation adds the operation to the paths of the document. An operation
+// must be unique by its path and method and its operationId must be unique in
+// the whole document.
+func (d *Document) addOperation(op *Operation) error {
+	if err := isValidPathTemplate(op); err != nil {
+		return err
+	}
+	for _, item := range d.Paths {
+		for _, other := range item {
+			if other.OperationID == op.OperationID {
+				return fmt.Errorf("operationId is not unique: %s", op.OperationID)
+			}
+		}
+	}
+	item, exists := d.Paths[op.path]
+	if !exists {
+		item = make(PathItem)
+		d.Paths[op.path] = item
+	}
+	if _, exists := item[op.method]; exists {
+		return fmt.Errorf("operation `%s %s` is defined more than once", op.method, op.path)
+	}
+	item[op.method] = op
+	return nil
+}
+
+var _pathParamRegexp = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// isValidPathTemplate checks that every templated segment of the path has a
+// path parameter and vice versa.
+func isValidPathTemplate(op *Operation) error {
+	names := make([]string, 0)
+	for _, match := range _pathParamRegexp.FindAllStringSubmatch(op.path, -1) {
+		names = append(names, match[1])
+	}
+	for _, param := range op.Parameters {
+		if param.In != "path" {
+			continue
+		}
+		if !slices.Contains(names, param.Name) {
+			return fmt.Errorf("path parameter `%s` of operation `%s` is not part of the path: %s", param.Name, op.OperationID, op.path)
+		}
+	}
+	for _, name := range names {
+		isDefined := slices.ContainsFunc(op.Parameters, func(param *Parameter) bool {
+			return param.In == "path" && param.Name == name
+		})
+		if !isDefined {
+			return fmt.Errorf("path parameter `%s` of operation `%s` is not defined", name, op.OperationID)
+		}
+	}
+	return nil
+}
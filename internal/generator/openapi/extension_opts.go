@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+)
+
+// _extensionPrefix is the prefix of every vendor extension.
+const _extensionPrefix = "x-"
+
+// Extension is a vendor extension in the format `<key>=<value>` e.g.
+// `x-order=1`. The value is parsed as JSON and falls back to a string e.g.
+// `x-go-type=Money`.
+type Extension string
+
+func (e Extension) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Vendor extension in the format <key>=<value> e.g. x-order=1. The key has to start with x- and the value is parsed as JSON or used as string otherwise. Can be used more than once",
+	}
+}
+
+func (e Extension) apply(schema *Schema) error {
+	key, raw, found := strings.Cut(string(e), "=")
+	if !found {
+		return fmt.Errorf("extension has to be in the format <key>=<value>: %s", e)
+	}
+	key, raw = strings.TrimSpace(key), strings.TrimSpace(raw)
+	if !strings.HasPrefix(key, _extensionPrefix) || len(key) == len(_extensionPrefix) {
+		return fmt.Errorf("key of an extension has to start with %s: %s", _extensionPrefix, key)
+	}
+	if raw == "" {
+		return fmt.Errorf("value of extension `%s` cannot be empty", key)
+	}
+	setExtension(schema, key, parseExtensionValue(raw))
+	return nil
+}
+
+// parseExtensionValue parses `raw` as JSON keeping the literals of numbers. If
+// `raw` is not valid JSON it is used as string.
+func parseExtensionValue(raw string) any {
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil || dec.More() {
+		return raw
+	}
+	return value
+}
+
+func setExtension(schema *Schema, key string, value any) {
+	if schema.Extensions == nil {
+		schema.Extensions = make(map[string]any)
+	}
+	schema.Extensions[key] = value
+}
+
+// applyDefaultExtensions adds the extensions configured for every schema of
+// the named type `obj`.
+func applyDefaultExtensions(schema *Schema, obj types.Object, cfg *config) {
+	exts := cfg.Schema.Extensions
+	if exts.GoPackage && obj.Pkg() != nil {
+		setExtension(schema, "x-go-package", obj.Pkg().Path())
+	}
+	if exts.GoType {
+		setExtension(schema, "x-go-type", obj.Name())
+	}
+	for key, value := range exts.Values {
+		setExtension(schema, key, value)
+	}
+}
+
+// isValidExtensions checks that every key of `exts` is a vendor extension.
+func isValidExtensions(exts map[string]any) error {
+	for key := range exts {
+		if !strings.HasPrefix(key, _extensionPrefix) {
+			return fmt.Errorf("key of an extension has to start with %s: %s", _extensionPrefix, key)
+		}
+	}
+	return nil
+}
+
+// appendExtensions appends `exts` sorted by their key to the JSON object
+// `data`.
+func appendExtensions(data []byte, exts map[string]any) ([]byte, error) {
+	if len(data) < 2 || data[len(data)-1] != '}' {
+		return nil, errors.New("extensions can only be added to JSON objects")
+	}
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, key := range slices.Sorted(maps.Keys(exts)) {
+		value, err := json.Marshal(exts[key])
+		if err != nil {
+			return nil, fmt.Errorf("value of extension `%s` is invalid: %w", key, err)
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// extensionsOf returns the vendor extensions of the JSON object `data`. If no
+// extension exists nil will be returned.
+func extensionsOf(data []byte) (map[string]any, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	var exts map[string]any
+	for key, raw := range object {
+		if !strings.HasPrefix(key, _extensionPrefix) {
+			continue
+		}
+		if exts == nil {
+			exts = make(map[string]any)
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		exts[key] = value
+	}
+	return exts, nil
+}
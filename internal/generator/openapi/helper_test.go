@@ -24,27 +24,9 @@ func gen(path, cfgFile string) ([]*genv1.Artifact, error) {
 	if err != nil {
 		return nil, err
 	}
-	cfg := gensCfg["openapi"].(map[string]any)
-	return gen.Generate(proj, cfg)
+	return gen.Generate(proj, gensCfg["openapi"].(map[string]any))
 }
 
-// func gen(path string) ([]*genv1.Artifact, error) {
-// 	mngr, err := generator.NewManager("")
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	gen, err := New()
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	err = mngr.Add(gen)
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	artifacts, err := mngr.Generate(nil, path)
-// 	return artifacts[gen.Domain()], err
-// }
-
 func mustMarshal(v any) []byte {
 	data, err := json.Marshal(v)
 	if err != nil {
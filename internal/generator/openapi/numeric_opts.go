@@ -1,7 +1,10 @@
 package openapi
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"math/big"
 
 	docv1 "github.com/naivary/codemark/api/doc/v1"
 )
@@ -10,140 +13,199 @@ func isNumberOrInteger(typ jsonType) bool {
 	return typ == numberType || typ == integerType
 }
 
-type MultipleOf int64
-
-func (m MultipleOf) Doc() docv1.Option {
-	return docv1.Option{
-		Desc: "Multiple of",
+// numericSchemaOf returns the schema to which a numeric constraint is applied.
+// This is either the schema itself, the items of an array or the additional
+// properties of an object.
+func numericSchemaOf(schema *Schema) *Schema {
+	for _, s := range []*Schema{schema, schema.Items, schema.AdditionalProperties} {
+		if s != nil && isNumberOrInteger(s.Type) {
+			return s
+		}
 	}
+	return nil
 }
 
-func (m MultipleOf) apply(schema *Schema) error {
-	multipleOf := int64(m)
-	if multipleOf == 0 {
-		return errors.New("a multiple of 0 is not allowed")
+// isIntegerType reports whether the numbers of `typ` are integers e.g. int,
+// *uint8, []int32 or map[string]int64.
+func isIntegerType(typ types.Type) bool {
+	for {
+		switch t := typ.Underlying().(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+		case *types.Slice:
+			typ = t.Elem()
+		case *types.Array:
+			typ = t.Elem()
+		case *types.Map:
+			typ = t.Elem()
+		case *types.Basic:
+			return t.Info()&types.IsInteger != 0
+		default:
+			return false
+		}
 	}
-	if isNumberOrInteger(schema.Type) {
-		schema.MultipleOf = multipleOf
-		return nil
+}
+
+// parseNumber returns the number literal `text` of a numeric marker. The
+// literal is kept as written to not lose precision e.g. for integers which
+// cannot be represented by a float64.
+func parseNumber(text []byte) (string, error) {
+	n, _, err := big.ParseFloat(string(text), 10, 0, big.ToNearestEven)
+	if err != nil {
+		return "", fmt.Errorf("not a number: %s", text)
 	}
-	if isNumberOrInteger(schema.Items.Type) {
-		schema.Items.MultipleOf = multipleOf
-		return nil
+	if n.IsInf() {
+		return "", fmt.Errorf("has to be a finite number: %s", text)
 	}
-	if isNumberOrInteger(schema.AdditionalProperties.Type) {
-		schema.AdditionalProperties.MultipleOf = multipleOf
-		return nil
+	// the literal is rendered as is which is why it has to be a valid JSON
+	// number e.g. `+1` or `.5` are not.
+	if !json.Valid(text) {
+		return "", fmt.Errorf("has to be a JSON number: %s", text)
 	}
-	return errors.New(
-		"multipleOf is only appliable to int[8,16,32,64]/float[32,64], objects with value of type int/float and arrays/slices of type int/float",
-	)
+	return string(text), nil
 }
 
-type Minimum int64
+// NumberOf returns the exact value of the number literal `lit` of a numeric
+// option and whether it is an integer.
+func NumberOf(lit string) (*big.Float, bool) {
+	n, _, _ := big.ParseFloat(lit, 10, 1024, big.ToNearestEven)
+	return n, n.IsInt()
+}
 
-func (m Minimum) Doc() docv1.Option {
+// IsExactFloat reports whether the number literal `lit` is an integer which
+// can be represented by a float64 without rounding. Literals which are not
+// integers e.g. 0.1 are always reported as exact because their decimal value
+// is not exactly representable anyway.
+func IsExactFloat(lit string) bool {
+	n, isInt := NumberOf(lit)
+	if !isInt {
+		return true
+	}
+	_, accuracy := n.Float64()
+	return accuracy == big.Exact
+}
+
+// applyNumeric applies the numeric constraint `name` with the literal `lit`
+// to `schema` of a field with the type `fieldType`. The literal is kept as it
+// was written in the marker.
+func applyNumeric(schema *Schema, fieldType types.Type, name string, lit string, set func(*Schema, json.Number)) error {
+	target := numericSchemaOf(schema)
+	if target == nil {
+		return fmt.Errorf(
+			"%s is only appliable to int[8,16,32,64]/float[32,64], objects with value of type int/float and arrays/slices of type int/float",
+			name,
+		)
+	}
+	_, isInt := NumberOf(lit)
+	if isIntegerType(fieldType) && !isInt {
+		return fmt.Errorf("%s of an integer type has to be an integer: %s", name, lit)
+	}
+	if !isIntegerType(fieldType) && !IsExactFloat(lit) {
+		return fmt.Errorf("%s cannot be represented exactly by a float: %s", name, lit)
+	}
+	set(target, json.Number(lit))
+	return nil
+}
+
+type MultipleOf string
+
+func (m MultipleOf) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Minimum allowable number (non-exclusive)",
+		Desc: "Multiple of. Has to be greater than 0 and an integer for integer types",
 	}
 }
 
-func (m Minimum) apply(schema *Schema) error {
-	minimum := int64(m)
-	if isNumberOrInteger(schema.Type) {
-		schema.Minimum = minimum
-		return nil
-	}
-	if isNumberOrInteger(schema.Items.Type) {
-		schema.Items.Minimum = minimum
-		return nil
+func (m *MultipleOf) UnmarshalText(text []byte) error {
+	lit, err := parseNumber(text)
+	*m = MultipleOf(lit)
+	return err
+}
+
+func (m MultipleOf) apply(schema *Schema, fieldType types.Type) error {
+	if n, _ := NumberOf(string(m)); n.Sign() <= 0 {
+		return fmt.Errorf("multipleOf has to be greater than 0: %s", m)
 	}
-	if isNumberOrInteger(schema.AdditionalProperties.Type) {
-		schema.AdditionalProperties.Minimum = minimum
-		return nil
+	return applyNumeric(schema, fieldType, "multipleOf", string(m), func(s *Schema, n json.Number) {
+		s.MultipleOf = n
+	})
+}
+
+type Minimum string
+
+func (m Minimum) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Minimum allowable number (non-exclusive) e.g. 1 or 0.5",
 	}
-	return errors.New(
-		"minimum is only appliable to int[8,16,32,64]/float[32,64], objects with value of type int/float and arrays/slices of type int/float",
-	)
 }
 
-type Maximum int64
+func (m *Minimum) UnmarshalText(text []byte) error {
+	lit, err := parseNumber(text)
+	*m = Minimum(lit)
+	return err
+}
+
+func (m Minimum) apply(schema *Schema, fieldType types.Type) error {
+	return applyNumeric(schema, fieldType, "minimum", string(m), func(s *Schema, n json.Number) {
+		s.Minimum = n
+	})
+}
+
+type Maximum string
 
 func (m Maximum) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Maximum allowable number (non-exclusive)",
+		Desc: "Maximum allowable number (non-exclusive) e.g. 100 or 0.95",
 	}
 }
 
-func (m Maximum) apply(schema *Schema) error {
-	maximum := int64(m)
-	if isNumberOrInteger(schema.Type) {
-		schema.Maximum = maximum
-		return nil
-	}
-	if isNumberOrInteger(schema.Items.Type) {
-		schema.Items.Maximum = maximum
-		return nil
-	}
-	if isNumberOrInteger(schema.AdditionalProperties.Type) {
-		schema.AdditionalProperties.Maximum = maximum
-		return nil
-	}
-	return errors.New(
-		"maximum is only appliable to int[8,16,32,64]/float[32,64], objects with value of type int/float and arrays/slices of type int/float",
-	)
+func (m *Maximum) UnmarshalText(text []byte) error {
+	lit, err := parseNumber(text)
+	*m = Maximum(lit)
+	return err
+}
+
+func (m Maximum) apply(schema *Schema, fieldType types.Type) error {
+	return applyNumeric(schema, fieldType, "maximum", string(m), func(s *Schema, n json.Number) {
+		s.Maximum = n
+	})
 }
 
-type ExclusiveMinimum int64
+type ExclusiveMinimum string
 
 func (e ExclusiveMinimum) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Exclusive minimum",
+		Desc: "Exclusive minimum",
 	}
 }
 
-func (e ExclusiveMaximum) apply(schema *Schema) error {
-	exclusiveMaximum := int64(e)
-	if isNumberOrInteger(schema.Type) {
-		schema.ExclusiveMaximum = exclusiveMaximum
-		return nil
-	}
-	if isNumberOrInteger(schema.Items.Type) {
-		schema.Items.ExclusiveMaximum = exclusiveMaximum
-		return nil
-	}
-	if isNumberOrInteger(schema.AdditionalProperties.Type) {
-		schema.AdditionalProperties.ExclusiveMaximum = exclusiveMaximum
-		return nil
-	}
-	return errors.New(
-		"exclusiveMaximum is only appliable to int[8,16,32,64]/float[32,64], objects with value of type int/float and arrays/slices of type int/float",
-	)
+func (e *ExclusiveMinimum) UnmarshalText(text []byte) error {
+	lit, err := parseNumber(text)
+	*e = ExclusiveMinimum(lit)
+	return err
+}
+
+func (e ExclusiveMinimum) apply(schema *Schema, fieldType types.Type) error {
+	return applyNumeric(schema, fieldType, "exclusiveMinimum", string(e), func(s *Schema, n json.Number) {
+		s.ExclusiveMinimum = n
+	})
 }
 
-type ExclusiveMaximum int
+type ExclusiveMaximum string
 
 func (e ExclusiveMaximum) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Exclusive maximum",
+		Desc: "Exclusive maximum",
 	}
 }
 
-func (e ExclusiveMinimum) apply(schema *Schema) error {
-	exclusiveMinimum := int64(e)
-	if isNumberOrInteger(schema.Type) {
-		schema.ExclusiveMinimum = exclusiveMinimum
-		return nil
-	}
-	if isNumberOrInteger(schema.Items.Type) {
-		schema.Items.ExclusiveMinimum = exclusiveMinimum
-		return nil
-	}
-	if isNumberOrInteger(schema.AdditionalProperties.Type) {
-		schema.AdditionalProperties.ExclusiveMinimum = exclusiveMinimum
-		return nil
-	}
-	return errors.New(
-		"exclusiveMinimum is only appliable to int[8,16,32,64]/float[32,64], objects with value of type int/float and arrays/slices of type int/float",
-	)
+func (e *ExclusiveMaximum) UnmarshalText(text []byte) error {
+	lit, err := parseNumber(text)
+	*e = ExclusiveMaximum(lit)
+	return err
+}
+
+func (e ExclusiveMaximum) apply(schema *Schema, fieldType types.Type) error {
+	return applyNumeric(schema, fieldType, "exclusiveMaximum", string(e), func(s *Schema, n json.Number) {
+		s.ExclusiveMaximum = n
+	})
 }
@@ -3,6 +3,10 @@ package openapi
 import (
 	"errors"
 	"fmt"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
 
 	docv1 "github.com/naivary/codemark/api/doc/v1"
 	infov1 "github.com/naivary/codemark/api/info/v1"
@@ -25,6 +29,34 @@ func (r Required) apply(schema *Schema, finfo *infov1.FieldInfo, cfg *config) er
 	return nil
 }
 
+// isRequired reports whether the field `obj` of the struct `strct` is
+// required. The required marker of the field takes precedence. If the required
+// inference is enabled every field which is not a pointer and not tagged with
+// `omitempty` or `omitzero` is required.
+func isRequired(strct *types.Struct, obj types.Object, finfo *infov1.FieldInfo, cfg *config) bool {
+	if opts, isDefined := finfo.Options().Get("openapi:schema:required"); isDefined {
+		return bool(opts[0].(Required))
+	}
+	if !cfg.Schema.InferRequired {
+		return false
+	}
+	if _, isPointer := obj.Type().Underlying().(*types.Pointer); isPointer {
+		return false
+	}
+	for i := range strct.NumFields() {
+		if strct.Field(i) != obj {
+			continue
+		}
+		_, opts, _ := strings.Cut(reflect.StructTag(strct.Tag(i)).Get("json"), ",")
+		for opt := range strings.SplitSeq(opts, ",") {
+			if opt == "omitempty" || opt == "omitzero" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 type DependentRequired []string
 
 func (dr DependentRequired) Doc() docv1.Option {
@@ -49,3 +81,33 @@ func (dr DependentRequired) apply(root *Schema, cfg *config, finfo *infov1.Field
 	}
 	return nil
 }
+
+type Discriminator string
+
+func (d Discriminator) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Name of the property identifying the implementation of the interface. Fields of the interface type are represented by a oneOf of all implementing structs",
+	}
+}
+
+type DiscriminatorValue string
+
+func (d DiscriminatorValue) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Value of the discriminator property identifying the struct. Defaults to the name of the struct",
+	}
+}
+
+// applyDiscriminator adds the discriminator property with the value `value`
+// to the schema of an implementation and marks it as required.
+func applyDiscriminator(schema *Schema, property, value string) {
+	prop, exists := schema.Properties[property]
+	if !exists {
+		prop = &Schema{Type: stringType}
+		schema.Properties[property] = prop
+	}
+	prop.Enum = []any{value}
+	if !slices.Contains(schema.Required, property) {
+		schema.Required = append(schema.Required, property)
+	}
+}
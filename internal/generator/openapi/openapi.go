@@ -2,18 +2,29 @@
 package openapi
 
 import (
+	"go/types"
+	"io/fs"
 	"maps"
 	"reflect"
 	"slices"
 
+	"golang.org/x/tools/go/packages"
+
 	docv1 "github.com/naivary/codemark/api/doc/v1"
 	genv1 "github.com/naivary/codemark/api/generator/v1"
 	infov1 "github.com/naivary/codemark/api/info/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
 	regv1 "github.com/naivary/codemark/api/registry/v1"
+	configer "github.com/naivary/codemark/internal/config"
 	"github.com/naivary/codemark/registry"
 )
 
-func newRegistry(resources ...Resourcer) (regv1.Registry, error) {
+// optioner is a resource which defines options.
+type optioner interface {
+	Options() []*optionv1.Option
+}
+
+func newRegistry(resources ...optioner) (regv1.Registry, error) {
 	reg := registry.InMemory()
 	for _, resource := range resources {
 		opts := resource.Options()
@@ -29,27 +40,55 @@ func newRegistry(resources ...Resourcer) (regv1.Registry, error) {
 
 const _domain = "openapi"
 
-var _ genv1.Generator = (*openAPIGenerator)(nil)
+var (
+	_ genv1.Generator     = (*openAPIGenerator)(nil)
+	_ genv1.ConfigSchemer = (*openAPIGenerator)(nil)
+)
 
 func New() (genv1.Generator, error) {
+	schemas := NewSchemaResourcer()
 	gen := &openAPIGenerator{
 		resources: map[reflect.Type][]Resourcer{
-			reflect.TypeFor[*infov1.StructInfo](): {NewSchemaResourcer()},
+			reflect.TypeFor[*infov1.StructInfo](): {schemas},
+			reflect.TypeFor[*infov1.NamedInfo]():  {schemas},
+			reflect.TypeFor[*infov1.AliasInfo]():  {schemas},
 		},
+		operations: newOperationResourcer(),
+	}
+	resources := make([]optioner, 0)
+	// a resource can be registered for more than one kind of type but its
+	// options are only defined once.
+	isDefined := make(map[Resourcer]bool)
+	for _, resource := range flatten(slices.Collect(maps.Values(gen.resources))) {
+		if isDefined[resource] {
+			continue
+		}
+		isDefined[resource] = true
+		resources = append(resources, resource)
 	}
-	resources := flatten(slices.Collect(maps.Values(gen.resources)))
-	reg, err := newRegistry(resources...)
+	reg, err := newRegistry(append(resources, gen.operations)...)
 	if err != nil {
 		return nil, err
 	}
 	gen.reg = reg
+	gen.configSchemas, err = configer.Schemas(_configSchemas)
+	if err != nil {
+		return nil, err
+	}
 	return gen, nil
 }
 
 type openAPIGenerator struct {
 	reg regv1.Registry
 
+	// configSchemas are the JSON Schemas of the config.
+	configSchemas fs.FS
+
 	resources map[reflect.Type][]Resourcer
+
+	// operations are assembled into a single OpenAPI document instead of
+	// being an artifact on their own.
+	operations *operationResourcer
 }
 
 func (g *openAPIGenerator) Domain() docv1.Domain {
@@ -59,9 +98,14 @@ func (g *openAPIGenerator) Domain() docv1.Domain {
 	}
 }
 
+func (g *openAPIGenerator) ConfigSchema() (fs.FS, string) {
+	return g.configSchemas, configer.SchemaRoot
+}
+
 func (g *openAPIGenerator) Resources() map[string]*docv1.Resource {
 	return map[string]*docv1.Resource{
-		_schemaResource: {Desc: "Generate an OpenAPI compatible JSON Schema"},
+		_schemaResource:    {Desc: "Generate an OpenAPI compatible JSON Schema"},
+		_operationResource: {Desc: "Generate an operation of the OpenAPI document from a function or method"},
 	}
 }
 
@@ -71,6 +115,36 @@ func (g *openAPIGenerator) Registry() regv1.Registry {
 
 func (g *openAPIGenerator) ConfigDoc() map[string]docv1.Config {
 	return map[string]docv1.Config{
+		"document": {
+			Description: `Defines the metadata of the OpenAPI document which is generated if at least one function or method is marked as an operation. The document contains all the operations as paths and all the schemas as components.`,
+			Options: map[string]docv1.Config{
+				"title": {
+					Default:     "API",
+					Description: `Title of the API used in the info object of the document.`,
+				},
+				"version": {
+					Default:     "0.0.0",
+					Description: `Version of the API used in the info object of the document.`,
+				},
+			},
+		},
+		"output": {
+			Description: `Defines the encoding of all generated artifacts. The file extension and the $id of the schemas follow the format.`,
+			Options: map[string]docv1.Config{
+				"format": {
+					Default:     "json",
+					Description: `Format of the artifacts. Valid options are "json" and "yaml".`,
+				},
+				"indent": {
+					Default:     "0",
+					Description: `Number of spaces used for indentation. 0 generates compact JSON. YAML is indented with 2 spaces if no indentation is set.`,
+				},
+				"order": {
+					Default:     "keyword",
+					Description: `Order of the keys. "keyword" orders the keys like the keywords of the specification e.g. $id and $schema first. "alphabetical" sorts all keys alphabetically which results in stable diffs.`,
+				},
+			},
+		},
 		"schema": {
 			Description: `Defines the full set of configuration options that control the generation of JSON Schemas compliant with the OpenAPI Specification. This includes structural metadata, type definitions, validation constraints, and any OpenAPI-specific extensions required for interoperability.`,
 			Options: map[string]docv1.Config{
@@ -78,10 +152,64 @@ func (g *openAPIGenerator) ConfigDoc() map[string]docv1.Config {
 					Default:     "https://json-schema.org/draft/2020-12/schema",
 					Description: `Specifies the JSON Schema draft version to use for validation. Currently, this setting is not configurable — the default value is the only supported option. It exists for future compatibility and may allow other drafts in later releases.`,
 				},
+				"dependencies": {
+					Default:     string(IncludeDependencies),
+					Description: `Defines how structs without schema markers are handled if they are referenced by a field of a schema. "include" generates a schema for them automatically, even if they are not part of the loaded packages. "error" fails the generation with an error pointing to the referencing field.`,
+				},
+				"bundle": {
+					Description: "Bundles all the schemas into a single self-contained artifact instead of generating one file per schema. The references between the schemas are rewritten to point into the bundle.",
+					Options: map[string]docv1.Config{
+						"mode": {
+							Default:     string(NoBundle),
+							Description: `Defines where the schemas are collected. "none" generates one file per schema. "defs" collects them into the $defs of a single JSON Schema and "components" into the components.schemas of an OpenAPI document.`,
+						},
+						"filename": {
+							Default:     "bundle",
+							Description: "Name of the bundle without the file extension. It is formatted using the filename format.",
+						},
+						"roots": {
+							Default:     []string{},
+							Description: "Structs which are the roots of the bundle. Only the schemas reachable from the roots are included. A root can be selected by its name e.g. User or by its qualified name e.g. example.com/api/models.User. If empty every struct with schema markers is a root.",
+						},
+					},
+				},
 				"idBaseURL": {
 					Default:     "",
 					Description: `Sets the base URL for the $id field in generated JSON Schemas. The base URL is prepended to schema identifiers so they can be resolved consistently. If left empty (default), schemas will not have a network-resolvable $id and will only be referenceable from the local filesystem.`,
 				},
+				"nullable": {
+					Default:     false,
+					Description: `Allows null as value of every pointer, slice, map and interface field because they are encoded as null if they are nil. The type of the field is rendered as list e.g. ["string", "null"] and references are combined with null using oneOf. The nullable marker of a field takes precedence.`,
+				},
+				"inferRequired": {
+					Default:     false,
+					Description: `Infers which fields are required instead of only using the required marker. Every field which is not a pointer and not tagged with omitempty or omitzero in its json struct tag is required. The required marker of a field takes precedence.`,
+				},
+				"inline": {
+					Default:     false,
+					Description: `Inlines the schemas of the structs referenced by fields instead of using $ref. Recursive and generic structs are always referenced. Structs without schema markers which are only inlined get no schema of their own. The inline marker of a field takes precedence.`,
+				},
+				"extensions": {
+					Description: "Vendor extensions which are added to the schema of every named type. The extension markers of a type take precedence.",
+					Options: map[string]docv1.Config{
+						"goPackage": {
+							Default:     false,
+							Description: "Adds x-go-package with the import path of the package declaring the type.",
+						},
+						"goType": {
+							Default:     false,
+							Description: "Adds x-go-type with the name of the go type.",
+						},
+						"values": {
+							Default:     map[string]any{},
+							Description: `Extensions added with a fixed value e.g. {"x-internal": false}. Every key has to start with x-.`,
+						},
+					},
+				},
+				"types": {
+					Default:     map[string]any{},
+					Description: `Maps fully qualified go types e.g. "time.Time" or "github.com/google/uuid.UUID" to the schema used for fields of the type. Builtin mappings exist for time.Time, time.Duration, []byte, net.IP, net/url.URL, github.com/google/uuid.UUID and encoding/json.RawMessage and can be overwritten. Types implementing encoding.TextMarshaler are mapped to a string automatically.`,
+				},
 				"formats": {
 					Description: "Controls the output style of generated JSON Schemas. Use this option to align schema formatting (e.g., indentation, line wrapping, property ordering) with your organization’s conventions.",
 					Options: map[string]docv1.Config{
@@ -105,16 +233,58 @@ func (g *openAPIGenerator) Generate(proj infov1.Project, config map[string]any)
 	if err != nil {
 		return nil, err
 	}
+	if err := isValidExtensions(cfg.Schema.Extensions.Values); err != nil {
+		return nil, err
+	}
+	schemas := &schemaResourcer{resource: _schemaResource}
+	ops, refs, err := g.collectOperations(proj)
+	if err != nil {
+		return nil, err
+	}
+	isRoot := func(_ *types.TypeName, info infov1.Info) bool {
+		return schemas.CanCreate(info)
+	}
+	bundle := cfg.Schema.Bundle
+	if bundle.Mode != NoBundle && len(bundle.Roots) > 0 {
+		isRoot = bundle.isRoot
+	}
+	res, err := newResolver(proj, cfg, isRoot, refs...)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := g.schemas(proj, schemas, cfg, res)
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return artifacts, nil
+	}
+	doc, err := g.document(ops, schemas, cfg, res)
+	if err != nil {
+		return nil, err
+	}
+	return append(artifacts, doc), nil
+}
+
+// schemas returns the schema artifacts of the project. If bundling is enabled
+// all the schemas are returned as a single artifact.
+func (g *openAPIGenerator) schemas(proj infov1.Project, schemas *schemaResourcer, cfg *config, res *resolver) ([]*genv1.Artifact, error) {
+	if cfg.Schema.Bundle.Mode != NoBundle {
+		bundle, err := g.bundle(schemas, cfg, res)
+		if err != nil {
+			return nil, err
+		}
+		return []*genv1.Artifact{bundle}, nil
+	}
 	artifacts := make([]*genv1.Artifact, 0, len(proj))
-	for pkg, pkgInfo := range proj {
-		infos := collectInfos(pkgInfo)
-		for obj, info := range infos {
+	for _, pkg := range infov1.Packages(proj) {
+		for obj, info := range proj[pkg].All() {
 			infoType := reflect.TypeOf(info)
 			for _, resource := range g.resources[infoType] {
 				if !resource.CanCreate(info) {
 					continue
 				}
-				artifact, err := resource.Create(pkg, obj, info, cfg)
+				artifact, err := resource.Create(pkg, obj, info, cfg, res)
 				if err != nil {
 					return nil, err
 				}
@@ -123,5 +293,66 @@ func (g *openAPIGenerator) Generate(proj infov1.Project, config map[string]any)
 
 		}
 	}
+	// schemas of the structs and enums without markers which are referenced
+	// by other schemas.
+	for _, dep := range res.dependencies() {
+		artifact, err := schemas.createRef(dep, cfg, res)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
 	return artifacts, nil
 }
+
+// operationRef is a function or method which is an operation of the document.
+type operationRef struct {
+	pkg  *packages.Package
+	obj  types.Object
+	info *infov1.FuncInfo
+}
+
+// collectOperations returns all the operations of the project and the types
+// referenced by them.
+func (g *openAPIGenerator) collectOperations(proj infov1.Project) ([]operationRef, []types.Type, error) {
+	ops := make([]operationRef, 0)
+	refs := make([]types.Type, 0)
+	for _, pkg := range infov1.Packages(proj) {
+		for obj, info := range infov1.ByPos(pkg.Fset, funcsOf(proj[pkg])) {
+			if !g.operations.CanCreate(info) {
+				continue
+			}
+			referenced, err := g.operations.types(pkg, info)
+			if err != nil {
+				return nil, nil, err
+			}
+			ops = append(ops, operationRef{pkg: pkg, obj: obj, info: info})
+			refs = append(refs, referenced...)
+		}
+	}
+	return ops, refs, nil
+}
+
+// document assembles the OpenAPI document containing all the operations as
+// paths and all the schemas as components.
+func (g *openAPIGenerator) document(ops []operationRef, schemas *schemaResourcer, cfg *config, res *resolver) (*genv1.Artifact, error) {
+	res = res.embedded(_componentsRef)
+	doc := newDocument(cfg)
+	for _, ref := range res.schemas() {
+		schema, err := schemas.schemaOf(ref, cfg, res)
+		if err != nil {
+			return nil, err
+		}
+		doc.Components.Schemas[ref.name] = schema
+	}
+	for _, ref := range ops {
+		op, err := g.operations.Create(ref.pkg, ref.obj, ref.info, res)
+		if err != nil {
+			return nil, err
+		}
+		if err := doc.addOperation(op); err != nil {
+			return nil, err
+		}
+	}
+	return newArtifact(_documentName+cfg.Output.ext(), cfg.Output, doc)
+}
@@ -0,0 +1,297 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+)
+
+var _httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var _paramLocations = []string{"path", "query", "header", "cookie"}
+
+type Path string
+
+func (p Path) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Path of the operation relative to the server e.g. /users/{id}",
+	}
+}
+
+func (p Path) apply(op *Operation) error {
+	path := string(p)
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("path of an operation has to begin with a forward slash: %s", path)
+	}
+	op.path = path
+	return nil
+}
+
+type Method string
+
+func (m Method) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "HTTP method of the operation e.g. get, post",
+	}
+}
+
+func (m Method) apply(op *Operation) error {
+	method := strings.ToLower(string(m))
+	if !slices.Contains(_httpMethods, method) {
+		return fmt.Errorf("http method is not supported: %s. Valid methods are: %v", m, _httpMethods)
+	}
+	op.method = method
+	return nil
+}
+
+type OperationID string
+
+func (o OperationID) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Unique identifier of the operation. Defaults to the name of the function",
+	}
+}
+
+func (o OperationID) apply(op *Operation) error {
+	if len(o) == 0 {
+		return errors.New("operationId cannot be empty")
+	}
+	op.OperationID = string(o)
+	return nil
+}
+
+type Tags []string
+
+func (t Tags) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Tags for logical grouping of operations",
+	}
+}
+
+func (t Tags) apply(op *Operation) error {
+	if len(t) == 0 {
+		return errors.New("tags cannot be empty")
+	}
+	op.Tags = t
+	return nil
+}
+
+type Summary string
+
+func (s Summary) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Short summary of what the operation does",
+	}
+}
+
+func (s Summary) apply(op *Operation) error {
+	if len(s) == 0 {
+		return errors.New("summary cannot be empty")
+	}
+	op.Summary = string(s)
+	return nil
+}
+
+type Body string
+
+func (b Body) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Go type of the JSON request body e.g. CreateUserRequest or []models.User",
+	}
+}
+
+func (b Body) typ(pkg *packages.Package, decl *ast.FuncDecl) (types.Type, error) {
+	return evalType(pkg, decl, string(b))
+}
+
+func (b Body) apply(op *Operation, pkg *packages.Package, decl *ast.FuncDecl, res *resolver) error {
+	typ, err := b.typ(pkg, decl)
+	if err != nil {
+		return err
+	}
+	schema, err := newSchema(typ, res)
+	if err != nil {
+		return err
+	}
+	op.RequestBody = &RequestBody{
+		Required: true,
+		Content: map[string]*MediaType{
+			_mediaTypeJSON: {Schema: &schema},
+		},
+	}
+	return nil
+}
+
+var _statusCodeRegexp = regexp.MustCompile(`^([1-5][0-9][0-9]|[1-5]XX|default)$`)
+
+// Resp is a response of an operation in the format `<status>[:<type>]` e.g.
+// `200:User` or `204`.
+type Resp string
+
+func (r Resp) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Response of the operation in the format <status>[:<type>] e.g. 200:User, 404:Error or 204",
+	}
+}
+
+func (r Resp) parse() (string, string, error) {
+	status, typeExpr, _ := strings.Cut(string(r), ":")
+	if !_statusCodeRegexp.MatchString(status) {
+		return "", "", fmt.Errorf("status code of response is invalid: %s", status)
+	}
+	return status, typeExpr, nil
+}
+
+// typ returns the type of the response body. If the response has no body nil
+// will be returned.
+func (r Resp) typ(pkg *packages.Package, decl *ast.FuncDecl) (types.Type, error) {
+	_, typeExpr, err := r.parse()
+	if err != nil || typeExpr == "" {
+		return nil, err
+	}
+	return evalType(pkg, decl, typeExpr)
+}
+
+func (r Resp) apply(op *Operation, pkg *packages.Package, decl *ast.FuncDecl, res *resolver) error {
+	status, _, err := r.parse()
+	if err != nil {
+		return err
+	}
+	if _, exists := op.Responses[status]; exists {
+		return fmt.Errorf("response for status code is defined more than once: %s", status)
+	}
+	response := &Response{
+		Desc: statusText(status),
+	}
+	typ, err := r.typ(pkg, decl)
+	if err != nil {
+		return err
+	}
+	if typ != nil {
+		schema, err := newSchema(typ, res)
+		if err != nil {
+			return err
+		}
+		response.Content = map[string]*MediaType{
+			_mediaTypeJSON: {Schema: &schema},
+		}
+	}
+	op.Responses[status] = response
+	return nil
+}
+
+// Param is a parameter of an operation in the format `<in>:<name>:<type>` e.g.
+// `query:limit:int`.
+type Param string
+
+func (p Param) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Parameter of the operation in the format <in>:<name>:<type> e.g. query:limit:int or path:id:string",
+	}
+}
+
+func (p Param) parse() (string, string, string, error) {
+	parts := strings.SplitN(string(p), ":", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("parameter has to be in the format <in>:<name>:<type>: %s", p)
+	}
+	in, name, typeExpr := parts[0], parts[1], parts[2]
+	if !slices.Contains(_paramLocations, in) {
+		return "", "", "", fmt.Errorf("location of parameter is invalid: %s. Valid locations are: %v", in, _paramLocations)
+	}
+	if name == "" {
+		return "", "", "", fmt.Errorf("name of parameter cannot be empty: %s", p)
+	}
+	return in, name, typeExpr, nil
+}
+
+func (p Param) typ(pkg *packages.Package, decl *ast.FuncDecl) (types.Type, error) {
+	_, _, typeExpr, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return evalType(pkg, decl, typeExpr)
+}
+
+func (p Param) apply(op *Operation, pkg *packages.Package, decl *ast.FuncDecl, res *resolver) error {
+	in, name, _, err := p.parse()
+	if err != nil {
+		return err
+	}
+	typ, err := p.typ(pkg, decl)
+	if err != nil {
+		return err
+	}
+	schema, err := newSchema(typ, res)
+	if err != nil {
+		return err
+	}
+	param := &Parameter{
+		Name:     name,
+		In:       in,
+		Required: in == "path",
+		Schema:   &schema,
+	}
+	op.Parameters = append(op.Parameters, param)
+	return nil
+}
+
+// In defines the location of a function parameter in the request. The
+// function parameter will be added as a parameter to the operation.
+type In string
+
+func (i In) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Location of the function parameter in the request e.g. path, query, header or cookie",
+	}
+}
+
+func (i In) apply(param *Parameter) error {
+	in := string(i)
+	if !slices.Contains(_paramLocations, in) {
+		return fmt.Errorf("location of parameter is invalid: %s. Valid locations are: %v", in, _paramLocations)
+	}
+	param.In = in
+	// path parameters are always required by the specification
+	param.Required = param.Required || in == "path"
+	return nil
+}
+
+// evalType evaluates the type expression `expr` in the scope of the file
+// declaring `decl`. This allows to reference types of imported packages e.g.
+// `models.User`.
+func evalType(pkg *packages.Package, decl *ast.FuncDecl, expr string) (types.Type, error) {
+	if expr == "" {
+		return nil, errors.New("type expression cannot be empty")
+	}
+	tv, err := types.Eval(pkg.Fset, pkg.Types, decl.Pos(), expr)
+	if err != nil {
+		return nil, fmt.Errorf("type expression `%s` of function `%s` is invalid: %w", expr, decl.Name.Name, err)
+	}
+	if !tv.IsType() {
+		return nil, fmt.Errorf("expression is not a type: %s", expr)
+	}
+	return tv.Type, nil
+}
+
+func statusText(status string) string {
+	if status == "default" {
+		return "Default response"
+	}
+	var code int
+	if _, err := fmt.Sscanf(status, "%d", &code); err == nil && len(status) == 3 {
+		if text := http.StatusText(code); text != "" {
+			return text
+		}
+	}
+	return status + " response"
+}
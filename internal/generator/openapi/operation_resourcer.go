@@ -0,0 +1,209 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+
+	"github.com/iancoleman/strcase"
+	"golang.org/x/tools/go/packages"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+)
+
+const _operationResource = "operation"
+
+// operationResourcer creates the operations of the OpenAPI document from
+// functions and methods. In contrast to the other resources an operation is
+// not an artifact on its own but part of the assembled document.
+type operationResourcer struct {
+	resource string
+}
+
+func newOperationResourcer() *operationResourcer {
+	return &operationResourcer{_operationResource}
+}
+
+func (o operationResourcer) Resource() string {
+	return o.resource
+}
+
+func (o operationResourcer) Options() []*optionv1.Option {
+	return makeOpts(o.resource,
+		mustMakeOpt(_typeName, Path(""), _unique, optionv1.TargetFunc, optionv1.TargetMethod),
+		mustMakeOpt(_typeName, Method(""), _unique, optionv1.TargetFunc, optionv1.TargetMethod),
+		mustMakeOpt(_typeName, OperationID(""), _unique, optionv1.TargetFunc, optionv1.TargetMethod),
+		mustMakeOpt(_typeName, Tags(nil), _unique, optionv1.TargetFunc, optionv1.TargetMethod),
+		mustMakeOpt(_typeName, Summary(""), _unique, optionv1.TargetFunc, optionv1.TargetMethod),
+		mustMakeOpt("requestBody", Body(""), _unique, optionv1.TargetFunc, optionv1.TargetMethod),
+		mustMakeOpt("response", Resp(""), _repetable, optionv1.TargetFunc, optionv1.TargetMethod),
+		mustMakeOpt("parameter", Param(""), _repetable, optionv1.TargetFunc, optionv1.TargetMethod),
+		mustMakeOpt(_typeName, Description(""), _unique, optionv1.TargetFunc, optionv1.TargetMethod, optionv1.TargetFuncParam),
+		mustMakeOpt(_typeName, Deprecated(false), _unique, optionv1.TargetFunc, optionv1.TargetMethod),
+		// function parameters
+		mustMakeOpt(_typeName, In(""), _unique, optionv1.TargetFuncParam),
+		mustMakeOpt(_typeName, Required(false), _unique, optionv1.TargetFuncParam),
+	)
+}
+
+func (o operationResourcer) CanCreate(info infov1.Info) bool {
+	_, isFunc := info.(*infov1.FuncInfo)
+	return isFunc && info.Options().IsDefined("openapi:operation:path")
+}
+
+// types returns all the types referenced by the operation. They have to be
+// known by the resolver before the operation can be created.
+func (o operationResourcer) types(pkg *packages.Package, info *infov1.FuncInfo) ([]types.Type, error) {
+	refs := make([]types.Type, 0)
+	for ident, opts := range info.Options() {
+		if !isResource(ident, o.resource) {
+			continue
+		}
+		for _, opt := range opts {
+			var typ types.Type
+			var err error
+			switch o := opt.(type) {
+			case Body:
+				typ, err = o.typ(pkg, info.Decl)
+			case Resp:
+				typ, err = o.typ(pkg, info.Decl)
+			case Param:
+				typ, err = o.typ(pkg, info.Decl)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if typ != nil {
+				refs = append(refs, typ)
+			}
+		}
+	}
+	for obj, param := range info.Params {
+		if param.Opts.IsDefined("openapi:operation:in") {
+			refs = append(refs, obj.Type())
+		}
+	}
+	return refs, nil
+}
+
+func (o operationResourcer) Create(
+	pkg *packages.Package,
+	obj types.Object,
+	info *infov1.FuncInfo,
+	res *resolver,
+) (*Operation, error) {
+	op := &Operation{
+		OperationID: defaultOperationID(obj),
+		Responses:   make(map[string]*Response),
+	}
+	err := o.applyFuncOpts(op, pkg, info, res)
+	if err != nil {
+		return nil, err
+	}
+	if op.method == "" {
+		return nil, fmt.Errorf("operation `%s` has no http method", op.OperationID)
+	}
+	for obj, pinfo := range infov1.ByPos(fsetOf(pkg), info.Params) {
+		if !pinfo.Opts.IsDefined("openapi:operation:in") {
+			continue
+		}
+		param, err := o.newParameter(obj, pinfo, res)
+		if err != nil {
+			return nil, fmt.Errorf("parameter of operation `%s`: %w", op.OperationID, err)
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+	return op, nil
+}
+
+func (o operationResourcer) applyFuncOpts(op *Operation, pkg *packages.Package, info *infov1.FuncInfo, res *resolver) error {
+	for ident, opts := range info.Options() {
+		if !isResource(ident, o.resource) {
+			continue
+		}
+		for _, opt := range opts {
+			var err error
+			switch o := opt.(type) {
+			case Path:
+				err = o.apply(op)
+			case Method:
+				err = o.apply(op)
+			case OperationID:
+				err = o.apply(op)
+			case Tags:
+				err = o.apply(op)
+			case Summary:
+				err = o.apply(op)
+			case Description:
+				op.Desc = string(o)
+			case Deprecated:
+				op.Deprecated = bool(o)
+			case Body:
+				err = o.apply(op, pkg, info.Decl, res)
+			case Resp:
+				err = o.apply(op, pkg, info.Decl, res)
+			case Param:
+				err = o.apply(op, pkg, info.Decl, res)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (o operationResourcer) newParameter(obj types.Object, info *infov1.ParamInfo, res *resolver) (*Parameter, error) {
+	if info.Ident == nil || info.Ident.Name == "_" {
+		return nil, errors.New("unnamed function parameters cannot be used as parameter")
+	}
+	schema, err := newSchema(obj.Type(), res)
+	if err != nil {
+		return nil, err
+	}
+	param := &Parameter{
+		Name:   info.Ident.Name,
+		Schema: &schema,
+	}
+	for ident, opts := range info.Options() {
+		if !isResource(ident, o.resource) {
+			continue
+		}
+		for _, opt := range opts {
+			var err error
+			switch o := opt.(type) {
+			case In:
+				err = o.apply(param)
+			case Description:
+				param.Desc = string(o)
+			case Required:
+				param.Required = param.Required || bool(o)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return param, nil
+}
+
+// defaultOperationID returns the operationId of a function without an
+// operationId marker. Methods are prefixed with the name of their receiver to
+// avoid collisions between different services.
+func defaultOperationID(obj types.Object) string {
+	fn := obj.(*types.Func)
+	recv := fn.Signature().Recv()
+	if recv == nil {
+		return strcase.ToLowerCamel(fn.Name())
+	}
+	typ := types.Unalias(recv.Type())
+	if ptr, isPtr := typ.(*types.Pointer); isPtr {
+		typ = types.Unalias(ptr.Elem())
+	}
+	named, isNamed := typ.(*types.Named)
+	if !isNamed {
+		return strcase.ToLowerCamel(fn.Name())
+	}
+	return strcase.ToLowerCamel(named.Obj().Name() + fn.Name())
+}
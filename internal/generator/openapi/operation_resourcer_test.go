@@ -0,0 +1,223 @@
+package openapi
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+func TestResourcer_Operation(t *testing.T) {
+	const models = `package models
+
+// +openapi:schema:title="user"
+type User struct {
+	Name    string
+	Address Address
+}
+
+type Address struct {
+	Street string
+}
+
+type Error struct {
+	Msg string
+}
+`
+	tests := []struct {
+		name    string
+		api     string
+		isValid bool
+		want    Document
+	}{
+		{
+			name:    "operations",
+			isValid: true,
+			api: `package api
+
+import (
+	"net/http"
+
+	"loadertest/models"
+)
+
+type UserService struct{}
+
+// +openapi:operation:path="/users/{id}"
+// +openapi:operation:method="get"
+// +openapi:operation:tags=["users"]
+// +openapi:operation:response="200:models.User"
+// +openapi:operation:response="404:models.Error"
+func (s *UserService) Get(
+	// +openapi:operation:in="path"
+	id string,
+	// +openapi:operation:in="query"
+	// +openapi:operation:description="include the address"
+	verbose bool,
+) (*models.User, error) {
+	return nil, nil
+}
+
+// +openapi:operation:path="/users"
+// +openapi:operation:method="post"
+// +openapi:operation:operationId="createUser"
+// +openapi:operation:requestBody="[]models.User"
+// +openapi:operation:parameter="header:X-Request-ID:string"
+// +openapi:operation:response="204"
+func Create(w http.ResponseWriter, r *http.Request) {}
+`,
+			want: Document{
+				OpenAPI:           _openAPIVersion,
+				Info:              DocumentInfo{Title: "API", Version: "0.0.0"},
+				JSONSchemaDialect: "https://json-schema.org/draft/2020-12/schema",
+				Paths: map[string]PathItem{
+					"/users/{id}": {
+						"get": {
+							OperationID: "userServiceGet",
+							Tags:        []string{"users"},
+							Parameters: []*Parameter{
+								{Name: "id", In: "path", Required: true, Schema: &Schema{Type: stringType}},
+								{Name: "verbose", In: "query", Desc: "include the address", Schema: &Schema{Type: booleanType}},
+							},
+							Responses: map[string]*Response{
+								"200": {
+									Desc:    "OK",
+									Content: map[string]*MediaType{_mediaTypeJSON: {Schema: &Schema{Ref: "#/components/schemas/User"}}},
+								},
+								"404": {
+									Desc:    "Not Found",
+									Content: map[string]*MediaType{_mediaTypeJSON: {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+								},
+							},
+						},
+					},
+					"/users": {
+						"post": {
+							OperationID: "createUser",
+							Parameters: []*Parameter{
+								{Name: "X-Request-ID", In: "header", Schema: &Schema{Type: stringType}},
+							},
+							RequestBody: &RequestBody{
+								Required: true,
+								Content: map[string]*MediaType{_mediaTypeJSON: {Schema: &Schema{
+									Type:  arrayType,
+									Items: &Schema{Ref: "#/components/schemas/User"},
+								}}},
+							},
+							Responses: map[string]*Response{
+								"204": {Desc: "No Content"},
+							},
+						},
+					},
+				},
+				Components: Components{
+					Schemas: map[string]*Schema{
+						"User": {
+							Title: "user",
+							Type:  objectType,
+							Properties: map[string]*Schema{
+								"name":    {Type: stringType},
+								"address": {Ref: "#/components/schemas/Address"},
+							},
+						},
+						"Address": {
+							Type:       objectType,
+							Properties: map[string]*Schema{"street": {Type: stringType}},
+						},
+						"Error": {
+							Type:       objectType,
+							Properties: map[string]*Schema{"msg": {Type: stringType}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "path parameter not defined",
+			isValid: false,
+			api: `package api
+
+// +openapi:operation:path="/users/{id}"
+// +openapi:operation:method="get"
+func Get() {}
+`,
+		},
+		{
+			name:    "duplicate operation",
+			isValid: false,
+			api: `package api
+
+// +openapi:operation:path="/users"
+// +openapi:operation:method="get"
+func List() {}
+
+// +openapi:operation:path="/users"
+// +openapi:operation:method="GET"
+func ListAll() {}
+`,
+		},
+		{
+			name:    "invalid status code",
+			isValid: false,
+			api: `package api
+
+// +openapi:operation:path="/users"
+// +openapi:operation:method="get"
+// +openapi:operation:response="600"
+func List() {}
+`,
+		},
+		{
+			name:    "unknown type",
+			isValid: false,
+			api: `package api
+
+// +openapi:operation:path="/users"
+// +openapi:operation:method="post"
+// +openapi:operation:requestBody="User"
+func Create() {}
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			files := map[string]string{
+				"models/models.go": models,
+				"api/api.go":       tc.api,
+			}
+			artifacts, err := loadertest.Generate(t, New, files, nil)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			names := make([]string, 0, len(artifacts))
+			for _, artifact := range artifacts {
+				names = append(names, artifact.Name)
+			}
+			if !slices.Contains(names, _documentName+".json") {
+				t.Fatalf("document not generated. artifacts: %v", names)
+			}
+			doc := artifacts[len(artifacts)-1]
+			var got Document
+			if err := json.NewDecoder(doc.Data).Decode(&got); err != nil {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if gotKeys, wantKeys := slices.Sorted(maps.Keys(got.Components.Schemas)), slices.Sorted(maps.Keys(tc.want.Components.Schemas)); !slices.Equal(gotKeys, wantKeys) {
+				t.Errorf("components are not equal. want: %v; got: %v", wantKeys, gotKeys)
+			}
+			wantJSON := mustMarshal(tc.want)
+			gotJSON := mustMarshal(got)
+			if !slices.Equal(wantJSON, gotJSON) {
+				t.Errorf("documents are not equal.\n want: %s\n got: %s", wantJSON, gotJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// OutputFormat is the encoding of the generated artifacts.
+type OutputFormat string
+
+const (
+	JSONOutput OutputFormat = "json"
+	YAMLOutput OutputFormat = "yaml"
+)
+
+// KeyOrder defines the order of the keys of the generated artifacts.
+type KeyOrder string
+
+const (
+	// KeywordOrder orders the keys by the keywords of the specification e.g.
+	// `$id` and `$schema` come first.
+	KeywordOrder KeyOrder = "keyword"
+	// AlphabeticalOrder orders all the keys alphabetically.
+	AlphabeticalOrder KeyOrder = "alphabetical"
+)
+
+// _defaultYAMLIndent is the indentation of YAML artifacts if no indentation is
+// configured because YAML cannot be encoded without indentation.
+const _defaultYAMLIndent = 2
+
+// ext returns the file extension of the artifacts including the dot.
+func (o outputConfig) ext() string {
+	return "." + string(o.Format)
+}
+
+// encode writes `v` to `w` in the configured format, indentation and key
+// order.
+func (o outputConfig) encode(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if o.Order == AlphabeticalOrder {
+		data, err = sortKeys(data)
+		if err != nil {
+			return err
+		}
+	}
+	if o.Format == YAMLOutput {
+		return o.encodeYAML(w, data)
+	}
+	if o.Indent > 0 {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", strings.Repeat(" ", o.Indent)); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// encodeYAML converts the JSON `data` to YAML preserving the order of the
+// keys.
+func (o outputConfig) encodeYAML(w io.Writer, data []byte) error {
+	var ordered any
+	if err := yaml.UnmarshalWithOptions(data, &ordered, yaml.UseOrderedMap()); err != nil {
+		return err
+	}
+	indent := o.Indent
+	if indent <= 0 {
+		indent = _defaultYAMLIndent
+	}
+	out, err := yaml.MarshalWithOptions(ordered, yaml.Indent(indent), yaml.IndentSequence(true))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// sortKeys sorts the keys of all the objects of the JSON `data`
+// alphabetically. Numbers are kept as they are.
+func sortKeys(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	// maps are encoded with sorted keys
+	return json.Marshal(v)
+}
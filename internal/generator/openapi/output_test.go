@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"io"
+	"testing"
+
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+func TestGenerator_Output(t *testing.T) {
+	files := map[string]string{
+		"api/user.go": `package api
+
+// +openapi:schema:title="user"
+type User struct {
+	// +openapi:schema:maximum=0.5
+	Score float64
+	Tags  []string
+}
+`,
+	}
+	tests := []struct {
+		name     string
+		output   map[string]any
+		isValid  bool
+		filename string
+		want     string
+	}{
+		{
+			name:     "compact json",
+			output:   nil,
+			isValid:  true,
+			filename: "user.json",
+			want:     `{"$id":"user.json","$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","title":"user","properties":{"score":{"type":"number","maximum":0.5},"tags":{"type":"array","items":{"type":"string"}}}}` + "\n",
+		},
+		{
+			name:     "indented alphabetical json",
+			output:   map[string]any{"indent": 2, "order": "alphabetical"},
+			isValid:  true,
+			filename: "user.json",
+			want: `{
+  "$id": "user.json",
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "score": {
+      "maximum": 0.5,
+      "type": "number"
+    },
+    "tags": {
+      "items": {
+        "type": "string"
+      },
+      "type": "array"
+    }
+  },
+  "title": "user",
+  "type": "object"
+}
+`,
+		},
+		{
+			name:     "yaml",
+			output:   map[string]any{"format": "yaml"},
+			isValid:  true,
+			filename: "user.yaml",
+			want: `$id: user.yaml
+$schema: https://json-schema.org/draft/2020-12/schema
+type: object
+title: user
+properties:
+  score:
+    type: number
+    maximum: 0.5
+  tags:
+    type: array
+    items:
+      type: string
+`,
+		},
+		{
+			name:    "unknown format",
+			output:  map[string]any{"format": "toml"},
+			isValid: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var cfg map[string]any
+			if tc.output != nil {
+				cfg = map[string]any{"output": tc.output}
+			}
+			artifacts, err := loadertest.Generate(t, New, files, cfg)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			if len(artifacts) != 1 || artifacts[0].Name != tc.filename {
+				t.Fatalf("expected a single artifact named %s. got: %d artifacts", tc.filename, len(artifacts))
+			}
+			got, err := io.ReadAll(artifacts[0].Data)
+			if err != nil {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("output is not equal.\n want: %s\n got: %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerator_InvalidConfig(t *testing.T) {
+	gen, err := New()
+	if err != nil {
+		t.Fatalf("err occurred: %v", err)
+	}
+	// the config is validated by the generator itself without the manager.
+	_, err = gen.Generate(nil, map[string]any{"output": map[string]any{"format": "toml"}})
+	if err == nil {
+		t.Fatalf("expected an error but none occured")
+	}
+	t.Logf("expected error occured: %s", err)
+}
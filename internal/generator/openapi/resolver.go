@@ -0,0 +1,911 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"golang.org/x/tools/go/packages"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+)
+
+// Dependencies defines how structs without schema markers are handled if they
+// are referenced by a schema.
+type Dependencies string
+
+const (
+	// IncludeDependencies generates a schema for every referenced struct
+	// without schema markers.
+	IncludeDependencies Dependencies = "include"
+	// ErrorDependencies fails the generation if a struct without schema
+	// markers is referenced.
+	ErrorDependencies Dependencies = "error"
+)
+
+// typeRef is a named type for which a schema will be generated. It is either a
+// struct, an enum or a named non-struct type or alias with schema markers.
+type typeRef struct {
+	obj *types.TypeName
+	// pkg is the package in which the type is declared. It is nil if the
+	// package is not part of the loaded project.
+	pkg  *packages.Package
+	info *infov1.StructInfo
+	// enum is set if the type is an enum instead of a struct.
+	enum *enumRef
+	// named is set if the type is a named non-struct type e.g. `type Email
+	// string` or an alias with schema markers instead of a struct.
+	named infov1.Info
+	// isDependency reports whether the type has no schema markers and is only
+	// generated because it is referenced by another schema.
+	isDependency bool
+	// name is the unique name of the schema in the project.
+	name string
+	// isRecursive reports whether the struct references itself directly or
+	// through other structs. Recursive structs are always referenced by name
+	// because their schema cannot be inlined.
+	isRecursive bool
+	// isInlined reports whether the struct has no schema markers and every
+	// field referencing it inlines its schema. Such a struct has no schema of
+	// its own.
+	isInlined bool
+}
+
+// canInline reports whether the schema of the struct can be inlined. Recursive
+// structs would be inlined endlessly and generic structs have no schema
+// without their type arguments.
+func (t *typeRef) canInline() bool {
+	if t.isRecursive || t.enum != nil || t.named != nil {
+		return false
+	}
+	named, isNamed := t.obj.Type().(*types.Named)
+	return isNamed && named.TypeParams().Len() == 0
+}
+
+// usage is the field `field` of `by` referencing the struct `obj`. The field
+// is nil if the struct is referenced by a named non-struct type.
+type usage struct {
+	by    *typeRef
+	field *types.Var
+	obj   *types.TypeName
+}
+
+// enumRef is a named basic type e.g. `type Status string` with constants of
+// the type declared in the same package.
+type enumRef struct {
+	info   *infov1.NamedInfo
+	values []*enumValue
+}
+
+type enumValue struct {
+	value any
+	// desc is the description of the value defined by the constant.
+	desc string
+}
+
+// ifaceRef is an interface with a discriminator marker. A field of the
+// interface type is represented by a `oneOf` of all the implementing structs.
+type ifaceRef struct {
+	obj  *types.TypeName
+	info *infov1.IfaceInfo
+	// discriminator is the name of the property which identifies the
+	// implementation.
+	discriminator string
+	impls         []*implRef
+}
+
+// implRef is a struct implementing an interface with a discriminator.
+type implRef struct {
+	ref   *typeRef
+	iface *ifaceRef
+	// value of the discriminator property identifying the struct.
+	value string
+}
+
+// resolver resolves the named types referenced by schemas through the whole
+// project. It assures that every referenced struct has a schema and that the
+// names of the schemas are unique even if the same type name is used in
+// different packages.
+type resolver struct {
+	cfg *config
+
+	// refPrefix is prepended to the name of a schema to reference it if the
+	// schemas are embedded into a single document e.g.
+	// `#/components/schemas/`. If empty the schemas are referenced by their
+	// `$id`.
+	refPrefix string
+
+	// structs contains every struct of the project.
+	structs map[*types.TypeName]*typeRef
+	// enums contains every enum of the project.
+	enums map[*types.TypeName]*typeRef
+	// named contains every named non-struct type and alias of the project
+	// with schema markers which is not an enum.
+	named map[*types.TypeName]*typeRef
+	// refs contains every struct for which a schema will be generated in the
+	// order of discovery.
+	refs []*typeRef
+	// resolved indexes refs by the type name.
+	resolved map[*types.TypeName]*typeRef
+	// ifaces contains every interface with a discriminator marker in the order
+	// of declaration.
+	ifaces []*ifaceRef
+	// types maps the fully qualified name of well-known types to their
+	// schema.
+	types map[string]*Schema
+	// edges contains the named types referenced by a named type.
+	edges map[*types.TypeName][]*types.TypeName
+	// usages contains every field referencing a struct.
+	usages []usage
+	// byName contains the structs which are referenced by name at least once
+	// e.g. by an operation or as the implementation of an interface.
+	byName map[*types.TypeName]bool
+
+	// inline returns the inlined schema of the struct referenced by `ref`. It
+	// is only set while building the schema of a field inlining the structs
+	// it references.
+	inline func(ref *typeRef) (Schema, error)
+}
+
+// newResolver returns a resolver for all types for which `isRoot` reports
+// true and every type reachable from them. `refs` are types referenced
+// outside of schemas e.g. by operations. The structs referenced by them are
+// always included.
+func newResolver(
+	proj infov1.Project,
+	cfg *config,
+	isRoot func(obj *types.TypeName, info infov1.Info) bool,
+	refs ...types.Type,
+) (*resolver, error) {
+	r := &resolver{
+		cfg:      cfg,
+		structs:  make(map[*types.TypeName]*typeRef),
+		enums:    make(map[*types.TypeName]*typeRef),
+		named:    make(map[*types.TypeName]*typeRef),
+		resolved: make(map[*types.TypeName]*typeRef),
+		edges:    make(map[*types.TypeName][]*types.TypeName),
+		byName:   make(map[*types.TypeName]bool),
+	}
+	table, err := newTypeTable(cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.types = table
+	ordered := make([]*typeRef, 0)
+	for _, pkg := range infov1.Packages(proj) {
+		for obj, info := range infov1.ByPos(pkg.Fset, proj[pkg].Structs) {
+			ref := &typeRef{obj: obj.(*types.TypeName), pkg: pkg, info: info}
+			r.structs[ref.obj] = ref
+			ordered = append(ordered, ref)
+		}
+	}
+	for _, pkg := range infov1.Packages(proj) {
+		for obj, info := range infov1.ByPos(pkg.Fset, proj[pkg].Named) {
+			enum := newEnumRef(obj.(*types.TypeName), info, proj[pkg])
+			if enum != nil {
+				r.enums[obj.(*types.TypeName)] = &typeRef{obj: obj.(*types.TypeName), pkg: pkg, enum: enum}
+			}
+		}
+		for obj, info := range infov1.ByPos(pkg.Fset, namedTypesOf(proj[pkg])) {
+			_, isEnum := r.enums[obj.(*types.TypeName)]
+			if isEnum || !hasSchemaOpts(info) {
+				continue
+			}
+			r.named[obj.(*types.TypeName)] = &typeRef{obj: obj.(*types.TypeName), pkg: pkg, named: info}
+		}
+	}
+	for _, pkg := range infov1.Packages(proj) {
+		for obj, info := range infov1.ByPos(pkg.Fset, proj[pkg].Ifaces) {
+			iface, err := newIfaceRef(obj.(*types.TypeName), info, ordered)
+			if err != nil {
+				return nil, err
+			}
+			if iface != nil {
+				r.ifaces = append(r.ifaces, iface)
+			}
+		}
+	}
+	for _, pkg := range infov1.Packages(proj) {
+		for obj, info := range infov1.ByPos(pkg.Fset, proj[pkg].Structs) {
+			if !isRoot(obj.(*types.TypeName), info) {
+				continue
+			}
+			r.add(r.structs[obj.(*types.TypeName)])
+		}
+		for obj, info := range infov1.ByPos(pkg.Fset, namedTypesOf(proj[pkg])) {
+			if !isRoot(obj.(*types.TypeName), info) {
+				continue
+			}
+			if ref, isEnum := r.enums[obj.(*types.TypeName)]; isEnum {
+				r.add(ref)
+				continue
+			}
+			if ref, isNamed := r.named[obj.(*types.TypeName)]; isNamed {
+				r.add(ref)
+			}
+		}
+	}
+	for _, typ := range refs {
+		if ref := r.namedRefOf(typ); ref != nil {
+			if _, isResolved := r.resolved[ref.obj]; !isResolved {
+				r.add(ref)
+			}
+			continue
+		}
+		r.includeEnum(typ)
+		named := namedStructOf(typ)
+		if named == nil || r.isWellKnown(named) {
+			continue
+		}
+		if isInstantiated(named) {
+			return nil, fmt.Errorf(
+				"instantiated generic struct `%s` is not supported. Use a struct without type parameters instead",
+				named,
+			)
+		}
+		obj := named.Origin().Obj()
+		r.byName[obj] = true
+		if _, isResolved := r.resolved[obj]; isResolved {
+			continue
+		}
+		ref, isLoaded := r.structs[obj]
+		if !isLoaded {
+			ref = &typeRef{obj: obj, info: structInfoOf(obj)}
+		}
+		ref.isDependency = true
+		r.add(ref)
+	}
+	// breadth first search of all types reachable from the roots
+	for i := 0; i < len(r.refs); i++ {
+		ref := r.refs[i]
+		if ref.enum != nil {
+			continue
+		}
+		if ref.named != nil {
+			if err := r.referenceType(definitionOf(ref.obj), ref, nil, make(map[*types.TypeName]bool)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for field := range ref.obj.Type().Underlying().(*types.Struct).Fields() {
+			if field.Embedded() || !field.Exported() {
+				continue
+			}
+			if err := r.referenceType(field.Type(), ref, field, make(map[*types.TypeName]bool)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	r.detectCycles()
+	r.detectInlined()
+	return r, r.assignNames()
+}
+
+// newIfaceRef returns the reference for the interface `obj` including every
+// struct of `structs` implementing it. If the interface has no discriminator
+// marker nil will be returned.
+func newIfaceRef(obj *types.TypeName, info *infov1.IfaceInfo, structs []*typeRef) (*ifaceRef, error) {
+	opts, isDefined := info.Options().Get("openapi:schema:discriminator")
+	if !isDefined {
+		return nil, nil
+	}
+	iface := &ifaceRef{
+		obj:           obj,
+		info:          info,
+		discriminator: string(opts[0].(Discriminator)),
+	}
+	if iface.discriminator == "" {
+		return nil, fmt.Errorf("discriminator of interface `%s` cannot be empty", qualifiedName(obj))
+	}
+	methods := obj.Type().Underlying().(*types.Interface)
+	values := make(map[string]*typeRef)
+	for _, ref := range structs {
+		// generic structs cannot implement an interface without being
+		// instantiated.
+		if named, isNamed := ref.obj.Type().(*types.Named); isNamed && named.TypeParams().Len() > 0 {
+			continue
+		}
+		typ := ref.obj.Type()
+		if !types.Implements(typ, methods) && !types.Implements(types.NewPointer(typ), methods) {
+			continue
+		}
+		value := ref.obj.Name()
+		if opts, isDefined := ref.info.Options().Get("openapi:schema:discriminatorValue"); isDefined {
+			value = string(opts[0].(DiscriminatorValue))
+		}
+		if other, exists := values[value]; exists {
+			return nil, fmt.Errorf(
+				"discriminator value `%s` of interface `%s` is used by `%s` and `%s`",
+				value,
+				qualifiedName(obj),
+				qualifiedName(other.obj),
+				qualifiedName(ref.obj),
+			)
+		}
+		values[value] = ref
+		iface.impls = append(iface.impls, &implRef{ref: ref, iface: iface, value: value})
+	}
+	if len(iface.impls) == 0 {
+		return nil, fmt.Errorf("interface `%s` with a discriminator has no implementing structs", qualifiedName(obj))
+	}
+	return iface, nil
+}
+
+// newEnumRef returns the reference for the named type `obj` if it is an enum.
+// A named type is an enum if its underlying type is a string or number and at
+// least one exported constant of the type is declared in the same package.
+// Named types marked with +openapi:schema:exclude are never enums. If the named
+// type is not an enum nil will be returned.
+func newEnumRef(obj *types.TypeName, info *infov1.NamedInfo, pkg *infov1.Information) *enumRef {
+	basic, isBasic := obj.Type().Underlying().(*types.Basic)
+	if !isBasic || basic.Info()&(types.IsString|types.IsInteger|types.IsFloat) == 0 {
+		return nil
+	}
+	if opts, isDefined := info.Options().Get("openapi:schema:exclude"); isDefined && bool(opts[0].(Exclude)) {
+		return nil
+	}
+	enum := &enumRef{info: info}
+	for c, cinfo := range infov1.ByPos(pkg.Fset, pkg.Consts) {
+		if !c.Exported() || !types.Identical(c.Type(), obj.Type()) {
+			continue
+		}
+		opts := cinfo.Options()
+		if opts, isDefined := opts.Get("openapi:schema:exclude"); isDefined && bool(opts[0].(Exclude)) {
+			continue
+		}
+		value := enumValue{value: constValueOf(c.(*types.Const).Val())}
+		if desc, isDefined := opts.Get("openapi:schema:description"); isDefined {
+			value.desc = string(desc[0].(Description))
+		}
+		enum.values = append(enum.values, &value)
+	}
+	if len(enum.values) == 0 {
+		return nil
+	}
+	return enum
+}
+
+// constValueOf returns the go value of the constant value `val`.
+func constValueOf(val constant.Value) any {
+	switch val.Kind() {
+	case constant.String:
+		return constant.StringVal(val)
+	case constant.Int:
+		if v, isExact := constant.Int64Val(val); isExact {
+			return v
+		}
+		v, _ := constant.Uint64Val(val)
+		return v
+	case constant.Float:
+		v, _ := constant.Float64Val(val)
+		return v
+	}
+	return val.ExactString()
+}
+
+// includeEnum includes the enum referenced by `typ` if any. Enums are detected
+// automatically and have no schema markers so they are always included.
+func (r *resolver) includeEnum(typ types.Type) {
+	enum := r.enumOf(typ)
+	if enum == nil {
+		return
+	}
+	if _, isResolved := r.resolved[enum.obj]; isResolved {
+		return
+	}
+	enum.isDependency = true
+	r.add(enum)
+}
+
+// enumOf returns the enum referenced by `typ`. If no enum is referenced nil
+// will be returned.
+func (r *resolver) enumOf(typ types.Type) *typeRef {
+	named := namedOf(typ)
+	if named == nil {
+		return nil
+	}
+	return r.enums[named.Origin().Obj()]
+}
+
+// referenceType resolves the named types referenced by `typ` which is the
+// type of the field `field` of `by`. The underlying type of a named type
+// without a schema of its own e.g. `type Users []User` is inlined and resolved
+// instead. `inlined` contains the named types which have already been inlined.
+func (r *resolver) referenceType(typ types.Type, by *typeRef, field *types.Var, inlined map[*types.TypeName]bool) error {
+	switch t := typ.(type) {
+	case *types.Alias:
+		if _, isNamed := r.named[t.Origin().Obj()]; isNamed {
+			return r.reference(t.Origin().Obj(), by, field)
+		}
+		return r.referenceType(t.Rhs(), by, field, inlined)
+	case *types.Pointer:
+		return r.referenceType(t.Elem(), by, field, inlined)
+	case *types.Slice:
+		return r.referenceType(t.Elem(), by, field, inlined)
+	case *types.Array:
+		return r.referenceType(t.Elem(), by, field, inlined)
+	case *types.Map:
+		return r.referenceType(t.Elem(), by, field, inlined)
+	case *types.Named:
+		return r.referenceNamed(t, by, field, inlined)
+	}
+	return nil
+}
+
+func (r *resolver) referenceNamed(named *types.Named, by *typeRef, field *types.Var, inlined map[*types.TypeName]bool) error {
+	obj := named.Origin().Obj()
+	if _, isNamed := r.named[obj]; isNamed {
+		return r.reference(obj, by, field)
+	}
+	if r.isWellKnown(named) {
+		return nil
+	}
+	if _, isEnum := r.enums[obj]; isEnum {
+		r.includeEnum(named)
+		return nil
+	}
+	switch named.Underlying().(type) {
+	case *types.Struct:
+		// the schema of a generic struct describes its type parameters as any
+		// value which would silently drop the type arguments.
+		if isInstantiated(named) {
+			return fmt.Errorf(
+				"instantiated generic struct `%s` referenced by %s is not supported. Use a struct without type parameters instead",
+				named,
+				referrerOf(by, field),
+			)
+		}
+		r.usages = append(r.usages, usage{by: by, field: field, obj: obj})
+		return r.reference(obj, by, field)
+	case *types.Interface:
+		iface := r.ifaceOf(named)
+		if iface == nil {
+			return nil
+		}
+		// every implementation of the interface is referenced by the field.
+		for _, impl := range iface.impls {
+			r.byName[impl.ref.obj] = true
+			if err := r.reference(impl.ref.obj, by, field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// the underlying type of a named type can only reference itself again
+	// if the type is recursive e.g. `type Tree map[string]Tree`.
+	if inlined[obj] {
+		return fmt.Errorf(
+			"type `%s` referenced by %s is recursive and cannot be inlined. Add a schema marker to it to generate a schema of its own",
+			qualifiedName(obj),
+			referrerOf(by, field),
+		)
+	}
+	inlined[obj] = true
+	return r.referenceType(named.Underlying(), by, field, inlined)
+}
+
+// reference resolves the named type `obj` which is referenced by the field
+// `field` of `by`.
+func (r *resolver) reference(obj *types.TypeName, by *typeRef, field *types.Var) error {
+	r.edges[by.obj] = append(r.edges[by.obj], obj)
+	if _, isResolved := r.resolved[obj]; isResolved {
+		return nil
+	}
+	if ref, isNamed := r.named[obj]; isNamed {
+		r.add(ref)
+		return nil
+	}
+	dep, err := r.dependency(obj, by, field)
+	if err != nil {
+		return err
+	}
+	r.add(dep)
+	return nil
+}
+
+// detectCycles marks every type which is part of a cycle of references as
+// recursive e.g. `type Node struct { Children []*Node }`. The cycles are the
+// strongly connected components of the references found using Tarjan's
+// algorithm.
+func (r *resolver) detectCycles() {
+	var (
+		index   = make(map[*types.TypeName]int, len(r.refs))
+		lowlink = make(map[*types.TypeName]int, len(r.refs))
+		onStack = make(map[*types.TypeName]bool, len(r.refs))
+		stack   = make([]*types.TypeName, 0, len(r.refs))
+		visit   func(obj *types.TypeName)
+	)
+	visit = func(obj *types.TypeName) {
+		index[obj] = len(index)
+		lowlink[obj] = index[obj]
+		stack = append(stack, obj)
+		onStack[obj] = true
+		for _, to := range r.edges[obj] {
+			if _, isVisited := index[to]; !isVisited {
+				visit(to)
+				lowlink[obj] = min(lowlink[obj], lowlink[to])
+			} else if onStack[to] {
+				lowlink[obj] = min(lowlink[obj], index[to])
+			}
+		}
+		if lowlink[obj] != index[obj] {
+			return
+		}
+		component := make([]*types.TypeName, 0)
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == obj {
+				break
+			}
+		}
+		isCycle := len(component) > 1 || slices.Contains(r.edges[obj], obj)
+		for _, member := range component {
+			if ref, isResolved := r.resolved[member]; isResolved {
+				ref.isRecursive = isCycle
+			}
+		}
+	}
+	for _, ref := range r.refs {
+		if _, isVisited := index[ref.obj]; !isVisited {
+			visit(ref.obj)
+		}
+	}
+}
+
+// detectInlined marks every struct without schema markers as inlined which is
+// only referenced by fields inlining its schema.
+func (r *resolver) detectInlined() {
+	for _, u := range r.usages {
+		ref := r.resolved[u.obj]
+		if !ref.canInline() || !isInline(fieldInfoOf(u.by, u.field), r.cfg) {
+			r.byName[u.obj] = true
+		}
+	}
+	for _, ref := range r.refs {
+		ref.isInlined = ref.isDependency && ref.canInline() && !r.byName[ref.obj]
+	}
+}
+
+// ifaceOf returns the interface with a discriminator referenced by `typ`. If
+// no such interface is referenced nil will be returned.
+func (r *resolver) ifaceOf(typ types.Type) *ifaceRef {
+	named := namedOf(typ)
+	if named == nil {
+		return nil
+	}
+	for _, iface := range r.ifaces {
+		if iface.obj == named.Origin().Obj() {
+			return iface
+		}
+	}
+	return nil
+}
+
+// discriminatorsOf returns the interfaces with a discriminator implemented by
+// the struct `obj` and the value identifying the struct.
+func (r *resolver) discriminatorsOf(obj types.Object) []*implRef {
+	impls := make([]*implRef, 0)
+	for _, iface := range r.ifaces {
+		for _, impl := range iface.impls {
+			if impl.ref.obj == obj {
+				impls = append(impls, impl)
+			}
+		}
+	}
+	return impls
+}
+
+// embedded returns a copy of the resolver which references the schemas by
+// their name prefixed with `prefix` instead of their `$id`.
+func (r *resolver) embedded(prefix string) *resolver {
+	embedded := *r
+	embedded.refPrefix = prefix
+	return &embedded
+}
+
+// inlining returns a copy of the resolver which inlines the schemas of the
+// referenced structs using `inline` instead of referencing them.
+func (r *resolver) inlining(inline func(ref *typeRef) (Schema, error)) *resolver {
+	inlining := *r
+	inlining.inline = inline
+	return &inlining
+}
+
+// schemas returns all the references which have a schema of their own.
+func (r *resolver) schemas() []*typeRef {
+	refs := make([]*typeRef, 0, len(r.refs))
+	for _, ref := range r.refs {
+		if !ref.isInlined {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// isEmbedded reports whether the schemas are embedded into a single document.
+func (r *resolver) isEmbedded() bool {
+	return r.refPrefix != ""
+}
+
+func (r *resolver) add(ref *typeRef) {
+	r.resolved[ref.obj] = ref
+	r.refs = append(r.refs, ref)
+}
+
+// dependency returns the reference for the struct `obj` which has no schema
+// markers but is referenced by the field `field` of `by`.
+func (r *resolver) dependency(obj *types.TypeName, by *typeRef, field *types.Var) (*typeRef, error) {
+	if r.cfg.Schema.Dependencies == ErrorDependencies {
+		return nil, fmt.Errorf(
+			"struct `%s` referenced by %s has no schema. Add a title or description marker to it or set `schema.dependencies` to `%s`",
+			qualifiedName(obj),
+			referrerOf(by, field),
+			IncludeDependencies,
+		)
+	}
+	ref, isLoaded := r.structs[obj]
+	if !isLoaded {
+		ref = &typeRef{obj: obj, info: structInfoOf(obj)}
+	}
+	ref.isDependency = true
+	return ref, nil
+}
+
+// assignNames assigns an unique name to every reference. The name of the type
+// is used if it is unique. Otherwise the name will be qualified with the name
+// of the package and if that's not sufficient with the path of the package.
+func (r *resolver) assignNames() error {
+	qualifiers := []func(pkg *types.Package) string{
+		(*types.Package).Name,
+		(*types.Package).Path,
+	}
+	for _, ref := range r.refs {
+		ref.name = ref.obj.Name()
+	}
+	for _, qualifier := range qualifiers {
+		collisions := r.collisions()
+		if len(collisions) == 0 {
+			return nil
+		}
+		for _, ref := range collisions {
+			ref.name = strcase.ToCamel(qualifier(ref.obj.Pkg())) + ref.obj.Name()
+		}
+	}
+	collisions := r.collisions()
+	if len(collisions) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(collisions))
+	for _, ref := range collisions {
+		names = append(names, qualifiedName(ref.obj))
+	}
+	return fmt.Errorf("schema names are not unique: %s", strings.Join(names, ", "))
+}
+
+// collisions returns all the references which would result in the same
+// filename.
+func (r *resolver) collisions() []*typeRef {
+	byFilename := make(map[string][]*typeRef, len(r.refs))
+	for _, ref := range r.refs {
+		filename := r.cfg.Schema.Formats.Filename.Format(ref.name)
+		byFilename[filename] = append(byFilename[filename], ref)
+	}
+	collisions := make([]*typeRef, 0)
+	for _, ref := range r.refs {
+		filename := r.cfg.Schema.Formats.Filename.Format(ref.name)
+		if len(byFilename[filename]) > 1 {
+			collisions = append(collisions, ref)
+		}
+	}
+	return collisions
+}
+
+// dependencies returns all the structs without schema markers which have to be
+// generated because they are referenced.
+func (r *resolver) dependencies() []*typeRef {
+	deps := make([]*typeRef, 0, len(r.refs))
+	for _, ref := range r.schemas() {
+		if ref.isDependency {
+			deps = append(deps, ref)
+		}
+	}
+	return deps
+}
+
+// ref returns the reference for the named struct type `named`.
+func (r *resolver) ref(named *types.Named) (*typeRef, error) {
+	obj := named.Origin().Obj()
+	ref, isResolved := r.resolved[obj]
+	if isResolved {
+		return ref, nil
+	}
+	return nil, fmt.Errorf("struct is not resolvable: %s", qualifiedName(obj))
+}
+
+// lookup returns the reference for the named type or alias `obj`.
+func (r *resolver) lookup(obj types.Object) (*typeRef, error) {
+	switch t := obj.Type().(type) {
+	case *types.Named:
+		return r.ref(t)
+	case *types.Alias:
+		ref, isResolved := r.resolved[t.Origin().Obj()]
+		if isResolved {
+			return ref, nil
+		}
+		return nil, fmt.Errorf("alias is not resolvable: %s", qualifiedName(t.Obj()))
+	}
+	return nil, fmt.Errorf("object is not a named type: %s", obj.Name())
+}
+
+// namedRef returns the reference for the named type or alias `typ` if it has
+// a schema of its own. Otherwise nil will be returned.
+func (r *resolver) namedRef(typ types.Type) *typeRef {
+	var obj *types.TypeName
+	switch t := typ.(type) {
+	case *types.Named:
+		obj = t.Origin().Obj()
+	case *types.Alias:
+		obj = t.Origin().Obj()
+	default:
+		return nil
+	}
+	ref, isResolved := r.resolved[obj]
+	if !isResolved || ref.named == nil {
+		return nil
+	}
+	return ref
+}
+
+// namedRefOf returns the named type or alias with schema markers referenced
+// by `typ` e.g. the element type of a slice. If no such type is referenced nil
+// will be returned.
+func (r *resolver) namedRefOf(typ types.Type) *typeRef {
+	switch t := typ.(type) {
+	case *types.Named:
+		return r.named[t.Origin().Obj()]
+	case *types.Alias:
+		if ref, isNamed := r.named[t.Origin().Obj()]; isNamed {
+			return ref
+		}
+		return r.namedRefOf(t.Rhs())
+	case *types.Pointer:
+		return r.namedRefOf(t.Elem())
+	case *types.Slice:
+		return r.namedRefOf(t.Elem())
+	case *types.Array:
+		return r.namedRefOf(t.Elem())
+	case *types.Map:
+		return r.namedRefOf(t.Elem())
+	}
+	return nil
+}
+
+// id returns the `$id` of the schema generated for `obj`.
+func (r *resolver) id(obj types.Object) (string, error) {
+	ref, err := r.lookup(obj)
+	if err != nil {
+		return "", err
+	}
+	return id(ref.name, r.cfg)
+}
+
+// refOf returns the value of `$ref` to reference the schema of `obj`.
+func (r *resolver) refOf(obj types.Object) (string, error) {
+	if !r.isEmbedded() {
+		return r.id(obj)
+	}
+	ref, err := r.lookup(obj)
+	if err != nil {
+		return "", err
+	}
+	return r.refPrefix + ref.name, nil
+}
+
+// namedStructOf returns the named struct referenced by `typ` for which a `$ref`
+// will be created while building the schema of `typ`. If no struct is
+// referenced nil will be returned.
+func namedStructOf(typ types.Type) *types.Named {
+	named := namedOf(typ)
+	if named == nil {
+		return nil
+	}
+	if _, isStruct := named.Underlying().(*types.Struct); isStruct {
+		return named
+	}
+	return nil
+}
+
+// namedOf returns the named type referenced by `typ` e.g. the element type of
+// a slice. If no named type is referenced nil will be returned.
+func namedOf(typ types.Type) *types.Named {
+	switch t := typ.(type) {
+	case *types.Named:
+		return t
+	case *types.Alias:
+		return namedOf(t.Rhs())
+	case *types.Pointer:
+		return namedOf(t.Elem())
+	case *types.Slice:
+		return namedOf(t.Elem())
+	case *types.Array:
+		return namedOf(t.Elem())
+	case *types.Map:
+		return namedOf(t.Elem())
+	}
+	return nil
+}
+
+// isInstantiated reports whether `named` is a generic type instantiated with
+// at least one type argument which is not a type parameter e.g. `Page[User]`.
+// The reference `Tree[T]` inside of `type Tree[T any]` is not instantiated.
+func isInstantiated(named *types.Named) bool {
+	for typ := range named.TypeArgs().Types() {
+		if _, isParam := typ.(*types.TypeParam); !isParam {
+			return true
+		}
+	}
+	return false
+}
+
+// definitionOf returns the type defining the named type or alias `obj` e.g.
+// `[]string` of `type Tags []string`.
+func definitionOf(obj *types.TypeName) types.Type {
+	if alias, isAlias := obj.Type().(*types.Alias); isAlias {
+		return alias.Rhs()
+	}
+	return obj.Type().Underlying()
+}
+
+// fieldInfoOf returns the information of the field `field` of `by`. If the
+// struct is not referenced by a field nil will be returned.
+func fieldInfoOf(by *typeRef, field *types.Var) *infov1.FieldInfo {
+	if field == nil || by.info == nil {
+		return nil
+	}
+	return by.info.Fields[field]
+}
+
+// referrerOf describes the field `field` of `by` for error messages. If the
+// type is not referenced by a field `by` itself is described.
+func referrerOf(by *typeRef, field *types.Var) string {
+	if field == nil {
+		return fmt.Sprintf("type `%s`", qualifiedName(by.obj))
+	}
+	return fmt.Sprintf("field `%s.%s`", qualifiedName(by.obj), field.Name())
+}
+
+// structInfoOf returns the information of a struct which is not part of the
+// loaded project. It contains no markers.
+func structInfoOf(obj *types.TypeName) *infov1.StructInfo {
+	strct := obj.Type().Underlying().(*types.Struct)
+	info := &infov1.StructInfo{
+		Spec:    &ast.TypeSpec{Name: ast.NewIdent(obj.Name())},
+		Opts:    make(infov1.Options),
+		Fields:  make(map[types.Object]*infov1.FieldInfo, strct.NumFields()),
+		Methods: make(map[types.Object]*infov1.FuncInfo),
+	}
+	for field := range strct.Fields() {
+		if field.Embedded() {
+			continue
+		}
+		info.Fields[field] = &infov1.FieldInfo{
+			Ident: ast.NewIdent(field.Name()),
+			Opts:  make(infov1.Options),
+		}
+	}
+	return info
+}
+
+func qualifiedName(obj *types.TypeName) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
@@ -0,0 +1,75 @@
+package openapi
+
+import (
+	"go/types"
+	"maps"
+	"testing"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+func TestResolver_Cycles(t *testing.T) {
+	files := map[string]string{
+		"api/api.go": `package api
+
+// +openapi:schema:title="node"
+type Node struct {
+	Children []*Node
+}
+
+// +openapi:schema:title="person"
+type Person struct {
+	Employer *Company
+	Address  Address
+}
+
+type Company struct {
+	Owner Person
+}
+
+type Address struct {
+	Street string
+}
+
+// +openapi:schema:title="list"
+type List[T any] struct {
+	Value T
+	Next  *List[T]
+}
+`,
+	}
+	gen, err := New()
+	if err != nil {
+		t.Fatalf("unexpected err occured: %s", err)
+	}
+	proj, err := loadertest.Load(t, files, gen.Registry(), nil)
+	if err != nil {
+		t.Fatalf("unexpected err occured: %s", err)
+	}
+	cfg, err := newConfig(nil)
+	if err != nil {
+		t.Fatalf("unexpected err occured: %s", err)
+	}
+	isRoot := func(_ *types.TypeName, info infov1.Info) bool {
+		return NewSchemaResourcer().CanCreate(info)
+	}
+	res, err := newResolver(proj, cfg, isRoot)
+	if err != nil {
+		t.Fatalf("unexpected err occured: %s", err)
+	}
+	want := map[string]bool{
+		"Node":    true,
+		"Person":  true,
+		"Company": true,
+		"Address": false,
+		"List":    true,
+	}
+	got := make(map[string]bool, len(res.refs))
+	for _, ref := range res.refs {
+		got[ref.name] = ref.isRecursive
+	}
+	if !maps.Equal(want, got) {
+		t.Errorf("recursive structs are not equal.\n want: %v\n got: %v", want, got)
+	}
+}
@@ -17,8 +17,9 @@ type Resourcer interface {
 	// Options of the resource
 	Options() []*optionv1.Option
 
-	// Create generated the actual artifact
-	Create(pkg *packages.Package, obj types.Object, info infov1.Info, config *config) (*genv1.Artifact, error)
+	// Create generated the actual artifact. `res` allows to resolve types
+	// which are referenced by the info through the whole project.
+	Create(pkg *packages.Package, obj types.Object, info infov1.Info, config *config, res *resolver) (*genv1.Artifact, error)
 
 	CanCreate(info infov1.Info) bool
 }
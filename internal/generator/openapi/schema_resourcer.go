@@ -1,6 +1,7 @@
 package openapi
 
 import (
+	"fmt"
 	"go/types"
 	"path/filepath"
 
@@ -16,11 +17,14 @@ const _schemaResource = "schema"
 var _ Resourcer = (*schemaResourcer)(nil)
 
 func NewSchemaResourcer() Resourcer {
-	return &schemaResourcer{_schemaResource}
+	return &schemaResourcer{resource: _schemaResource}
 }
 
 type schemaResourcer struct {
 	resource string
+
+	// hooks apply the markers of other generators to the schemas.
+	hooks Hooks
 }
 
 func (s schemaResourcer) Resource() string {
@@ -30,66 +34,202 @@ func (s schemaResourcer) Resource() string {
 func (s schemaResourcer) Options() []*optionv1.Option {
 	return makeOpts(s.resource,
 		// annotations
-		mustMakeOpt(_typeName, Description(""), _unique, optionv1.TargetStruct, optionv1.TargetField),
-		mustMakeOpt(_typeName, Title(""), _unique, optionv1.TargetStruct, optionv1.TargetField),
-		mustMakeOpt(_typeName, Examples(nil), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, Default(""), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, Deprecated(false), _unique, optionv1.TargetStruct, optionv1.TargetField),
+		mustMakeOpt(_typeName, Description(""), _unique, optionv1.TargetStruct, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias, optionv1.TargetConst),
+		mustMakeOpt(_typeName, Title(""), _unique, optionv1.TargetStruct, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, Examples(nil), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, Default(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, Deprecated(false), _unique, optionv1.TargetStruct, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
 		mustMakeOpt(_typeName, WriteOnly(false), _unique, optionv1.TargetField),
 		mustMakeOpt(_typeName, ReadOnly(false), _unique, optionv1.TargetField),
 		// agnostic
-		mustMakeOpt(_typeName, Enum(nil), _unique, optionv1.TargetField),
+		mustMakeOpt(_typeName, Enum(nil), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, Exclude(false), _unique, optionv1.TargetConst, optionv1.TargetNamed),
+		mustMakeOpt(_typeName, Nullable(false), _unique, optionv1.TargetField),
+		mustMakeOpt(_typeName, Inline(false), _unique, optionv1.TargetField),
+		// extensions
+		mustMakeOpt(_typeName, Extension(""), _repetable, optionv1.TargetStruct, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
 		// array
-		mustMakeOpt(_typeName, MinItems(0), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, MaxItems(0), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, UniqueItems(false), _unique, optionv1.TargetField),
+		mustMakeOpt(_typeName, MinItems(0), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, MaxItems(0), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, UniqueItems(false), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
 		// object
 		mustMakeOpt(_typeName, Required(false), _unique, optionv1.TargetField),
 		mustMakeOpt(_typeName, DependentRequired(nil), _unique, optionv1.TargetField),
+		mustMakeOpt(_typeName, MinProperties(0), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_typeName, MaxProperties(0), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_typeName, AdditionalProperties(false), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_typeName, Const(""), _repetable, optionv1.TargetStruct),
+		// composition
+		mustMakeOpt(_typeName, If(""), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_typeName, Then(nil), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_typeName, Else(nil), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_typeName, AllOf(""), _repetable, optionv1.TargetStruct),
+		mustMakeOpt(_typeName, Not(nil), _unique, optionv1.TargetStruct),
+		// polymorphism
+		mustMakeOpt(_typeName, Discriminator(""), _unique, optionv1.TargetIface),
+		mustMakeOpt(_typeName, DiscriminatorValue(""), _unique, optionv1.TargetStruct),
 		// string
-		mustMakeOpt(_typeName, Pattern(""), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, Format(""), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, ContentMediaType(""), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, ContentEncoding(""), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, MinLength(0), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, MaxLength(0), _unique, optionv1.TargetField),
+		mustMakeOpt(_typeName, Pattern(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, Format(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, ContentMediaType(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, ContentEncoding(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, MinLength(0), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, MaxLength(0), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
 		// numeric
-		mustMakeOpt(_typeName, Minimum(0), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, Maximum(0), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, ExclusiveMaximum(0), _unique, optionv1.TargetField),
-		mustMakeOpt(_typeName, ExclusiveMinimum(0), _unique, optionv1.TargetField),
+		mustMakeOpt(_typeName, Minimum(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, Maximum(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, ExclusiveMaximum(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, ExclusiveMinimum(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeName, MultipleOf(""), _unique, optionv1.TargetField, optionv1.TargetNamed, optionv1.TargetAlias),
 	)
 }
 
+// CanCreate reports whether a schema is generated for `info`. Structs need a
+// title or description marker while named non-struct types and aliases are
+// generated if they have any schema marker.
 func (s schemaResourcer) CanCreate(info infov1.Info) bool {
-	_, isStruct := info.(*infov1.StructInfo)
-	opts := info.Options()
-	return opts.IsDefined("openapi:schema:description") || opts.IsDefined("openapi:schema:title") && isStruct
+	switch info.(type) {
+	case *infov1.StructInfo:
+		opts := info.Options()
+		return opts.IsDefined("openapi:schema:description") || opts.IsDefined("openapi:schema:title")
+	case *infov1.NamedInfo, *infov1.AliasInfo:
+		return hasSchemaOpts(info)
+	}
+	return false
 }
 
-func (s schemaResourcer) Create(pkg *packages.Package, obj types.Object, info infov1.Info, cfg *config) (*genv1.Artifact, error) {
-	structInfo := info.(*infov1.StructInfo)
-	root, err := s.newRootSchema(structInfo, cfg)
+func (s schemaResourcer) Create(
+	pkg *packages.Package,
+	obj types.Object,
+	info infov1.Info,
+	cfg *config,
+	res *resolver,
+) (*genv1.Artifact, error) {
+	structInfo, isStruct := info.(*infov1.StructInfo)
+	if isStruct {
+		root, err := s.schema(pkg, obj, structInfo, cfg, res)
+		if err != nil {
+			return nil, err
+		}
+		filename := filepath.Base(root.ID)
+		return newArtifact(filename, cfg.Output, root)
+	}
+	ref, err := res.lookup(obj)
+	if err != nil {
+		return nil, err
+	}
+	return s.createRef(ref, cfg, res)
+}
+
+// createRef creates the artifact of the type referenced by `ref`.
+func (s schemaResourcer) createRef(ref *typeRef, cfg *config, res *resolver) (*genv1.Artifact, error) {
+	schema, err := s.schemaOf(ref, cfg, res)
+	if err != nil {
+		return nil, err
+	}
+	return newArtifact(filepath.Base(schema.ID), cfg.Output, schema)
+}
+
+// schemaOf returns the schema of the type referenced by `ref`.
+func (s schemaResourcer) schemaOf(ref *typeRef, cfg *config, res *resolver) (*Schema, error) {
+	switch {
+	case ref.named != nil:
+		return s.namedSchema(ref, cfg, res)
+	case ref.enum == nil:
+		return s.schema(ref.pkg, ref.obj, ref.info, cfg, res)
+	}
+	schema, err := newRootSchemaOf(ref.obj, cfg, res)
+	if err != nil {
+		return nil, err
+	}
+	applyEnumValues(&schema, ref)
+	// the title and description are applied the same way as for structs.
+	if err := s.applyNamedOpts(&schema, ref.enum.info, ref.obj); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// namedSchema returns the schema of the named non-struct type or alias
+// referenced by `ref`. The schema is built from the type defining it e.g.
+// `string` of `type Email string`.
+func (s schemaResourcer) namedSchema(ref *typeRef, cfg *config, res *resolver) (*Schema, error) {
+	root, err := newRootSchemaOf(ref.obj, cfg, res)
+	if err != nil {
+		return nil, err
+	}
+	typeRes := res
+	if cfg.Schema.Inline {
+		typeRes = s.inlining(cfg, res)
+	}
+	schema, err := newSchema(definitionOf(ref.obj), typeRes)
 	if err != nil {
+		return nil, fmt.Errorf("type `%s` is invalid: %w", ref.obj.Name(), err)
+	}
+	schema.ID, schema.Draft, schema.Extensions = root.ID, root.Draft, root.Extensions
+	if err := s.applyNamedOpts(&schema, ref.named, ref.obj); err != nil {
+		return nil, fmt.Errorf("type `%s` is invalid: %w", ref.obj.Name(), err)
+	}
+	if err := isValidValues(fmt.Sprintf("type `%s`", ref.obj.Name()), &schema); err != nil {
+		return nil, err
+	}
+	if err := isValidSchema(ref.obj.Name(), &schema); err != nil {
 		return nil, err
 	}
-	err = s.applyStructOpts(&root, structInfo)
+	return &schema, nil
+}
+
+// schema returns the schema of the struct `obj`.
+func (s schemaResourcer) schema(
+	pkg *packages.Package,
+	obj types.Object,
+	structInfo *infov1.StructInfo,
+	cfg *config,
+	res *resolver,
+) (*Schema, error) {
+	root, err := s.newRootSchema(obj, structInfo, cfg, res)
 	if err != nil {
 		return nil, err
 	}
-	for obj, finfo := range structInfo.Fields {
+	strct := obj.Type().Underlying().(*types.Struct)
+	for obj, finfo := range infov1.ByPos(fsetOf(pkg), structInfo.Fields) {
 		if !finfo.Ident.IsExported() {
 			continue
 		}
-		fieldSchema, err := s.buildFieldSchema(&root, structInfo, obj, finfo, cfg)
+		fieldSchema, err := s.buildFieldSchema(&root, structInfo, obj, finfo, cfg, res)
 		if err != nil {
+			return nil, fmt.Errorf("field `%s` of struct `%s` is invalid: %w", finfo.Ident.Name, structInfo.Spec.Name.Name, err)
+		}
+		subject := fmt.Sprintf("field `%s` in struct `%s`", finfo.Ident.Name, structInfo.Spec.Name.Name)
+		if err := isValidValues(subject, fieldSchema); err != nil {
 			return nil, err
 		}
 		name := cfg.Schema.Formats.Property.Format(finfo.Ident.Name)
 		root.Properties[name] = fieldSchema
+		if !isRequired(strct, obj, finfo, cfg) {
+			continue
+		}
+		if err := Required(true).apply(&root, finfo, cfg); err != nil {
+			return nil, err
+		}
 	}
-	filename := filepath.Base(root.ID)
-	return newArtifact(filename, root)
+	// struct options are applied after the fields because they might
+	// reference the properties of the fields.
+	if err := s.applyStructOpts(&root, structInfo, cfg); err != nil {
+		return nil, err
+	}
+	if s.hooks.Struct != nil {
+		if err := s.hooks.Struct(obj, structInfo, &root); err != nil {
+			return nil, fmt.Errorf("struct `%s` is invalid: %w", structInfo.Spec.Name.Name, err)
+		}
+	}
+	for _, impl := range res.discriminatorsOf(obj) {
+		applyDiscriminator(&root, impl.iface.discriminator, impl.value)
+	}
+	if err := isValidSchema(obj.Name(), &root); err != nil {
+		return nil, err
+	}
+	return &root, nil
 }
 
 func (s schemaResourcer) buildFieldSchema(
@@ -98,31 +238,56 @@ func (s schemaResourcer) buildFieldSchema(
 	obj types.Object,
 	finfo *infov1.FieldInfo,
 	cfg *config,
+	res *resolver,
 ) (*Schema, error) {
-	fieldSchema, err := newSchema(obj.Type(), cfg)
+	typeRes := res
+	if isInline(finfo, cfg) {
+		typeRes = s.inlining(cfg, res)
+	}
+	fieldSchema, err := newSchema(obj.Type(), typeRes)
 	if err != nil {
 		return nil, err
 	}
-	// if a reference is set it means that the field schema is another schema.
-	if fieldSchema.Ref != "" {
-		return &fieldSchema, s.applyRefOpts(&fieldSchema, finfo)
+	// the options of a field referencing another schema are siblings of the
+	// `$ref` and apply in addition to the referenced schema.
+	if err := s.applyFieldOpts(root, &fieldSchema, sinfo, obj, finfo, cfg); err != nil {
+		return nil, err
 	}
-	err = s.applyFieldOpts(root, &fieldSchema, sinfo, obj, finfo, cfg)
-	return &fieldSchema, err
+	if s.hooks.Field != nil {
+		if err := s.hooks.Field(obj, finfo, &fieldSchema); err != nil {
+			return nil, err
+		}
+	}
+	nullable, err := isNullable(obj, finfo, cfg)
+	if err != nil || !nullable {
+		return &fieldSchema, err
+	}
+	return newNullableSchema(&fieldSchema), nil
+}
+
+// inlining returns a copy of `res` which inlines the schemas of the
+// referenced structs. The fields of an inlined struct decide on their own
+// whether they inline the structs they reference.
+func (s schemaResourcer) inlining(cfg *config, res *resolver) *resolver {
+	return res.inlining(func(ref *typeRef) (Schema, error) {
+		schema, err := s.schema(ref.pkg, ref.obj, ref.info, cfg, res)
+		if err != nil {
+			return _schemaz, err
+		}
+		// an inlined schema is identified by its location.
+		schema.ID, schema.Draft = "", ""
+		return *schema, nil
+	})
 }
 
-func (s schemaResourcer) newRootSchema(info *infov1.StructInfo, cfg *config) (Schema, error) {
-	id, err := id(info.Spec.Name.Name, cfg.Schema.IDBaseURL, cfg.Schema.Formats.Filename)
+func (s schemaResourcer) newRootSchema(obj types.Object, info *infov1.StructInfo, cfg *config, res *resolver) (Schema, error) {
+	schema, err := newRootSchemaOf(obj, cfg, res)
 	if err != nil {
 		return Schema{}, err
 	}
-	schema := Schema{
-		ID:                id,
-		Draft:             cfg.Schema.Draft,
-		Type:              objectType,
-		Properties:        make(map[string]*Schema, len(info.Fields)),
-		DependentRequired: make(map[string][]string),
-	}
+	schema.Type = objectType
+	schema.Properties = make(map[string]*Schema, len(info.Fields))
+	schema.DependentRequired = make(map[string][]string)
 	return schema, nil
 }
 
@@ -167,15 +332,15 @@ func (s schemaResourcer) applyFieldOpts(
 				err = o.apply(fieldSchema)
 			// numeric
 			case Maximum:
-				err = o.apply(fieldSchema)
+				err = o.apply(fieldSchema, obj.Type())
 			case Minimum:
-				err = o.apply(fieldSchema)
+				err = o.apply(fieldSchema, obj.Type())
 			case ExclusiveMaximum:
-				err = o.apply(fieldSchema)
+				err = o.apply(fieldSchema, obj.Type())
 			case ExclusiveMinimum:
-				err = o.apply(fieldSchema)
+				err = o.apply(fieldSchema, obj.Type())
 			case MultipleOf:
-				err = o.apply(fieldSchema)
+				err = o.apply(fieldSchema, obj.Type())
 			// string
 			case Format:
 				err = o.apply(fieldSchema)
@@ -190,10 +355,11 @@ func (s schemaResourcer) applyFieldOpts(
 			case ContentMediaType:
 				err = o.apply(fieldSchema)
 			// object
-			case Required:
-				err = o.apply(root, finfo, cfg)
 			case DependentRequired:
 				err = o.apply(root, cfg, finfo, sinfo)
+			// extensions
+			case Extension:
+				err = o.apply(fieldSchema)
 			}
 			if err != nil {
 				return err
@@ -203,7 +369,7 @@ func (s schemaResourcer) applyFieldOpts(
 	return nil
 }
 
-func (s schemaResourcer) applyStructOpts(schema *Schema, info *infov1.StructInfo) error {
+func (s schemaResourcer) applyStructOpts(schema *Schema, info *infov1.StructInfo, cfg *config) error {
 	for ident, opts := range info.Options() {
 		if !isResource(ident, _schemaResource) {
 			continue
@@ -217,27 +383,96 @@ func (s schemaResourcer) applyStructOpts(schema *Schema, info *infov1.StructInfo
 				err = o.apply(schema)
 			case Deprecated:
 				err = o.apply(schema)
+			// object
+			case MinProperties:
+				err = o.apply(schema)
+			case MaxProperties:
+				err = o.apply(schema)
+			case AdditionalProperties:
+				err = o.apply(schema)
+			case Const:
+				err = o.apply(schema, cfg, info)
+			// extensions
+			case Extension:
+				err = o.apply(schema)
+			// composition
+			case If:
+				err = o.apply(schema, cfg, info)
+			case Then:
+				err = o.apply(schema, cfg, info)
+			case Else:
+				err = o.apply(schema, cfg, info)
+			case AllOf:
+				err = o.apply(schema, cfg, info)
+			case Not:
+				err = o.apply(schema, cfg, info)
 			}
 			if err != nil {
 				return err
 			}
 		}
 	}
-	return nil
+	return isValidObject(schema, info)
 }
 
-func (s schemaResourcer) applyRefOpts(schema *Schema, finfo *infov1.FieldInfo) error {
-	for ident, opts := range finfo.Options() {
+// applyNamedOpts applies the options of the named type or alias `obj` to its
+// schema.
+func (s schemaResourcer) applyNamedOpts(schema *Schema, info infov1.Info, obj types.Object) error {
+	for ident, opts := range info.Options() {
 		if !isResource(ident, _schemaResource) {
 			continue
 		}
 		for _, opt := range opts {
 			var err error
 			switch o := opt.(type) {
+			// agnostic
+			case Enum:
+				err = o.apply(schema, obj)
+			// array
 			case MinItems:
 				err = o.apply(schema)
 			case MaxItems:
 				err = o.apply(schema)
+			case UniqueItems:
+				err = o.apply(schema)
+			// annotations
+			case Title:
+				err = o.apply(schema)
+			case Description:
+				err = o.apply(schema)
+			case Examples:
+				err = o.apply(schema, obj.Type())
+			case Default:
+				err = o.apply(schema)
+			case Deprecated:
+				err = o.apply(schema)
+			// numeric
+			case Maximum:
+				err = o.apply(schema, obj.Type())
+			case Minimum:
+				err = o.apply(schema, obj.Type())
+			case ExclusiveMaximum:
+				err = o.apply(schema, obj.Type())
+			case ExclusiveMinimum:
+				err = o.apply(schema, obj.Type())
+			case MultipleOf:
+				err = o.apply(schema, obj.Type())
+			// string
+			case Format:
+				err = o.apply(schema)
+			case Pattern:
+				err = o.apply(schema)
+			case MaxLength:
+				err = o.apply(schema)
+			case MinLength:
+				err = o.apply(schema)
+			case ContentEncoding:
+				err = o.apply(schema)
+			case ContentMediaType:
+				err = o.apply(schema)
+			// extensions
+			case Extension:
+				err = o.apply(schema)
 			}
 			if err != nil {
 				return err
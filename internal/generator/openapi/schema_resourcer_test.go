@@ -2,9 +2,12 @@ package openapi
 
 import (
 	"encoding/json"
+	"maps"
 	"path/filepath"
 	"slices"
 	"testing"
+
+	"github.com/naivary/codemark/loader/loadertest"
 )
 
 func TestResourcer_Schema(t *testing.T) {
@@ -13,6 +16,8 @@ func TestResourcer_Schema(t *testing.T) {
 		isValid bool
 		cfgFile string
 		want    Schema
+		// deps are the schemas of the referenced structs without markers.
+		deps []Schema
 	}{
 		{
 			path:    "testdata/schema/required.go",
@@ -102,6 +107,65 @@ func TestResourcer_Schema(t *testing.T) {
 			isValid: false,
 			want:    _schemaz,
 		},
+		{
+			path:    "testdata/schema/recursive_self.go",
+			isValid: true,
+			want: Schema{
+				ID:    "node.json",
+				Draft: "https://json-schema.org/draft/2020-12/schema",
+				Title: "node",
+				Type:  objectType,
+				Properties: map[string]*Schema{
+					"value":    {Type: stringType},
+					"parent":   {Ref: "node.json"},
+					"children": {Type: arrayType, Items: &Schema{Ref: "node.json"}},
+				},
+			},
+		},
+		{
+			path:    "testdata/schema/recursive_mutual.go",
+			isValid: true,
+			want: Schema{
+				ID:    "person.json",
+				Draft: "https://json-schema.org/draft/2020-12/schema",
+				Title: "person",
+				Type:  objectType,
+				Properties: map[string]*Schema{
+					"name":     {Type: stringType},
+					"employer": {Ref: "company.json"},
+				},
+			},
+			deps: []Schema{
+				{
+					ID:    "company.json",
+					Draft: "https://json-schema.org/draft/2020-12/schema",
+					Type:  objectType,
+					Properties: map[string]*Schema{
+						"employees": {Type: arrayType, Items: &Schema{Ref: "person.json"}},
+						"branches":  {Type: objectType, AdditionalProperties: &Schema{Ref: "company.json"}},
+					},
+				},
+			},
+		},
+		{
+			path:    "testdata/schema/recursive_generic.go",
+			isValid: true,
+			want: Schema{
+				ID:    "tree.json",
+				Draft: "https://json-schema.org/draft/2020-12/schema",
+				Title: "tree",
+				Type:  objectType,
+				Properties: map[string]*Schema{
+					"value":    {},
+					"children": {Type: arrayType, Items: &Schema{Ref: "tree.json"}},
+					"parent":   {Ref: "tree.json"},
+				},
+			},
+		},
+		{
+			path:    "testdata/schema/generic_instance.go",
+			isValid: false,
+		},
 	}
 	for _, tc := range tests {
 		name := filepath.Base(tc.path)
@@ -126,7 +190,1481 @@ func TestResourcer_Schema(t *testing.T) {
 				t.Errorf("schemas are not equal.\n want: %s\n got: %s", wantJSON, gotJSON)
 				t.FailNow()
 			}
+			if len(artifacts) != len(tc.deps)+1 {
+				t.Fatalf("expected %d dependencies. got: %d", len(tc.deps), len(artifacts)-1)
+			}
+			for i, dep := range tc.deps {
+				got := Schema{}
+				if err := json.NewDecoder(artifacts[i+1].Data).Decode(&got); err != nil {
+					t.Fatalf("unexpected err occured: %s", err)
+				}
+				if wantJSON, gotJSON := mustMarshal(dep), mustMarshal(got); !slices.Equal(wantJSON, gotJSON) {
+					t.Errorf("dependencies are not equal.\n want: %s\n got: %s", wantJSON, gotJSON)
+				}
+			}
 			t.Logf("Success!\n want: %s\n got: %s", wantJSON, gotJSON)
 		})
 	}
 }
+
+func TestResourcer_SchemaRefs(t *testing.T) {
+	files := map[string]string{
+		"api/customer.go": `package api
+
+import (
+	"loadertest/billing"
+	"loadertest/shipping"
+)
+
+// +openapi:schema:title="customer"
+type Customer struct {
+	Billing  billing.Address
+	Shipping []*shipping.Address
+	Contact  Contact
+}
+
+type Contact struct {
+	Email string
+}
+`,
+		"billing/address.go":  "package billing\n\ntype Address struct {\n\tIBAN string\n}\n",
+		"shipping/address.go": "package shipping\n\ntype Address struct {\n\tStreet string\n}\n",
+	}
+	tests := []struct {
+		name    string
+		cfg     map[string]any
+		isValid bool
+		want    []string
+	}{
+		{
+			name:    "include",
+			isValid: true,
+			want:    []string{"customer.json", "billing_address.json", "shipping_address.json", "contact.json"},
+		},
+		{
+			name:    "error",
+			cfg:     map[string]any{"schema": map[string]any{"dependencies": "error"}},
+			isValid: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, files, tc.cfg)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err != nil && !tc.isValid {
+				t.Logf("skipping rest of the test because expected error occured: %s", err)
+				t.SkipNow()
+			}
+			got := make([]string, 0, len(artifacts))
+			for _, artifact := range artifacts {
+				got = append(got, artifact.Name)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("artifacts are not equal.\n want: %v\n got: %v", tc.want, got)
+			}
+			customer := Schema{}
+			err = json.NewDecoder(artifacts[0].Data).Decode(&customer)
+			if err != nil {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			refs := map[string]string{
+				"billing":  customer.Properties["billing"].Ref,
+				"shipping": customer.Properties["shipping"].Items.Ref,
+				"contact":  customer.Properties["contact"].Ref,
+			}
+			for i, prop := range []string{"billing", "shipping", "contact"} {
+				if refs[prop] != tc.want[i+1] {
+					t.Errorf("ref of %s is not equal. want: %s; got: %s", prop, tc.want[i+1], refs[prop])
+				}
+			}
+		})
+	}
+}
+
+func TestResourcer_SchemaPolymorphism(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  string
+		isValid bool
+		mapping map[string]string
+	}{
+		{
+			name:    "discriminator",
+			isValid: true,
+			events: `package api
+
+// +openapi:schema:discriminator="kind"
+type Event interface {
+	isEvent()
+}
+
+type Created struct {
+	Name string
+}
+
+func (c Created) isEvent() {}
+
+// +openapi:schema:discriminatorValue="deleted"
+type Deleted struct {
+	Kind string
+	ID   string
+}
+
+func (d *Deleted) isEvent() {}
+
+type Other struct{}
+`,
+			mapping: map[string]string{
+				"Created": "created.json",
+				"deleted": "deleted.json",
+			},
+		},
+		{
+			name:    "no implementations",
+			isValid: false,
+			events: `package api
+
+// +openapi:schema:discriminator="kind"
+type Event interface {
+	isEvent()
+}
+`,
+		},
+		{
+			name:    "duplicate value",
+			isValid: false,
+			events: `package api
+
+// +openapi:schema:discriminator="kind"
+type Event interface {
+	isEvent()
+}
+
+// +openapi:schema:discriminatorValue="created"
+type Created struct{}
+
+func (c Created) isEvent() {}
+
+// +openapi:schema:discriminatorValue="created"
+type Restored struct{}
+
+func (r Restored) isEvent() {}
+`,
+		},
+	}
+	const envelope = `package api
+
+// +openapi:schema:title="envelope"
+type Envelope struct {
+	Payload Event
+	History []Event
+}
+`
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			files := map[string]string{
+				"api/envelope.go": envelope,
+				"api/events.go":   tc.events,
+			}
+			artifacts, err := loadertest.Generate(t, New, files, nil)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			schemas := make(map[string]*Schema, len(artifacts))
+			for _, artifact := range artifacts {
+				schema := Schema{}
+				if err := json.NewDecoder(artifact.Data).Decode(&schema); err != nil {
+					t.Fatalf("unexpected err occured: %s", err)
+				}
+				schemas[artifact.Name] = &schema
+			}
+			if _, exists := schemas["other.json"]; exists {
+				t.Errorf("struct not implementing the interface should not be generated")
+			}
+			payload := schemas["envelope.json"].Properties["payload"]
+			history := schemas["envelope.json"].Properties["history"].Items
+			for _, prop := range []*Schema{payload, history} {
+				if prop.Discriminator == nil || prop.Discriminator.PropertyName != "kind" {
+					t.Fatalf("discriminator is missing: %s", mustMarshal(prop))
+				}
+				if !maps.Equal(prop.Discriminator.Mapping, tc.mapping) {
+					t.Errorf("mapping is not equal.\n want: %v\n got: %v", tc.mapping, prop.Discriminator.Mapping)
+				}
+				refs := make([]string, 0, len(prop.OneOf))
+				for _, one := range prop.OneOf {
+					refs = append(refs, one.Ref)
+				}
+				if want := []string{"created.json", "deleted.json"}; !slices.Equal(refs, want) {
+					t.Errorf("oneOf is not equal.\n want: %v\n got: %v", want, refs)
+				}
+			}
+			for filename, value := range map[string]string{"created.json": "Created", "deleted.json": "deleted"} {
+				impl := schemas[filename]
+				kind := impl.Properties["kind"]
+				if kind == nil || !slices.Equal(kind.Enum, []any{value}) {
+					t.Errorf("discriminator property of %s is not equal to %s: %s", filename, value, mustMarshal(kind))
+				}
+				if !slices.Equal(impl.Required, []string{"kind"}) {
+					t.Errorf("discriminator property of %s is not required: %v", filename, impl.Required)
+				}
+			}
+		})
+	}
+}
+
+func TestResourcer_SchemaEnum(t *testing.T) {
+	files := map[string]string{
+		"api/task.go": `package api
+
+// +openapi:schema:title="task"
+type Task struct {
+	Status     Status
+	Priorities []Priority
+	Label      Label
+	Port       Port
+}
+
+// +openapi:schema:description="status of a task"
+type Status string
+
+const (
+	// +openapi:schema:description="task is in progress"
+	StatusActive Status = "active"
+	// +openapi:schema:exclude
+	StatusDeleted Status = "deleted"
+	StatusPending Status = "pending"
+
+	statusUnknown Status = "unknown"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityMedium
+	PriorityHigh
+)
+
+type Label string
+
+// +openapi:schema:exclude
+type Port int
+
+const DefaultPort Port = 80
+`,
+	}
+	artifacts, err := loadertest.Generate(t, New, files, nil)
+	if err != nil {
+		t.Fatalf("unexpected err occured: %s", err)
+	}
+	schemas := make(map[string]*Schema, len(artifacts))
+	for _, artifact := range artifacts {
+		schema := Schema{}
+		if err := json.NewDecoder(artifact.Data).Decode(&schema); err != nil {
+			t.Fatalf("unexpected err occured: %s", err)
+		}
+		schemas[artifact.Name] = &schema
+	}
+	want := map[string]Schema{
+		"task.json": {
+			ID:    "task.json",
+			Draft: "https://json-schema.org/draft/2020-12/schema",
+			Title: "task",
+			Type:  objectType,
+			Properties: map[string]*Schema{
+				"status":     {Ref: "status.json"},
+				"priorities": {Type: arrayType, Items: &Schema{Ref: "priority.json"}},
+				"label":      {Type: stringType},
+				"port":       {Type: integerType},
+			},
+		},
+		"status.json": {
+			ID:    "status.json",
+			Draft: "https://json-schema.org/draft/2020-12/schema",
+			Type:  stringType,
+			Desc:  "status of a task",
+			Enum:  []any{"active", "pending"},
+			OneOf: []*Schema{
+				{Const: "active", Desc: "task is in progress"},
+				{Const: "pending"},
+			},
+		},
+		"priority.json": {
+			ID:    "priority.json",
+			Draft: "https://json-schema.org/draft/2020-12/schema",
+			Type:  integerType,
+			Enum:  []any{0, 1, 2},
+		},
+	}
+	if got := slices.Sorted(maps.Keys(schemas)); !slices.Equal(got, slices.Sorted(maps.Keys(want))) {
+		t.Fatalf("artifacts are not equal.\n want: %v\n got: %v", slices.Sorted(maps.Keys(want)), got)
+	}
+	for filename, schema := range want {
+		wantJSON := mustMarshal(schema)
+		gotJSON := mustMarshal(schemas[filename])
+		if !slices.Equal(wantJSON, gotJSON) {
+			t.Errorf("schemas of %s are not equal.\n want: %s\n got: %s", filename, wantJSON, gotJSON)
+		}
+	}
+}
+
+func TestResourcer_SchemaNullable(t *testing.T) {
+	const user = `package api
+
+// +openapi:schema:title="user"
+type User struct {
+	Name     string
+	Nickname *string ` + "`json:\"nickname\"`" + `
+	Email    string  ` + "`json:\"email,omitempty\"`" + `
+	Tags     []string
+	Address  *Address
+	// +openapi:schema:enum=["admin", "guest"]
+	Role *string
+	// +openapi:schema:nullable=false
+	Labels map[string]string
+	// +openapi:schema:required=false
+	Age int
+}
+
+type Address struct {
+	Street string
+}
+`
+	tests := []struct {
+		name    string
+		cfg     map[string]any
+		user    string
+		isValid bool
+		want    Schema
+	}{
+		{
+			name:    "nullable and inferred required",
+			cfg:     map[string]any{"schema": map[string]any{"nullable": true, "inferRequired": true}},
+			user:    user,
+			isValid: true,
+			want: Schema{
+				ID:    "user.json",
+				Draft: "https://json-schema.org/draft/2020-12/schema",
+				Title: "user",
+				Type:  objectType,
+				Properties: map[string]*Schema{
+					"name":     {Type: stringType},
+					"nickname": {Type: stringType, Nullable: true},
+					"email":    {Type: stringType},
+					"tags":     {Type: arrayType, Nullable: true, Items: &Schema{Type: stringType}},
+					"address":  {OneOf: []*Schema{{Ref: "address.json"}, {Type: nullType}}},
+					"role":     {Type: stringType, Nullable: true, Enum: []any{"admin", "guest", nil}},
+					"labels":   {Type: objectType, AdditionalProperties: &Schema{Type: stringType}},
+					"age":      {Type: integerType},
+				},
+				Required: []string{"name", "tags", "labels"},
+			},
+		},
+		{
+			name:    "default",
+			user:    user,
+			isValid: true,
+			want: Schema{
+				ID:    "user.json",
+				Draft: "https://json-schema.org/draft/2020-12/schema",
+				Title: "user",
+				Type:  objectType,
+				Properties: map[string]*Schema{
+					"name":     {Type: stringType},
+					"nickname": {Type: stringType},
+					"email":    {Type: stringType},
+					"tags":     {Type: arrayType, Items: &Schema{Type: stringType}},
+					"address":  {Ref: "address.json"},
+					"role":     {Type: stringType, Enum: []any{"admin", "guest"}},
+					"labels":   {Type: objectType, AdditionalProperties: &Schema{Type: stringType}},
+					"age":      {Type: integerType},
+				},
+			},
+		},
+		{
+			name:    "nullable non pointer",
+			isValid: false,
+			user: `package api
+
+// +openapi:schema:title="user"
+type User struct {
+	// +openapi:schema:nullable
+	Name string
+}
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, map[string]string{"api/user.go": tc.user}, tc.cfg)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			got := Schema{}
+			if err := json.NewDecoder(artifacts[0].Data).Decode(&got); err != nil {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			wantJSON := mustMarshal(tc.want)
+			gotJSON := mustMarshal(got)
+			if !slices.Equal(wantJSON, gotJSON) {
+				t.Errorf("schemas are not equal.\n want: %s\n got: %s", wantJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestResourcer_SchemaWellKnownTypes(t *testing.T) {
+	files := map[string]string{
+		"api/event.go": `package api
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"time"
+)
+
+// +openapi:schema:title="event"
+type Event struct {
+	CreatedAt time.Time
+	DeletedAt *time.Time
+	History   []time.Time
+	Timeout   time.Duration
+	Payload   []byte
+	IP        net.IP
+	Link      url.URL
+	Raw       json.RawMessage
+	Color     Color
+	Price     Money
+}
+
+type Color struct {
+	R, G, B uint8
+}
+
+func (c Color) MarshalText() ([]byte, error) {
+	return nil, nil
+}
+
+type Money struct {
+	Amount   int
+	Currency string
+}
+`,
+	}
+	cfg := map[string]any{
+		"schema": map[string]any{
+			"types": map[string]any{
+				"loadertest/api.Money": map[string]any{"type": "string", "pattern": "^[0-9]+ [A-Z]{3}$"},
+				"time.Duration":        map[string]any{"type": "string"},
+			},
+		},
+	}
+	artifacts, err := loadertest.Generate(t, New, files, cfg)
+	if err != nil {
+		t.Fatalf("unexpected err occured: %s", err)
+	}
+	if len(artifacts) != 1 {
+		t.Fatalf("well-known types should not be generated as schemas. got %d artifacts", len(artifacts))
+	}
+	got := Schema{}
+	if err := json.NewDecoder(artifacts[0].Data).Decode(&got); err != nil {
+		t.Fatalf("unexpected err occured: %s", err)
+	}
+	want := map[string]*Schema{
+		"createdAt": {Type: stringType, Format: "date-time"},
+		"deletedAt": {Type: stringType, Format: "date-time"},
+		"history":   {Type: arrayType, Items: &Schema{Type: stringType, Format: "date-time"}},
+		"timeout":   {Type: stringType},
+		"payload":   {Type: stringType, ContentEncoding: "base64"},
+		"ip":        {Type: stringType, AnyOf: []*Schema{{Format: "ipv4"}, {Format: "ipv6"}}},
+		"link":      {Type: stringType, Format: "uri"},
+		"raw":       {},
+		"color":     {Type: stringType},
+		"price":     {Type: stringType, Pattern: "^[0-9]+ [A-Z]{3}$"},
+	}
+	wantJSON := mustMarshal(want)
+	gotJSON := mustMarshal(got.Properties)
+	if !slices.Equal(wantJSON, gotJSON) {
+		t.Errorf("schemas are not equal.\n want: %s\n got: %s", wantJSON, gotJSON)
+	}
+}
+
+func TestResourcer_SchemaWellKnownTypesInvalid(t *testing.T) {
+	files := map[string]string{
+		"api/event.go": `package api
+
+import "time"
+
+// +openapi:schema:title="event"
+type Event struct {
+	Timeout time.Duration
+}
+`,
+	}
+	cfg := map[string]any{
+		"schema": map[string]any{
+			"types": map[string]any{
+				"time.Duration": map[string]any{"type": "integer", "maximum": "ten"},
+			},
+		},
+	}
+	_, err := loadertest.Generate(t, New, files, cfg)
+	if err == nil {
+		t.Fatalf("expected an error for a schema which cannot be encoded")
+	}
+	t.Logf("expected error occured: %s", err)
+}
+
+func TestResourcer_SchemaNumeric(t *testing.T) {
+	tests := []struct {
+		name    string
+		product string
+		isValid bool
+		want    map[string]*Schema
+	}{
+		{
+			name:    "integers and floats",
+			isValid: true,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:minimum=0
+	// +openapi:schema:maximum=0.95
+	Discount float64
+	// +openapi:schema:exclusiveMinimum=0
+	// +openapi:schema:multipleOf=5
+	Stock int
+	// +openapi:schema:multipleOf=0.01
+	// +openapi:schema:exclusiveMaximum=1000.5
+	Prices []float32
+	// +openapi:schema:maximum=10
+	Ratings map[string]uint8
+}
+`,
+			want: map[string]*Schema{
+				"discount": {Type: numberType, Minimum: "0", Maximum: "0.95"},
+				"stock":    {Type: integerType, ExclusiveMinimum: "0", MultipleOf: "5"},
+				"prices":   {Type: arrayType, Items: &Schema{Type: numberType, ExclusiveMaximum: "1000.5", MultipleOf: "0.01"}},
+				"ratings":  {Type: objectType, AdditionalProperties: &Schema{Type: integerType, Maximum: "10"}},
+			},
+		},
+		{
+			name:    "exact integer beyond float64",
+			isValid: true,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:minimum=9007199254740993
+	ID int64
+}
+`,
+			want: map[string]*Schema{
+				"id": {Type: integerType, Minimum: "9007199254740993"},
+			},
+		},
+		{
+			name:    "literal as written",
+			isValid: true,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:maximum=0.950
+	Discount float64
+}
+`,
+			want: map[string]*Schema{
+				"discount": {Type: numberType, Maximum: "0.950"},
+			},
+		},
+		{
+			name:    "literal which is not a JSON number",
+			isValid: false,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:maximum=+5
+	Stock int
+}
+`,
+		},
+		{
+			name:    "inexact float bound",
+			isValid: false,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:maximum=9007199254740993
+	Price float64
+}
+`,
+		},
+		{
+			name:    "float multipleOf of integer",
+			isValid: false,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:multipleOf=0.5
+	Stock int
+}
+`,
+		},
+		{
+			name:    "float maximum of integer slice",
+			isValid: false,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:maximum=9.5
+	Stock []int
+}
+`,
+		},
+		{
+			name:    "multipleOf zero",
+			isValid: false,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:multipleOf=0
+	Discount float64
+}
+`,
+		},
+		{
+			name:    "minimum of string",
+			isValid: false,
+			product: `package api
+
+// +openapi:schema:title="product"
+type Product struct {
+	// +openapi:schema:minimum=1
+	Name string
+}
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, map[string]string{"api/product.go": tc.product}, nil)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			got := Schema{}
+			if err := json.NewDecoder(artifacts[0].Data).Decode(&got); err != nil {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			wantJSON := mustMarshal(tc.want)
+			gotJSON := mustMarshal(got.Properties)
+			if !slices.Equal(wantJSON, gotJSON) {
+				t.Errorf("schemas are not equal.\n want: %s\n got: %s", wantJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestResourcer_SchemaComposition(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment string
+		isValid bool
+		want    Schema
+	}{
+		{
+			name:    "conditional",
+			isValid: true,
+			payment: `package api
+
+// +openapi:schema:title="payment"
+// +openapi:schema:if="Kind=card"
+// +openapi:schema:then=["CardNumber"]
+// +openapi:schema:else=["IBAN"]
+// +openapi:schema:not=["CardNumber", "IBAN"]
+// +openapi:schema:const="Version=2"
+// +openapi:schema:additionalProperties=false
+// +openapi:schema:minProperties=2
+// +openapi:schema:maxProperties=4
+type Payment struct {
+	Version    int
+	Kind       string
+	CardNumber string
+	IBAN       string
+}
+`,
+			want: Schema{
+				If: &Schema{
+					Properties: map[string]*Schema{"kind": {Const: "card"}},
+					Required:   []string{"kind"},
+				},
+				Then:                 &Schema{Required: []string{"cardNumber"}},
+				Else:                 &Schema{Required: []string{"iban"}},
+				Not:                  &Schema{Required: []string{"cardNumber", "iban"}},
+				Properties:           map[string]*Schema{"version": {Type: integerType, Const: 2}},
+				AdditionalProperties: &Schema{False: true},
+				MinProperties:        2,
+				MaxProperties:        4,
+			},
+		},
+		{
+			name:    "allOf",
+			isValid: true,
+			payment: `package api
+
+// +openapi:schema:title="payment"
+// +openapi:schema:allOf="Kind=card:CardNumber"
+// +openapi:schema:allOf="Kind=sepa:IBAN"
+type Payment struct {
+	Kind       string
+	CardNumber string
+	IBAN       string
+}
+`,
+			want: Schema{
+				AllOf: []*Schema{
+					{
+						If:   &Schema{Properties: map[string]*Schema{"kind": {Const: "card"}}, Required: []string{"kind"}},
+						Then: &Schema{Required: []string{"cardNumber"}},
+					},
+					{
+						If:   &Schema{Properties: map[string]*Schema{"kind": {Const: "sepa"}}, Required: []string{"kind"}},
+						Then: &Schema{Required: []string{"iban"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "unknown field",
+			isValid: false,
+			payment: `package api
+
+// +openapi:schema:title="payment"
+// +openapi:schema:if="Kind=card"
+// +openapi:schema:then=["Number"]
+type Payment struct {
+	Kind string
+}
+`,
+		},
+		{
+			name:    "then without if",
+			isValid: false,
+			payment: `package api
+
+// +openapi:schema:title="payment"
+// +openapi:schema:then=["Kind"]
+type Payment struct {
+	Kind string
+}
+`,
+		},
+		{
+			name:    "value of wrong type",
+			isValid: false,
+			payment: `package api
+
+// +openapi:schema:title="payment"
+// +openapi:schema:const="Version=two"
+type Payment struct {
+	Version int
+}
+`,
+		},
+		{
+			name:    "minProperties greater than closed properties",
+			isValid: false,
+			payment: `package api
+
+// +openapi:schema:title="payment"
+// +openapi:schema:additionalProperties=false
+// +openapi:schema:minProperties=2
+type Payment struct {
+	Kind string
+}
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, map[string]string{"api/payment.go": tc.payment}, nil)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			got := Schema{}
+			if err := json.NewDecoder(artifacts[0].Data).Decode(&got); err != nil {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			// only the composition keywords and properties with a constant
+			// value are compared.
+			var consts map[string]*Schema
+			for name, prop := range got.Properties {
+				if prop.Const == nil {
+					continue
+				}
+				if consts == nil {
+					consts = make(map[string]*Schema)
+				}
+				consts[name] = prop
+			}
+			composition := Schema{
+				AllOf:                got.AllOf,
+				If:                   got.If,
+				Then:                 got.Then,
+				Else:                 got.Else,
+				Not:                  got.Not,
+				Properties:           consts,
+				AdditionalProperties: got.AdditionalProperties,
+				MinProperties:        got.MinProperties,
+				MaxProperties:        got.MaxProperties,
+			}
+			wantJSON := mustMarshal(tc.want)
+			gotJSON := mustMarshal(composition)
+			if !slices.Equal(wantJSON, gotJSON) {
+				t.Errorf("schemas are not equal.\n want: %s\n got: %s", wantJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestResourcer_SchemaValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   string
+		isValid bool
+		want    map[string]*Schema
+	}{
+		{
+			name:    "valid examples and defaults",
+			isValid: true,
+			order: `package api
+
+// +openapi:schema:title="order"
+type Order struct {
+	// +openapi:schema:maximum=10
+	// +openapi:schema:examples=[1, 5]
+	// +openapi:schema:default="1"
+	Quantity int
+	// +openapi:schema:enum=["open", "closed"]
+	// +openapi:schema:default="open"
+	Status string
+	// +openapi:schema:default="[1, 2]"
+	Codes []int
+}
+`,
+			want: map[string]*Schema{
+				"quantity": {Type: integerType, Examples: []any{1, 5}, Default: 1, Maximum: "10"},
+				"status":   {Type: stringType, Enum: []any{"open", "closed"}, Default: "open"},
+				"codes":    {Type: arrayType, Items: &Schema{Type: integerType}, Default: []any{1, 2}},
+			},
+		},
+		{
+			name:    "example violates maximum",
+			isValid: false,
+			order: `package api
+
+// +openapi:schema:title="order"
+type Order struct {
+	// +openapi:schema:maximum=10
+	// +openapi:schema:examples=[5, 11]
+	Quantity int
+}
+`,
+		},
+		{
+			name:    "default violates enum",
+			isValid: false,
+			order: `package api
+
+// +openapi:schema:title="order"
+type Order struct {
+	// +openapi:schema:enum=["open", "closed"]
+	// +openapi:schema:default="pending"
+	Status string
+}
+`,
+		},
+		{
+			name:    "default of wrong type",
+			isValid: false,
+			order: `package api
+
+// +openapi:schema:title="order"
+type Order struct {
+	// +openapi:schema:default="many"
+	Quantity int
+}
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, map[string]string{"api/order.go": tc.order}, nil)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			got := Schema{}
+			if err := json.NewDecoder(artifacts[0].Data).Decode(&got); err != nil {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			wantJSON := mustMarshal(tc.want)
+			gotJSON := mustMarshal(got.Properties)
+			if !slices.Equal(wantJSON, gotJSON) {
+				t.Errorf("schemas are not equal.\n want: %s\n got: %s", wantJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestIsValidSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  *Schema
+		isValid bool
+	}{
+		{name: "valid", schema: &Schema{Type: stringType, MinLength: 1, ContentEncoding: "base64"}, isValid: true},
+		{name: "negative minItems", schema: &Schema{Type: arrayType, MinItems: -1}, isValid: false},
+		{name: "unknown type", schema: &Schema{Type: "text"}, isValid: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidSchema("Test", tc.schema)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+		})
+	}
+}
+
+func TestResourcer_SchemaNamed(t *testing.T) {
+	const draft = "https://json-schema.org/draft/2020-12/schema"
+	tests := []struct {
+		name    string
+		user    string
+		isValid bool
+		want    map[string]Schema
+	}{
+		{
+			name:    "named types and aliases",
+			isValid: true,
+			user: `package api
+
+// +openapi:schema:title="user"
+type User struct {
+	Email   Email
+	Mails   []Email
+	Tags    Tags
+	Age     Age
+	Contact Contact
+	Names   Names
+}
+
+// +openapi:schema:format="email"
+// +openapi:schema:maxLength=254
+type Email string
+
+// +openapi:schema:minItems=1
+// +openapi:schema:uniqueItems=true
+type Tags []string
+
+// +openapi:schema:minimum=0
+// +openapi:schema:maximum=150
+type Age int
+
+// +openapi:schema:title="contact"
+type Contact = Email
+
+type Names []string
+`,
+			want: map[string]Schema{
+				"user.json": {
+					ID:    "user.json",
+					Draft: draft,
+					Title: "user",
+					Type:  objectType,
+					Properties: map[string]*Schema{
+						"email":   {Ref: "email.json"},
+						"mails":   {Type: arrayType, Items: &Schema{Ref: "email.json"}},
+						"tags":    {Ref: "tags.json"},
+						"age":     {Ref: "age.json"},
+						"contact": {Ref: "contact.json"},
+						"names":   {Type: arrayType, Items: &Schema{Type: stringType}},
+					},
+				},
+				"email.json": {ID: "email.json", Draft: draft, Type: stringType, Format: "email", MaxLength: 254},
+				"tags.json": {
+					ID:          "tags.json",
+					Draft:       draft,
+					Type:        arrayType,
+					Items:       &Schema{Type: stringType},
+					MinItems:    1,
+					UniqueItems: true,
+				},
+				"age.json":     {ID: "age.json", Draft: draft, Type: integerType, Minimum: "0", Maximum: "150"},
+				"contact.json": {ID: "contact.json", Draft: draft, Ref: "email.json", Title: "contact"},
+			},
+		},
+		{
+			name:    "recursive named type",
+			isValid: true,
+			user: `package api
+
+// +openapi:schema:title="tree"
+type Tree map[string]Tree
+`,
+			want: map[string]Schema{
+				"tree.json": {
+					ID:                   "tree.json",
+					Draft:                draft,
+					Title:                "tree",
+					Type:                 objectType,
+					AdditionalProperties: &Schema{Ref: "tree.json"},
+				},
+			},
+		},
+		{
+			name:    "recursive named type without markers",
+			isValid: false,
+			user: `package api
+
+// +openapi:schema:title="user"
+type User struct {
+	Tree Tree
+}
+
+type Tree map[string]Tree
+`,
+		},
+		{
+			name:    "fractional minimum of integer type",
+			isValid: false,
+			user: `package api
+
+// +openapi:schema:minimum=0.5
+type Age int
+`,
+		},
+		{
+			name:    "example violates pattern",
+			isValid: false,
+			user: `package api
+
+// +openapi:schema:pattern="^[A-Z]+$"
+// +openapi:schema:examples=["abc"]
+type Code string
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, map[string]string{"api/user.go": tc.user}, nil)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			schemas := make(map[string]*Schema, len(artifacts))
+			for _, artifact := range artifacts {
+				schema := Schema{}
+				if err := json.NewDecoder(artifact.Data).Decode(&schema); err != nil {
+					t.Fatalf("unexpected err occured: %s", err)
+				}
+				schemas[artifact.Name] = &schema
+			}
+			if got := slices.Sorted(maps.Keys(schemas)); !slices.Equal(got, slices.Sorted(maps.Keys(tc.want))) {
+				t.Fatalf("artifacts are not equal.\n want: %v\n got: %v", slices.Sorted(maps.Keys(tc.want)), got)
+			}
+			for filename, schema := range tc.want {
+				wantJSON := mustMarshal(schema)
+				gotJSON := mustMarshal(schemas[filename])
+				if !slices.Equal(wantJSON, gotJSON) {
+					t.Errorf("schemas of %s are not equal.\n want: %s\n got: %s", filename, wantJSON, gotJSON)
+				}
+			}
+		})
+	}
+}
+
+func TestResourcer_SchemaInline(t *testing.T) {
+	const draft = "https://json-schema.org/draft/2020-12/schema"
+	address := &Schema{Type: objectType, Properties: map[string]*Schema{"street": {Type: stringType}}}
+	tests := []struct {
+		name    string
+		order   string
+		cfg     map[string]any
+		isValid bool
+		want    map[string]Schema
+	}{
+		{
+			name:    "inline marker",
+			isValid: true,
+			order: `package api
+
+// +openapi:schema:title="order"
+type Order struct {
+	// +openapi:schema:inline
+	Shipping Address
+	Billing  Address
+}
+
+type Address struct {
+	Street string
+}
+`,
+			want: map[string]Schema{
+				"order.json": {
+					ID:    "order.json",
+					Draft: draft,
+					Title: "order",
+					Type:  objectType,
+					Properties: map[string]*Schema{
+						"shipping": address,
+						"billing":  {Ref: "address.json"},
+					},
+				},
+				"address.json": {ID: "address.json", Draft: draft, Type: objectType, Properties: address.Properties},
+			},
+		},
+		{
+			name:    "inline config",
+			isValid: true,
+			cfg:     map[string]any{"schema": map[string]any{"inline": true}},
+			order: `package api
+
+// +openapi:schema:title="order"
+type Order struct {
+	Shipping Address
+	Items    []Item
+}
+
+type Address struct {
+	Street string
+}
+
+type Item struct {
+	Quantity int
+}
+`,
+			want: map[string]Schema{
+				"order.json": {
+					ID:    "order.json",
+					Draft: draft,
+					Title: "order",
+					Type:  objectType,
+					Properties: map[string]*Schema{
+						"shipping": address,
+						"items": {
+							Type: arrayType,
+							Items: &Schema{
+								Type:       objectType,
+								Properties: map[string]*Schema{"quantity": {Type: integerType}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "recursive struct is referenced",
+			isValid: true,
+			cfg:     map[string]any{"schema": map[string]any{"inline": true}},
+			order: `package api
+
+// +openapi:schema:title="node"
+type Node struct {
+	Children []*Node
+}
+`,
+			want: map[string]Schema{
+				"node.json": {
+					ID:    "node.json",
+					Draft: draft,
+					Title: "node",
+					Type:  objectType,
+					Properties: map[string]*Schema{
+						"children": {Type: arrayType, Items: &Schema{Ref: "node.json"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "annotations next to reference",
+			isValid: true,
+			cfg:     map[string]any{"schema": map[string]any{"nullable": true}},
+			order: `package api
+
+// +openapi:schema:title="order"
+type Order struct {
+	// +openapi:schema:description="address of the invoice"
+	// +openapi:schema:readOnly=true
+	Billing Address
+	// +openapi:schema:description="address of the delivery"
+	Shipping *Address
+}
+
+type Address struct {
+	Street string
+}
+`,
+			want: map[string]Schema{
+				"order.json": {
+					ID:    "order.json",
+					Draft: draft,
+					Title: "order",
+					Type:  objectType,
+					Properties: map[string]*Schema{
+						"billing": {Ref: "address.json", Desc: "address of the invoice", ReadOnly: true},
+						"shipping": {
+							Desc:  "address of the delivery",
+							OneOf: []*Schema{{Ref: "address.json"}, {Type: nullType}},
+						},
+					},
+				},
+				"address.json": {ID: "address.json", Draft: draft, Type: objectType, Properties: address.Properties},
+			},
+		},
+		{
+			name:    "string constraint on reference",
+			isValid: false,
+			order: `package api
+
+// +openapi:schema:title="order"
+type Order struct {
+	// +openapi:schema:maxLength=5
+	Billing Address
+}
+
+type Address struct {
+	Street string
+}
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, map[string]string{"api/order.go": tc.order}, tc.cfg)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			schemas := make(map[string]*Schema, len(artifacts))
+			for _, artifact := range artifacts {
+				schema := Schema{}
+				if err := json.NewDecoder(artifact.Data).Decode(&schema); err != nil {
+					t.Fatalf("unexpected err occured: %s", err)
+				}
+				schemas[artifact.Name] = &schema
+			}
+			if got := slices.Sorted(maps.Keys(schemas)); !slices.Equal(got, slices.Sorted(maps.Keys(tc.want))) {
+				t.Fatalf("artifacts are not equal.\n want: %v\n got: %v", slices.Sorted(maps.Keys(tc.want)), got)
+			}
+			for filename, schema := range tc.want {
+				wantJSON := mustMarshal(schema)
+				gotJSON := mustMarshal(schemas[filename])
+				if !slices.Equal(wantJSON, gotJSON) {
+					t.Errorf("schemas of %s are not equal.\n want: %s\n got: %s", filename, wantJSON, gotJSON)
+				}
+			}
+		})
+	}
+}
+
+func TestResourcer_SchemaExtensions(t *testing.T) {
+	const draft = "https://json-schema.org/draft/2020-12/schema"
+	tests := []struct {
+		name    string
+		user    string
+		cfg     map[string]any
+		isValid bool
+		want    map[string]Schema
+	}{
+		{
+			name:    "markers",
+			isValid: true,
+			user: `package api
+
+// +openapi:schema:title="user"
+// +openapi:schema:extension="x-internal=true"
+type User struct {
+	// +openapi:schema:extension="x-order=1"
+	// +openapi:schema:extension="x-go-type=Name"
+	Name string
+	// +openapi:schema:extension="x-order=2"
+	Email Email
+}
+
+// +openapi:schema:format="email"
+// +openapi:schema:extension="x-codes=[1, 2]"
+type Email string
+`,
+			want: map[string]Schema{
+				"user.json": {
+					ID:         "user.json",
+					Draft:      draft,
+					Title:      "user",
+					Type:       objectType,
+					Extensions: map[string]any{"x-internal": true},
+					Properties: map[string]*Schema{
+						"name":  {Type: stringType, Extensions: map[string]any{"x-order": json.Number("1"), "x-go-type": "Name"}},
+						"email": {Ref: "email.json", Extensions: map[string]any{"x-order": json.Number("2")}},
+					},
+				},
+				"email.json": {
+					ID:         "email.json",
+					Draft:      draft,
+					Type:       stringType,
+					Format:     "email",
+					Extensions: map[string]any{"x-codes": []any{json.Number("1"), json.Number("2")}},
+				},
+			},
+		},
+		{
+			name:    "defaults",
+			isValid: true,
+			cfg: map[string]any{"schema": map[string]any{"extensions": map[string]any{
+				"goPackage": true,
+				"goType":    true,
+				"values":    map[string]any{"x-internal": false},
+			}}},
+			user: `package api
+
+// +openapi:schema:title="user"
+// +openapi:schema:extension="x-internal=true"
+type User struct {
+	Name string
+}
+`,
+			want: map[string]Schema{
+				"user.json": {
+					ID:    "user.json",
+					Draft: draft,
+					Title: "user",
+					Type:  objectType,
+					Extensions: map[string]any{
+						"x-go-package": "loadertest/api",
+						"x-go-type":    "User",
+						"x-internal":   true,
+					},
+					Properties: map[string]*Schema{"name": {Type: stringType}},
+				},
+			},
+		},
+		{
+			name:    "key without prefix",
+			isValid: false,
+			user: `package api
+
+// +openapi:schema:title="user"
+// +openapi:schema:extension="order=1"
+type User struct {
+	Name string
+}
+`,
+		},
+		{
+			name:    "config key without prefix",
+			isValid: false,
+			cfg: map[string]any{"schema": map[string]any{"extensions": map[string]any{
+				"values": map[string]any{"internal": true},
+			}}},
+			user: `package api
+
+// +openapi:schema:title="user"
+type User struct {
+	Name string
+}
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, map[string]string{"api/user.go": tc.user}, tc.cfg)
+			if err != nil && tc.isValid {
+				t.Fatalf("unexpected err occured: %s", err)
+			}
+			if err == nil && !tc.isValid {
+				t.Fatalf("expected an error but none occured")
+			}
+			if !tc.isValid {
+				t.Logf("expected error occured: %s", err)
+				return
+			}
+			schemas := make(map[string]*Schema, len(artifacts))
+			for _, artifact := range artifacts {
+				schema := Schema{}
+				if err := json.NewDecoder(artifact.Data).Decode(&schema); err != nil {
+					t.Fatalf("unexpected err occured: %s", err)
+				}
+				schemas[artifact.Name] = &schema
+			}
+			if got := slices.Sorted(maps.Keys(schemas)); !slices.Equal(got, slices.Sorted(maps.Keys(tc.want))) {
+				t.Fatalf("artifacts are not equal.\n want: %v\n got: %v", slices.Sorted(maps.Keys(tc.want)), got)
+			}
+			for filename, schema := range tc.want {
+				wantJSON := mustMarshal(schema)
+				gotJSON := mustMarshal(schemas[filename])
+				if !slices.Equal(wantJSON, gotJSON) {
+					t.Errorf("schemas of %s are not equal.\n want: %s\n got: %s", filename, wantJSON, gotJSON)
+				}
+			}
+		})
+	}
+}
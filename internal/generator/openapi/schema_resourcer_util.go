@@ -1,6 +1,7 @@
 package openapi
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go/types"
@@ -16,13 +17,35 @@ type Schema struct {
 	Draft string   `json:"$schema,omitzero"`
 	Ref   string   `json:"$ref,omitzero"`
 	Type  jsonType `json:"type,omitzero"`
+	// Nullable allows null in addition to the type of the schema. The type
+	// will be rendered as list e.g. `["string", "null"]`.
+	Nullable bool `json:"-"`
+	// False marks the boolean schema `false` which does not allow any value
+	// e.g. `"additionalProperties": false`.
+	False bool `json:"-"`
+	// Extensions are the vendor extensions of the schema e.g. `x-order`. They
+	// are rendered after all the keywords sorted by their key.
+	Extensions map[string]any `json:"-"`
+
+	Defs map[string]*Schema `json:"$defs,omitzero"`
 
 	OneOf []*Schema `json:"oneOf,omitzero"`
 	AnyOf []*Schema `json:"anyOf,omitzero"`
+	AllOf []*Schema `json:"allOf,omitzero"`
 	Not   *Schema   `json:"not,omitzero"`
 
+	// conditional
+	If   *Schema `json:"if,omitzero"`
+	Then *Schema `json:"then,omitzero"`
+	Else *Schema `json:"else,omitzero"`
+
+	// Discriminator is the OpenAPI extension to identify the schema of a
+	// `oneOf` by the value of a property.
+	Discriminator *DiscriminatorObject `json:"discriminator,omitzero"`
+
 	// agnostic
-	Enum []any `json:"enum,omitzero"`
+	Enum  []any `json:"enum,omitzero"`
+	Const any   `json:"const,omitzero"`
 
 	// annotations
 	Title      string `json:"title,omitzero"`
@@ -31,7 +54,7 @@ type Schema struct {
 	Deprecated bool   `json:"deprecated,omitzero"`
 	WriteOnly  bool   `json:"writeOnly,omitzero"`
 	ReadOnly   bool   `json:"readOnly,omitzero"`
-	Default    string `json:"default,omitzero"`
+	Default    any    `json:"default,omitzero"`
 
 	// array
 	MaxItems    int64   `json:"maxItems,omitzero"`
@@ -45,48 +68,144 @@ type Schema struct {
 	AdditionalProperties *Schema             `json:"additionalProperties,omitzero"`
 	PatternProperties    map[string]*Schema  `json:"patternProperties,omitzero,omitempty"`
 	DependentRequired    map[string][]string `json:"dependentRequired,omitzero,omitempty"`
+	MinProperties        int64               `json:"minProperties,omitzero"`
+	MaxProperties        int64               `json:"maxProperties,omitzero"`
 
 	// string
 	MinLength        int64  `json:"minLength,omitzero"`
 	MaxLength        int64  `json:"maxLength,omitzero"`
 	Pattern          string `json:"pattern,omitzero"`
-	ContentEncoding  string `json:"contentEnconding,omitzero"`
+	ContentEncoding  string `json:"contentEncoding,omitzero"`
 	ContentMediaType string `json:"contentMediaType,omitzero"`
 	Format           string `json:"format,omitzero"`
 
 	// number
-	Maximum          int64 `json:"maximum,omitzero"`
-	Minimum          int64 `json:"minimum,omitzero"`
-	ExclusiveMaximum int64 `json:"exclusiveMaximum,omitzero"`
-	ExclusiveMinimum int64 `json:"exclusiveMinimum,omitzero"`
-	MultipleOf       int64 `json:"multipleOf,omitzero"`
+	Maximum          json.Number `json:"maximum,omitzero"`
+	Minimum          json.Number `json:"minimum,omitzero"`
+	ExclusiveMaximum json.Number `json:"exclusiveMaximum,omitzero"`
+	ExclusiveMinimum json.Number `json:"exclusiveMinimum,omitzero"`
+	MultipleOf       json.Number `json:"multipleOf,omitzero"`
 }
 
-func newSchema(typ types.Type, cfg *config) (Schema, error) {
+func newSchema(typ types.Type, r *resolver) (Schema, error) {
+	// named types with schema markers are always referenced by name.
+	if ref := r.namedRef(typ); ref != nil {
+		id, err := r.refOf(ref.obj)
+		return Schema{Ref: id}, err
+	}
+	schema, isWellKnown, err := r.wellKnown(typ)
+	if err != nil {
+		return Schema{}, err
+	}
+	if isWellKnown {
+		return *schema, nil
+	}
 	switch t := typ.(type) {
 	case *types.Named:
-		return newSchemaFromNamed(t, cfg)
+		return newSchemaFromNamed(t, r)
 	case *types.Alias:
-		return newSchema(t.Rhs(), cfg)
+		return newSchema(t.Rhs(), r)
 	}
 
 	switch t := typ.Underlying().(type) {
 	case *types.Basic:
 		return newBasicSchema(t)
 	case *types.Slice:
-		return newArraySchemaFromSlice(t, cfg)
+		return newArraySchemaFromSlice(t, r)
 	case *types.Array:
-		return newArraySchemaFromArray(t, cfg)
+		return newArraySchemaFromArray(t, r)
 	case *types.Map:
-		return newObjectSchemaFromMap(t, cfg)
+		return newObjectSchemaFromMap(t, r)
 	case *types.Interface:
 		return newSchemaFromIface(t)
 	case *types.Pointer:
-		return newSchema(t.Elem(), cfg)
+		return newSchema(t.Elem(), r)
 	}
 	return Schema{}, fmt.Errorf("type is not supported: %s", typ)
 }
 
+// MarshalJSON renders the type of a nullable schema as list of the type and
+// null and adds the extensions to the keywords.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.False {
+		return []byte("false"), nil
+	}
+	data, err := s.marshalKeywords()
+	if err != nil || len(s.Extensions) == 0 {
+		return data, err
+	}
+	return appendExtensions(data, s.Extensions)
+}
+
+func (s Schema) marshalKeywords() ([]byte, error) {
+	type schema Schema
+	if !s.Nullable || s.Type == "" {
+		return json.Marshal(schema(s))
+	}
+	return json.Marshal(struct {
+		schema
+		Type []jsonType `json:"type"`
+	}{schema(s), []jsonType{s.Type, nullType}})
+}
+
+// UnmarshalJSON is the inverse of MarshalJSON allowing the type to be a
+// single type or a list of a type and null.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var isTrue bool
+	if err := json.Unmarshal(data, &isTrue); err == nil {
+		*s = Schema{False: !isTrue}
+		return nil
+	}
+	type schema Schema
+	aux := struct {
+		*schema
+		Type json.RawMessage `json:"type"`
+	}{schema: (*schema)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	exts, err := extensionsOf(data)
+	if err != nil {
+		return err
+	}
+	s.Extensions = exts
+	if len(aux.Type) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(aux.Type, &s.Type); err == nil {
+		return nil
+	}
+	var types []jsonType
+	if err := json.Unmarshal(aux.Type, &types); err != nil {
+		return err
+	}
+	for _, typ := range types {
+		if typ == nullType {
+			s.Nullable = true
+			continue
+		}
+		s.Type = typ
+	}
+	return nil
+}
+
+// newRootSchemaOf returns the schema of the named type `obj` which is the root
+// of an artifact or embedded into a document.
+func newRootSchemaOf(obj types.Object, cfg *config, r *resolver) (Schema, error) {
+	schema := Schema{}
+	applyDefaultExtensions(&schema, obj, cfg)
+	// embedded schemas are identified by their location in the document.
+	if r.isEmbedded() {
+		return schema, nil
+	}
+	id, err := r.id(obj)
+	if err != nil {
+		return _schemaz, err
+	}
+	schema.ID, schema.Draft = id, cfg.Schema.Draft
+	return schema, nil
+}
+
 func newBasicSchema(t *types.Basic) (Schema, error) {
 	return Schema{
 		Type: jsonTypeOf(t),
@@ -100,8 +219,8 @@ func newSchemaFromIface(t *types.Interface) (Schema, error) {
 	return Schema{}, nil
 }
 
-func newArraySchemaFromSlice(t *types.Slice, cfg *config) (Schema, error) {
-	elemSchema, err := newSchema(t.Elem(), cfg)
+func newArraySchemaFromSlice(t *types.Slice, r *resolver) (Schema, error) {
+	elemSchema, err := newSchema(t.Elem(), r)
 	if err != nil {
 		return _schemaz, err
 	}
@@ -112,8 +231,8 @@ func newArraySchemaFromSlice(t *types.Slice, cfg *config) (Schema, error) {
 	return schema, nil
 }
 
-func newArraySchemaFromArray(t *types.Array, cfg *config) (Schema, error) {
-	elemSchema, err := newSchema(t.Elem(), cfg)
+func newArraySchemaFromArray(t *types.Array, r *resolver) (Schema, error) {
+	elemSchema, err := newSchema(t.Elem(), r)
 	if err != nil {
 		return _schemaz, err
 	}
@@ -125,7 +244,7 @@ func newArraySchemaFromArray(t *types.Array, cfg *config) (Schema, error) {
 	return schema, nil
 }
 
-func newObjectSchemaFromMap(t *types.Map, cfg *config) (Schema, error) {
+func newObjectSchemaFromMap(t *types.Map, r *resolver) (Schema, error) {
 	basic, isBasic := t.Key().Underlying().(*types.Basic)
 	if !isBasic {
 		return _schemaz, errors.New("map is not indexed with an basic type e.g. int, string etc.")
@@ -133,7 +252,7 @@ func newObjectSchemaFromMap(t *types.Map, cfg *config) (Schema, error) {
 	if basic.Kind() != types.String {
 		return _schemaz, errors.New("map has to be indexed with string")
 	}
-	valueSchema, err := newSchema(t.Elem(), cfg)
+	valueSchema, err := newSchema(t.Elem(), r)
 	if err != nil {
 		return _schemaz, err
 	}
@@ -143,24 +262,76 @@ func newObjectSchemaFromMap(t *types.Map, cfg *config) (Schema, error) {
 	}, nil
 }
 
-func newObjectSchemaFromStruct(name string, cfg *config) (Schema, error) {
-	id, err := id(name, cfg.Schema.IDBaseURL, cfg.Schema.Formats.Filename)
+// newObjectSchemaFromStruct returns a reference to the schema of the struct
+// `n` or its inlined schema if the field inlines the structs it references.
+// Recursive structs e.g. `type Node struct { Children []*Node }` are always
+// referenced by name which allows them to reference themselves.
+func newObjectSchemaFromStruct(n *types.Named, r *resolver) (Schema, error) {
+	if r.inline != nil {
+		ref, err := r.ref(n)
+		if err != nil {
+			return _schemaz, err
+		}
+		if ref.canInline() {
+			return r.inline(ref)
+		}
+	}
+	ref, err := r.refOf(n.Origin().Obj())
 	if err != nil {
 		return _schemaz, err
 	}
 	return Schema{
-		Ref: id,
+		Ref: ref,
 	}, nil
 }
 
-func newSchemaFromNamed(n *types.Named, cfg *config) (Schema, error) {
+func newSchemaFromNamed(n *types.Named, r *resolver) (Schema, error) {
 	switch t := n.Underlying().(type) {
 	case *types.Struct:
-		return newObjectSchemaFromStruct(n.Obj().Name(), cfg)
+		return newObjectSchemaFromStruct(n, r)
 	case *types.Basic:
+		if enum := r.enumOf(n); enum != nil {
+			ref, err := r.refOf(enum.obj)
+			return Schema{Ref: ref}, err
+		}
 		return newBasicSchema(t)
+	case *types.Interface:
+		return newPolymorphicSchema(n, t, r)
+	}
+	// named types without a schema of their own are inlined.
+	return newSchema(n.Underlying(), r)
+}
+
+// newPolymorphicSchema returns a `oneOf` of all the structs implementing the
+// interface `n` if it has a discriminator.
+func newPolymorphicSchema(n *types.Named, t *types.Interface, r *resolver) (Schema, error) {
+	iface := r.ifaceOf(n)
+	if iface == nil {
+		return newSchemaFromIface(t)
+	}
+	schema := Schema{
+		OneOf: make([]*Schema, 0, len(iface.impls)),
+		Discriminator: &DiscriminatorObject{
+			PropertyName: iface.discriminator,
+			Mapping:      make(map[string]string, len(iface.impls)),
+		},
+	}
+	for _, impl := range iface.impls {
+		ref, err := r.refOf(impl.ref.obj)
+		if err != nil {
+			return _schemaz, err
+		}
+		schema.OneOf = append(schema.OneOf, &Schema{Ref: ref})
+		schema.Discriminator.Mapping[impl.value] = ref
 	}
-	return _schemaz, nil
+	return schema, nil
+}
+
+// DiscriminatorObject identifies the schema of a `oneOf` by the value of the
+// property `PropertyName`.
+type DiscriminatorObject struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitzero"`
 }
 
 type jsonType string
@@ -216,9 +387,11 @@ func jsonTypeOf(typ types.Type) jsonType {
 	}
 }
 
-func id(name, baseURL string, nc NamingConvention) (string, error) {
-	id := nc.Format(name) + ".json"
-	idURL, err := url.JoinPath(baseURL, id)
+// id returns the `$id` of the schema `name`. The file extension follows the
+// configured output format.
+func id(name string, cfg *config) (string, error) {
+	id := cfg.Schema.Formats.Filename.Format(name) + cfg.Output.ext()
+	idURL, err := url.JoinPath(cfg.Schema.IDBaseURL, id)
 	if err != nil {
 		return "", err
 	}
@@ -8,6 +8,18 @@ import (
 	docv1 "github.com/naivary/codemark/api/doc/v1"
 )
 
+// stringSchemaOf returns the schema to which a string constraint is applied.
+// This is either the schema itself, the items of an array or the additional
+// properties of an object.
+func stringSchemaOf(schema *Schema) *Schema {
+	for _, s := range []*Schema{schema, schema.Items, schema.AdditionalProperties} {
+		if s != nil && s.Type == stringType {
+			return s
+		}
+	}
+	return nil
+}
+
 type Pattern string
 
 func (p Pattern) Doc() docv1.Option {
@@ -21,19 +33,12 @@ func (p Pattern) apply(schema *Schema) error {
 	if !p.isValidRegExp() {
 		return fmt.Errorf("pattern is not a valid regular expression: %s", regExp)
 	}
-	if schema.Type == stringType {
-		schema.Pattern = regExp
-		return nil
-	}
-	if schema.Items.Type == stringType {
-		schema.Items.Pattern = regExp
-		return nil
-	}
-	if schema.AdditionalProperties.Type == stringType {
-		schema.AdditionalProperties.Pattern = regExp
-		return nil
+	target := stringSchemaOf(schema)
+	if target == nil {
+		return errors.New("pattern is only appliable to strings, objects with value of type string and arrays/slices of type string")
 	}
-	return errors.New("pattern is only appliable to strings, objects with value of type string and arrays/slices of type string")
+	target.Pattern = regExp
+	return nil
 }
 
 func (p Pattern) isValidRegExp() bool {
@@ -45,7 +50,7 @@ type MinLength int64
 
 func (m MinLength) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Minimum length of the string",
+		Desc: "Minimum length of the string",
 	}
 }
 
@@ -54,26 +59,19 @@ func (m MinLength) apply(schema *Schema) error {
 	if minLen < 0 {
 		return fmt.Errorf("minLength cannot be negative: %d", minLen)
 	}
-	if schema.Type == stringType {
-		schema.MinLength = minLen
-		return nil
+	target := stringSchemaOf(schema)
+	if target == nil {
+		return errors.New("minLength is only appliable to strings, objects with value of type string and arrays/slices of type string")
 	}
-	if schema.Items.Type == stringType {
-		schema.Items.MinLength = minLen
-		return nil
-	}
-	if schema.AdditionalProperties.Type == stringType {
-		schema.AdditionalProperties.MinLength = minLen
-		return nil
-	}
-	return errors.New("minLength is only appliable to strings, objects with value of type string and arrays/slices of type string")
+	target.MinLength = minLen
+	return nil
 }
 
 type MaxLength int
 
 func (m MaxLength) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Maximum length of the string",
+		Desc: "Maximum length of the string",
 	}
 }
 
@@ -82,26 +80,19 @@ func (m MaxLength) apply(schema *Schema) error {
 	if maxLen < 0 {
 		return fmt.Errorf("minLength cannot be negative: %d", maxLen)
 	}
-	if schema.Type == stringType {
-		schema.MaxLength = maxLen
-		return nil
-	}
-	if schema.Items.Type == stringType {
-		schema.Items.MaxLength = maxLen
-		return nil
+	target := stringSchemaOf(schema)
+	if target == nil {
+		return errors.New("maxLength is only appliable to strings, objects with value of type string and arrays/slices of type string")
 	}
-	if schema.AdditionalProperties.Type == stringType {
-		schema.AdditionalProperties.MaxLength = maxLen
-		return nil
-	}
-	return errors.New("maxLength is only appliable to strings, objects with value of type string and arrays/slices of type string")
+	target.MaxLength = maxLen
+	return nil
 }
 
 type ContentEncoding string
 
 func (c ContentEncoding) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Content encoding",
+		Desc: "Content encoding",
 	}
 }
 
@@ -110,26 +101,19 @@ func (c ContentEncoding) apply(schema *Schema) error {
 	if len(c) == 0 {
 		return errors.New("contentEncoding marker cannot be empty")
 	}
-	if schema.Type == stringType {
-		schema.ContentEncoding = encoding
-		return nil
-	}
-	if schema.Items.Type == stringType {
-		schema.Items.ContentEncoding = encoding
-		return nil
+	target := stringSchemaOf(schema)
+	if target == nil {
+		return errors.New("contentEncoding is only appliable to strings, objects with value of type string and arrays/slices of type string")
 	}
-	if schema.AdditionalProperties.Type == stringType {
-		schema.AdditionalProperties.ContentEncoding = encoding
-		return nil
-	}
-	return errors.New("contentEncoding is only appliable to strings, objects with value of type string and arrays/slices of type string")
+	target.ContentEncoding = encoding
+	return nil
 }
 
 type ContentMediaType string
 
 func (c ContentMediaType) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Content media type",
+		Desc: "Content media type",
 	}
 }
 
@@ -138,26 +122,19 @@ func (c ContentMediaType) apply(schema *Schema) error {
 	if len(mediaType) == 0 {
 		return errors.New("contentMediaType cannot be empty")
 	}
-	if schema.Type == stringType {
-		schema.ContentMediaType = mediaType
-		return nil
-	}
-	if schema.Items.Type == stringType {
-		schema.Items.ContentMediaType = mediaType
-		return nil
+	target := stringSchemaOf(schema)
+	if target == nil {
+		return errors.New("contentMediaType is only appliable to strings, objects with value of type string and arrays/slices of type string")
 	}
-	if schema.AdditionalProperties.Type == stringType {
-		schema.AdditionalProperties.ContentMediaType = mediaType
-		return nil
-	}
-	return errors.New("contentMediaType is only appliable to strings, objects with value of type string and arrays/slices of type string")
+	target.ContentMediaType = mediaType
+	return nil
 }
 
 type Format string
 
 func (f Format) Doc() docv1.Option {
 	return docv1.Option{
-		Desc:    "Format",
+		Desc: "Format",
 	}
 }
 
@@ -166,17 +143,10 @@ func (f Format) apply(schema *Schema) error {
 	if len(format) == 0 {
 		return errors.New("format marker cannot be empty")
 	}
-	if schema.Type == stringType {
-		schema.Format = format
-		return nil
-	}
-	if schema.Items.Type == stringType {
-		schema.Items.Format = format
-		return nil
-	}
-	if schema.AdditionalProperties.Type == stringType {
-		schema.AdditionalProperties.Format = format
-		return nil
+	target := stringSchemaOf(schema)
+	if target == nil {
+		return errors.New("format is only appliable to strings, objects with value of type string and arrays/slices of type string")
 	}
-	return errors.New("format is only appliable to strings, objects with value of type string and arrays/slices of type string")
+	target.Format = format
+	return nil
 }
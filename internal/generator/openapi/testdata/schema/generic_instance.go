@@ -0,0 +1,15 @@
+package schema
+
+// +openapi:schema:title="user"
+type User struct {
+	Friends Page[Friend]
+}
+
+type Page[T any] struct {
+	Items []T
+	Next  string
+}
+
+type Friend struct {
+	Name string
+}
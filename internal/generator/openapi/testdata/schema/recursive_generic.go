@@ -0,0 +1,8 @@
+package schema
+
+// +openapi:schema:title="tree"
+type Tree[T any] struct {
+	Value    T
+	Children []Tree[T]
+	Parent   *Tree[T]
+}
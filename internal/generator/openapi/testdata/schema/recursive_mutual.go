@@ -0,0 +1,12 @@
+package schema
+
+// +openapi:schema:title="person"
+type Person struct {
+	Name     string
+	Employer *Company
+}
+
+type Company struct {
+	Employees []Person
+	Branches  map[string]*Company
+}
@@ -0,0 +1,8 @@
+package schema
+
+// +openapi:schema:title="node"
+type Node struct {
+	Value    string
+	Parent   *Node
+	Children []*Node
+}
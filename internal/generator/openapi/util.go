@@ -2,11 +2,15 @@ package openapi
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
+	"go/token"
 	"go/types"
+	"io"
+	"maps"
 	"reflect"
 
+	"golang.org/x/tools/go/packages"
+
 	docv1 "github.com/naivary/codemark/api/doc/v1"
 	genv1 "github.com/naivary/codemark/api/generator/v1"
 	infov1 "github.com/naivary/codemark/api/info/v1"
@@ -49,20 +53,69 @@ func makeOpts(resource string, opts ...*optionv1.Option) []*optionv1.Option {
 	return opts
 }
 
-func newArtifact(name string, manifests ...any) (*genv1.Artifact, error) {
+func newArtifact(name string, out outputConfig, manifests ...any) (*genv1.Artifact, error) {
 	artifact := &genv1.Artifact{
 		Name: name,
 		Data: bytes.NewBuffer(nil),
 	}
-	for _, manifest := range manifests {
-		err := json.NewEncoder(artifact.Data).Encode(&manifest)
-		if err != nil {
+	for i, manifest := range manifests {
+		if i > 0 && out.Format == YAMLOutput {
+			if _, err := io.WriteString(artifact.Data, "---\n"); err != nil {
+				return nil, err
+			}
+		}
+		if err := out.encode(artifact.Data, manifest); err != nil {
 			return nil, err
 		}
 	}
 	return artifact, nil
 }
 
+// funcsOf returns all the functions and methods of a package.
+func funcsOf(info *infov1.Information) map[types.Object]*infov1.FuncInfo {
+	funcs := maps.Clone(info.Funcs)
+	for _, strct := range info.Structs {
+		maps.Copy(funcs, strct.Methods)
+	}
+	for _, named := range info.Named {
+		maps.Copy(funcs, named.Methods)
+	}
+	return funcs
+}
+
+// namedTypesOf returns the named non-struct types and aliases of `info`.
+func namedTypesOf(info *infov1.Information) map[types.Object]infov1.Info {
+	named := make(map[types.Object]infov1.Info, len(info.Named)+len(info.Aliases))
+	for obj, info := range info.Named {
+		named[obj] = info
+	}
+	for obj, info := range info.Aliases {
+		named[obj] = info
+	}
+	return named
+}
+
+// hasSchemaOpts reports whether `info` has at least one schema marker. The
+// exclude marker only disables the enum of a named type and is not a keyword
+// of the schema.
+func hasSchemaOpts(info infov1.Info) bool {
+	for ident := range info.Options() {
+		if isResource(ident, _schemaResource) && ident != "openapi:schema:exclude" {
+			return true
+		}
+	}
+	return false
+}
+
+// fsetOf returns the file set of `pkg`. If `pkg` is nil e.g. because the type
+// is not part of the loaded project nil will be returned.
+func fsetOf(pkg *packages.Package) *token.FileSet {
+	if pkg == nil {
+		return nil
+	}
+	return pkg.Fset
+}
+
 func isResource(ident, resource string) bool {
 	return optionutil.ResourceOf(ident) == resource
 }
@@ -74,58 +127,3 @@ func flatten[T any](lists [][]T) []T {
 	}
 	return res
 }
-
-func collectInfos(info *infov1.Information) map[types.Object]infov1.Info {
-	all := make(map[types.Object]infov1.Info, infoCap(info))
-	for obj, str := range info.Structs {
-		all[obj] = str
-	}
-	for obj, alias := range info.Aliases {
-		all[obj] = alias
-	}
-	for obj, v := range info.Vars {
-		all[obj] = v
-	}
-	for obj, c := range info.Consts {
-		all[obj] = c
-	}
-	for obj, fn := range info.Funcs {
-		all[obj] = fn
-	}
-	for obj, iface := range info.Ifaces {
-		all[obj] = iface
-	}
-	for obj, imp := range info.Imports {
-		all[obj] = imp
-	}
-	for obj, named := range info.Named {
-		all[obj] = named
-	}
-	// TODO: Need types.Object for file or a fake one atleast
-	// for filename, file := range info.Files {
-	// 	all[nil] = file
-	// }
-	return all
-}
-
-func infoCap(info *infov1.Information) int {
-	return len(
-		info.Structs,
-	) + len(
-		info.Aliases,
-	) + len(
-		info.Vars,
-	) + len(
-		info.Consts,
-	) + len(
-		info.Funcs,
-	) + len(
-		info.Ifaces,
-	) + len(
-		info.Imports,
-	) + len(
-		info.Named,
-	) + len(
-		info.Files,
-	)
-}
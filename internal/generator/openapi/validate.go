@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// _metaSchema is the JSON Schema 2020-12 meta-schema which is embedded into
+// the jsonschema library. Compiling it does not access the network.
+var _metaSchema = sync.OnceValues(func() (*jsonschema.Schema, error) {
+	return jsonschema.NewCompiler().Compile("https://json-schema.org/draft/2020-12/schema")
+})
+
+// isValidSchema validates the generated `schema` of the type `name` against
+// the meta-schema.
+func isValidSchema(name string, schema *Schema) error {
+	meta, err := _metaSchema()
+	if err != nil {
+		return err
+	}
+	inst, err := toInstance(schema)
+	if err != nil {
+		return err
+	}
+	if err := meta.Validate(inst); err != nil {
+		return fmt.Errorf("schema of type `%s` is not a valid JSON Schema: %w", name, err)
+	}
+	return nil
+}
+
+// isValidValues validates the examples and the default of `schema` against
+// the schema itself. `subject` describes the schema in errors e.g. a field of a
+// struct. References to other schemas are not resolved and allow any value.
+func isValidValues(subject string, schema *Schema) error {
+	if len(schema.Examples) == 0 && schema.Default == nil {
+		return nil
+	}
+	// the values are validated against a copy of the schema without the
+	// annotations to be independent of the validated values.
+	clone, err := cloneSchema(schema)
+	if err != nil {
+		return err
+	}
+	local := withoutRefs(clone)
+	local.Examples, local.Default = nil, nil
+	inst, err := toInstance(local)
+	if err != nil {
+		return err
+	}
+	const url = "file:///values.json"
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(url, inst); err != nil {
+		return err
+	}
+	compiled, err := c.Compile(url)
+	if err != nil {
+		return err
+	}
+	for _, example := range schema.Examples {
+		if err := isValidValue(compiled, example); err != nil {
+			return fmt.Errorf("example `%v` of %s is invalid: %w", example, subject, err)
+		}
+	}
+	if schema.Default == nil {
+		return nil
+	}
+	if err := isValidValue(compiled, schema.Default); err != nil {
+		return fmt.Errorf("default `%v` of %s is invalid: %w", schema.Default, subject, err)
+	}
+	return nil
+}
+
+func isValidValue(schema *jsonschema.Schema, value any) error {
+	inst, err := toInstance(value)
+	if err != nil {
+		return err
+	}
+	return schema.Validate(inst)
+}
+
+// toInstance converts `v` to the representation of JSON values expected by
+// the validator.
+func toInstance(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
+
+// withoutRefs removes all the references of `schema` and its sub-schemas
+// which allows any value for them.
+func withoutRefs(schema *Schema) *Schema {
+	if schema == nil {
+		return nil
+	}
+	schema.Ref = ""
+	// a oneOf of references would match more than once if the references
+	// allow any value.
+	for _, one := range schema.OneOf {
+		if one.Ref != "" {
+			schema.OneOf, schema.Discriminator = nil, nil
+			break
+		}
+	}
+	subs := []*Schema{schema.Items, schema.AdditionalProperties, schema.Not, schema.If, schema.Then, schema.Else}
+	subs = append(subs, schema.OneOf...)
+	subs = append(subs, schema.AnyOf...)
+	subs = append(subs, schema.AllOf...)
+	for _, prop := range schema.Properties {
+		subs = append(subs, prop)
+	}
+	for _, sub := range subs {
+		withoutRefs(sub)
+	}
+	return schema
+}
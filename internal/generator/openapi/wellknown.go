@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+
+	"github.com/naivary/codemark/internal/wellknown"
+)
+
+// schemaOfEncoding returns the schema of a well-known type with the encoding
+// `enc`.
+func schemaOfEncoding(enc wellknown.Encoding) *Schema {
+	switch enc {
+	case wellknown.DateTime:
+		return &Schema{Type: stringType, Format: "date-time"}
+	case wellknown.Duration:
+		return &Schema{Type: integerType, Format: "int64"}
+	case wellknown.Bytes:
+		return &Schema{Type: stringType, ContentEncoding: "base64"}
+	case wellknown.IP:
+		return &Schema{Type: stringType, AnyOf: []*Schema{{Format: "ipv4"}, {Format: "ipv6"}}}
+	case wellknown.URI:
+		return &Schema{Type: stringType, Format: "uri"}
+	case wellknown.UUID:
+		return &Schema{Type: stringType, Format: "uuid"}
+	}
+	// any JSON value
+	return &Schema{}
+}
+
+// newTypeTable returns the table of well-known types including the types
+// configured by the user using the `schema.types` config which take
+// precedence.
+func newTypeTable(cfg *config) (map[string]*Schema, error) {
+	table := make(map[string]*Schema)
+	for key, enc := range wellknown.Types() {
+		table[key] = schemaOfEncoding(enc)
+	}
+	for key, schema := range cfg.Schema.Types {
+		// the schema is copied for every field of the type which fails if it
+		// cannot be encoded e.g. `maximum: abc`.
+		if _, err := cloneSchema(schema); err != nil {
+			return nil, fmt.Errorf("schema of type `%s` in `schema.types` is invalid: %w", key, err)
+		}
+		table[key] = schema
+	}
+	return table, nil
+}
+
+// wellKnown returns a copy of the schema of `typ` which can be modified if it
+// is a well-known type or implements `encoding.TextMarshaler`.
+func (r *resolver) wellKnown(typ types.Type) (*Schema, bool, error) {
+	schema, isWellKnown := r.lookupWellKnown(typ)
+	if !isWellKnown {
+		return nil, false, nil
+	}
+	clone, err := cloneSchema(schema)
+	return clone, true, err
+}
+
+// lookupWellKnown returns the schema of `typ` if it is a well-known type or
+// implements `encoding.TextMarshaler`. The returned schema is shared and must
+// not be modified.
+func (r *resolver) lookupWellKnown(typ types.Type) (*Schema, bool) {
+	// an alias can be mapped itself e.g. `encoding/json.RawMessage` which is
+	// an alias of `encoding/json/jsontext.Value` in newer go versions.
+	if alias, isAlias := typ.(*types.Alias); isAlias {
+		if schema, isMapped := r.types[types.TypeString(alias, nil)]; isMapped {
+			return schema, true
+		}
+	}
+	if schema, isMapped := r.types[wellknown.KeyOf(typ)]; isMapped {
+		return schema, true
+	}
+	named, isNamed := types.Unalias(typ).(*types.Named)
+	if !isNamed || r.enumOf(named) != nil {
+		return nil, false
+	}
+	if wellknown.IsTextMarshaler(named) {
+		return &Schema{Type: stringType}, true
+	}
+	return nil, false
+}
+
+// isWellKnown reports whether `typ` is a well-known type.
+func (r *resolver) isWellKnown(typ types.Type) bool {
+	_, isWellKnown := r.lookupWellKnown(typ)
+	return isWellKnown
+}
+
+func cloneSchema(schema *Schema) (*Schema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	clone := &Schema{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
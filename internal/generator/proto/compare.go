@@ -0,0 +1,143 @@
+package proto
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// _scopeRegExp matches the beginning of a message, enum or oneof.
+	_scopeRegExp = regexp.MustCompile(`^(message|enum|oneof)\s+([A-Za-z][A-Za-z0-9_]*)\s*\{`)
+	// _fieldRegExp matches a field of a message.
+	_fieldRegExp = regexp.MustCompile(`^(?:(?:optional|repeated)\s+)?(?:map\s*<[^>]*>|[A-Za-z.][A-Za-z0-9_.]*)\s+([A-Za-z][A-Za-z0-9_]*)\s*=\s*([0-9]+)`)
+)
+
+// compare compares the field numbers of `file` with the proto file of the
+// same name in `dir`. The number of a field cannot change and the number of a
+// removed field cannot be used by another field.
+func compare(dir string, file *protoFile) error {
+	if dir == "" {
+		return nil
+	}
+	path := filepath.Join(dir, file.name)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := parseFieldNumbers(data)
+	if err != nil {
+		return fmt.Errorf("proto file `%s` cannot be parsed: %w", path, err)
+	}
+	for _, d := range file.decls {
+		msg, isMessage := d.(*message)
+		if !isMessage {
+			continue
+		}
+		numbers, isDefined := existing[msg.name]
+		if !isDefined {
+			continue
+		}
+		if err := compareMessage(msg, numbers); err != nil {
+			return fmt.Errorf("message `%s` is incompatible with `%s`: %w", msg.name, path, err)
+		}
+	}
+	return nil
+}
+
+func compareMessage(msg *message, numbers map[string]int64) error {
+	names := make(map[string]bool)
+	for _, f := range msg.allFields() {
+		names[f.name] = true
+	}
+	for _, f := range msg.allFields() {
+		number, isDefined := numbers[f.name]
+		if isDefined && number != f.number {
+			return fmt.Errorf("number of field `%s` changed from %d to %d", f.name, number, f.number)
+		}
+		for name, number := range numbers {
+			if number != f.number || name == f.name || names[name] {
+				continue
+			}
+			return fmt.Errorf("field `%s` uses the number %d of the removed field `%s`. Keep the name of the field using +%s or reserve the number", f.name, f.number, name, identOf[Name](_fieldResource))
+		}
+	}
+	return nil
+}
+
+// parseFieldNumbers returns the numbers of the fields of all the messages in
+// `data` indexed by the name of the message and the name of the field. Nested
+// messages are indexed by their qualified name e.g. `Outer.Inner`.
+func parseFieldNumbers(data []byte) (map[string]map[string]int64, error) {
+	messages := make(map[string]map[string]int64)
+	// scopes are the names of the open messages. Enums and oneofs are
+	// represented by an empty name.
+	scopes := make([]string, 0)
+	kinds := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i != -1 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if match := _scopeRegExp.FindStringSubmatch(line); match != nil {
+			kinds = append(kinds, match[1])
+			if match[1] == "message" {
+				scopes = append(scopes, match[2])
+			}
+			if strings.HasSuffix(line, "}") {
+				kinds, scopes = closeScope(kinds, scopes)
+			}
+			continue
+		}
+		if line == "}" {
+			if len(kinds) == 0 {
+				return nil, errors.New("unexpected closing brace")
+			}
+			kinds, scopes = closeScope(kinds, scopes)
+			continue
+		}
+		if len(kinds) == 0 || kinds[len(kinds)-1] == "enum" {
+			continue
+		}
+		match := _fieldRegExp.FindStringSubmatch(line)
+		if match == nil || strings.HasPrefix(line, "reserved ") || strings.HasPrefix(line, "option ") {
+			continue
+		}
+		number, err := strconv.ParseInt(match[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.Join(scopes, ".")
+		if messages[name] == nil {
+			messages[name] = make(map[string]int64)
+		}
+		messages[name][match[1]] = number
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(kinds) != 0 {
+		return nil, errors.New("missing closing brace")
+	}
+	return messages, nil
+}
+
+func closeScope(kinds, scopes []string) ([]string, []string) {
+	kind := kinds[len(kinds)-1]
+	kinds = kinds[:len(kinds)-1]
+	if kind == "message" {
+		scopes = scopes[:len(scopes)-1]
+	}
+	return kinds, scopes
+}
@@ -0,0 +1,30 @@
+package proto
+
+import (
+	"embed"
+
+	"github.com/goccy/go-yaml"
+)
+
+// _configSchemas contains config.json, the JSON Schema of the dir option of
+// `config`.
+//
+//go:embed schemas/*.json
+var _configSchemas embed.FS
+
+func newConfig(cfg map[string]any) (*config, error) {
+	c := config{
+		Dir: "",
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &c)
+	return &c, err
+}
+
+// +openapi:schema:description="config options for the proto generator"
+type config struct {
+	Dir string `yaml:"dir"`
+}
@@ -0,0 +1,159 @@
+package proto
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// _header is written at the beginning of every proto file.
+const _header = "// Code generated by codemark. DO NOT EDIT.\n"
+
+// protoFile is the proto file generated for a go package.
+type protoFile struct {
+	name      string
+	pkg       string
+	goPackage string
+	imports   map[string]bool
+	// decls are the messages and enums in the order of the source.
+	decls []decl
+}
+
+// decl is a message or enum of a proto file.
+type decl interface {
+	render(b *strings.Builder)
+}
+
+type message struct {
+	name          string
+	doc           string
+	reservedNums  []reservedRange
+	reservedNames []string
+	// fields are the fields which are not part of a oneof group.
+	fields []*field
+	oneofs []*oneof
+}
+
+type field struct {
+	name       string
+	typ        string
+	number     int64
+	isOptional bool
+	isRepeated bool
+	doc        string
+	// goName is the name of the go field used in errors.
+	goName string
+}
+
+type oneof struct {
+	name   string
+	fields []*field
+}
+
+type enum struct {
+	name   string
+	doc    string
+	values []*enumValue
+}
+
+type enumValue struct {
+	name   string
+	number int64
+	doc    string
+}
+
+// allFields returns all the fields of the message including the fields of the
+// oneof groups.
+func (m *message) allFields() []*field {
+	fields := slices.Clone(m.fields)
+	for _, group := range m.oneofs {
+		fields = append(fields, group.fields...)
+	}
+	return fields
+}
+
+func (f *protoFile) render() []byte {
+	var b strings.Builder
+	b.WriteString(_header)
+	b.WriteString("\nsyntax = \"proto3\";\n")
+	fmt.Fprintf(&b, "\npackage %s;\n", f.pkg)
+	if len(f.imports) > 0 {
+		b.WriteString("\n")
+		for _, imp := range slices.Sorted(maps.Keys(f.imports)) {
+			fmt.Fprintf(&b, "import %q;\n", imp)
+		}
+	}
+	if f.goPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", f.goPackage)
+	}
+	for _, d := range f.decls {
+		b.WriteString("\n")
+		d.render(&b)
+	}
+	return []byte(b.String())
+}
+
+func (m *message) render(b *strings.Builder) {
+	writeDoc(b, m.doc, "")
+	fmt.Fprintf(b, "message %s {\n", m.name)
+	if len(m.reservedNums) > 0 {
+		ranges := make([]string, 0, len(m.reservedNums))
+		for _, r := range m.reservedNums {
+			ranges = append(ranges, r.String())
+		}
+		fmt.Fprintf(b, "  reserved %s;\n", strings.Join(ranges, ", "))
+	}
+	if len(m.reservedNames) > 0 {
+		names := make([]string, 0, len(m.reservedNames))
+		for _, name := range m.reservedNames {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+		fmt.Fprintf(b, "  reserved %s;\n", strings.Join(names, ", "))
+	}
+	if (len(m.reservedNums) > 0 || len(m.reservedNames) > 0) && len(m.allFields()) > 0 {
+		b.WriteString("\n")
+	}
+	for _, f := range m.fields {
+		f.render(b, "  ")
+	}
+	for _, group := range m.oneofs {
+		fmt.Fprintf(b, "  oneof %s {\n", group.name)
+		for _, f := range group.fields {
+			f.render(b, "    ")
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+}
+
+func (f *field) render(b *strings.Builder, indent string) {
+	writeDoc(b, f.doc, indent)
+	label := ""
+	switch {
+	case f.isRepeated:
+		label = "repeated "
+	case f.isOptional:
+		label = "optional "
+	}
+	fmt.Fprintf(b, "%s%s%s %s = %d;\n", indent, label, f.typ, f.name, f.number)
+}
+
+func (e *enum) render(b *strings.Builder) {
+	writeDoc(b, e.doc, "")
+	fmt.Fprintf(b, "enum %s {\n", e.name)
+	for _, value := range e.values {
+		writeDoc(b, value.doc, "  ")
+		fmt.Fprintf(b, "  %s = %d;\n", value.name, value.number)
+	}
+	b.WriteString("}\n")
+}
+
+func writeDoc(b *strings.Builder, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	for line := range strings.SplitSeq(doc, "\n") {
+		b.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+}
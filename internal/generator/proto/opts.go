@@ -0,0 +1,249 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/optionutil"
+	"github.com/naivary/codemark/registry"
+)
+
+const (
+	_fileResource    = "file"
+	_messageResource = "message"
+	_fieldResource   = "field"
+	_enumResource    = "enum"
+)
+
+const (
+	_unique    = true
+	_repetable = false
+)
+
+const (
+	// _maxFieldNumber is the largest field number of a message.
+	_maxFieldNumber = 1<<29 - 1
+	// _firstReservedNumber and _lastReservedNumber are the field numbers
+	// reserved for the implementation of Protocol Buffers.
+	_firstReservedNumber = 19000
+	_lastReservedNumber  = 19999
+)
+
+// _identRegExp matches the identifiers of Protocol Buffers.
+var _identRegExp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// _packageRegExp matches the package of a proto file e.g. example.api.v1.
+var _packageRegExp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)*$`)
+
+func newRegistry() (regv1.Registry, error) {
+	opts := []*optionv1.Option{
+		mustMakeOpt(_fileResource, Package(""), _unique, optionv1.TargetPkg),
+		mustMakeOpt(_fileResource, GoPackage(""), _unique, optionv1.TargetPkg),
+		mustMakeOpt(_messageResource, Name(""), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_messageResource, Reserved(""), _repetable, optionv1.TargetStruct),
+		mustMakeOpt(_messageResource, Exclude(false), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_fieldResource, Number(0), _unique, optionv1.TargetField),
+		mustMakeOpt(_fieldResource, Name(""), _unique, optionv1.TargetField),
+		mustMakeOpt(_fieldResource, Oneof(""), _unique, optionv1.TargetField),
+		mustMakeOpt(_fieldResource, Exclude(false), _unique, optionv1.TargetField),
+		mustMakeOpt(_enumResource, Name(""), _unique, optionv1.TargetNamed, optionv1.TargetConst),
+		mustMakeOpt(_enumResource, Number(0), _unique, optionv1.TargetConst),
+		mustMakeOpt(_enumResource, Exclude(false), _unique, optionv1.TargetConst),
+	}
+	reg := registry.InMemory()
+	for _, opt := range opts {
+		if err := reg.Define(opt); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+func mustMakeOpt(resource string, output any, isUnique bool, targets ...optionv1.Target) *optionv1.Option {
+	rtype := reflect.TypeOf(output)
+	doc := output.(openapi.Docer[docv1.Option]).Doc()
+	ident := fmt.Sprintf("%s:%s:%s", _domain, resource, openapi.CamelCase.Format(rtype.Name()))
+	opt := optionutil.MustMake(ident, rtype, &doc, isUnique, targets...)
+	return &opt
+}
+
+// optionOf returns the option of type `T` in `opts` which belongs to
+// `resource`.
+func optionOf[T any](opts infov1.Options, resource string) (T, bool) {
+	values, isDefined := opts.Get(identOf[T](resource))
+	if !isDefined {
+		var zero T
+		return zero, false
+	}
+	return values[0].(T), true
+}
+
+// optionsOf returns all the options of type `T` in `opts` which belong to
+// `resource`.
+func optionsOf[T any](opts infov1.Options, resource string) []T {
+	values, _ := opts.Get(identOf[T](resource))
+	res := make([]T, 0, len(values))
+	for _, value := range values {
+		res = append(res, value.(T))
+	}
+	return res
+}
+
+// hasOptions reports whether `opts` contains an option of `resource`.
+func hasOptions(opts infov1.Options, resource string) bool {
+	for ident := range opts {
+		if optionutil.DomainOf(ident) == _domain && optionutil.ResourceOf(ident) == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func identOf[T any](resource string) string {
+	return fmt.Sprintf("%s:%s:%s", _domain, resource, openapi.CamelCase.Format(reflect.TypeFor[T]().Name()))
+}
+
+// Package is the package of the proto file of a go package.
+type Package string
+
+func (p Package) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Package of the proto file generated for the go package e.g. example.api.v1. Defaults to the name of the go package",
+	}
+}
+
+func (p Package) validate() error {
+	if !_packageRegExp.MatchString(string(p)) {
+		return fmt.Errorf("package is not a valid proto package: %s", p)
+	}
+	return nil
+}
+
+// GoPackage is the `go_package` option of the proto file.
+type GoPackage string
+
+func (g GoPackage) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Value of the go_package option of the proto file e.g. example.com/api/v1;apiv1",
+	}
+}
+
+// Name renames a message, field, enum or value of an enum.
+type Name string
+
+func (n Name) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Name in the proto file. Messages and enums default to the name of the go type, fields to the snake_case name of the field and values of enums to the SCREAMING_SNAKE_CASE name of the constant prefixed by the enum",
+	}
+}
+
+func (n Name) validate() error {
+	if !_identRegExp.MatchString(string(n)) {
+		return fmt.Errorf("name is not a valid proto identifier: %s", n)
+	}
+	return nil
+}
+
+// Reserved is a reserved field number, range of numbers e.g. `5-7` or field
+// name of a message.
+type Reserved string
+
+func (r Reserved) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Reserves a field number e.g. 2, a range of field numbers e.g. 5-7 or a field name e.g. email which cannot be used anymore. Can be used more than once",
+	}
+}
+
+// reservedRange is an inclusive range of reserved field numbers.
+type reservedRange struct {
+	from, to int64
+}
+
+func (r reservedRange) contains(number int64) bool {
+	return number >= r.from && number <= r.to
+}
+
+func (r reservedRange) String() string {
+	if r.from == r.to {
+		return strconv.FormatInt(r.from, 10)
+	}
+	return fmt.Sprintf("%d to %d", r.from, r.to)
+}
+
+// parse returns the reserved range or the reserved name.
+func (r Reserved) parse() (*reservedRange, string, error) {
+	value := strings.TrimSpace(string(r))
+	if value == "" {
+		return nil, "", errors.New("reserved cannot be empty")
+	}
+	if _identRegExp.MatchString(value) {
+		return nil, value, nil
+	}
+	from, to, isRange := strings.Cut(value, "-")
+	if !isRange {
+		to = from
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(from), 10, 64)
+	if err != nil {
+		return nil, "", fmt.Errorf("reserved has to be a field number, range or name: %s", r)
+	}
+	end, err := strconv.ParseInt(strings.TrimSpace(to), 10, 64)
+	if err != nil {
+		return nil, "", fmt.Errorf("reserved has to be a field number, range or name: %s", r)
+	}
+	if start > end {
+		return nil, "", fmt.Errorf("start of reserved range is greater than the end: %s", r)
+	}
+	if err := Number(start).validate(); err != nil {
+		return nil, "", err
+	}
+	if err := Number(end).validate(); err != nil {
+		return nil, "", err
+	}
+	return &reservedRange{from: start, to: end}, "", nil
+}
+
+type Exclude bool
+
+func (e Exclude) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Excludes the message, field or value of an enum from the proto file",
+	}
+}
+
+// Number is the stable number of a field or value of an enum.
+type Number int64
+
+func (n Number) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Number of the field or value of an enum. Every field of a message needs a number which cannot change once the message is used. Values of enums default to the value of the constant",
+	}
+}
+
+func (n Number) validate() error {
+	if n < 1 || n > _maxFieldNumber {
+		return fmt.Errorf("field number has to be between 1 and %d: %d", _maxFieldNumber, n)
+	}
+	if n >= _firstReservedNumber && n <= _lastReservedNumber {
+		return fmt.Errorf("field numbers %d to %d are reserved for Protocol Buffers: %d", _firstReservedNumber, _lastReservedNumber, n)
+	}
+	return nil
+}
+
+// Oneof is the name of the oneof group of a field.
+type Oneof string
+
+func (o Oneof) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Name of the oneof group of the field. All fields with the same oneof name are grouped and at most one of them can be set",
+	}
+}
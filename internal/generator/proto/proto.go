@@ -0,0 +1,686 @@
+package proto
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"golang.org/x/tools/go/packages"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	genv1 "github.com/naivary/codemark/api/generator/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	configer "github.com/naivary/codemark/internal/config"
+	"github.com/naivary/codemark/internal/source"
+)
+
+const _domain = "proto"
+
+var (
+	_ genv1.Generator     = (*protoGenerator)(nil)
+	_ genv1.ConfigSchemer = (*protoGenerator)(nil)
+)
+
+func New() (genv1.Generator, error) {
+	reg, err := newRegistry()
+	if err != nil {
+		return nil, err
+	}
+	configSchemas, err := configer.Schemas(_configSchemas)
+	if err != nil {
+		return nil, err
+	}
+	return &protoGenerator{reg: reg, configSchemas: configSchemas}, nil
+}
+
+type protoGenerator struct {
+	reg regv1.Registry
+
+	// configSchemas are the JSON Schemas of the config.
+	configSchemas fs.FS
+}
+
+func (g *protoGenerator) Domain() docv1.Domain {
+	return docv1.Domain{
+		Name: _domain,
+		Desc: "Generate Protocol Buffers schemas",
+	}
+}
+
+func (g *protoGenerator) ConfigSchema() (fs.FS, string) {
+	return g.configSchemas, configer.SchemaRoot
+}
+
+func (g *protoGenerator) Resources() map[string]*docv1.Resource {
+	return map[string]*docv1.Resource{
+		_fileResource:    {Desc: "Define the package and options of the proto file generated for a go package"},
+		_messageResource: {Desc: "Generate a message from a struct"},
+		_fieldResource:   {Desc: "Define the stable number, name and oneof group of a field of a message"},
+		_enumResource:    {Desc: "Rename or number the values of enums generated from named types with constants"},
+	}
+}
+
+func (g *protoGenerator) Registry() regv1.Registry {
+	return g.reg
+}
+
+func (g *protoGenerator) ConfigDoc() map[string]docv1.Config {
+	return map[string]docv1.Config{
+		"dir": {
+			Default:     "",
+			Description: `Directory containing the previously generated proto files. If a proto file with the same name exists the generation fails if a field number changed or the number of a removed field is used by another field. If empty no files are compared.`,
+		},
+	}
+}
+
+func (g *protoGenerator) Generate(proj infov1.Project, config map[string]any) ([]*genv1.Artifact, error) {
+	cfg, err := newConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	b, err := newBuilder(proj)
+	if err != nil {
+		return nil, err
+	}
+	files, err := b.build()
+	if err != nil {
+		return nil, err
+	}
+	artifacts := make([]*genv1.Artifact, 0, len(files))
+	for _, file := range files {
+		if err := compare(cfg.Dir, file); err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, &genv1.Artifact{
+			Name: file.name,
+			Data: bytes.NewBuffer(file.render()),
+		})
+	}
+	return artifacts, nil
+}
+
+// typeRef is a message or enum which can be referenced by fields.
+type typeRef struct {
+	file *protoFile
+	name string
+	pos  token.Pos
+	// isUsed reports whether the enum is referenced by a message.
+	isUsed bool
+
+	// struct of a message
+	info *infov1.StructInfo
+	// named type of an enum
+	named *infov1.NamedInfo
+	pkg   *infov1.Information
+}
+
+// builder builds the proto files of a project.
+type builder struct {
+	files    map[*types.Package]*protoFile
+	messages map[*types.TypeName]*typeRef
+	enums    map[*types.TypeName]*typeRef
+}
+
+func newBuilder(proj infov1.Project) (*builder, error) {
+	b := &builder{
+		files:    make(map[*types.Package]*protoFile),
+		messages: make(map[*types.TypeName]*typeRef),
+		enums:    make(map[*types.TypeName]*typeRef),
+	}
+	names := make(map[string]string)
+	for _, pkg := range infov1.Packages(proj) {
+		file, err := newProtoFile(pkg, proj[pkg])
+		if err != nil {
+			return nil, err
+		}
+		if other, isDefined := names[file.name]; isDefined {
+			return nil, fmt.Errorf("packages `%s` and `%s` generate the same proto file: %s", other, pkg.PkgPath, file.name)
+		}
+		names[file.name] = pkg.PkgPath
+		b.files[pkg.Types] = file
+		if err := b.collect(pkg, proj[pkg], file); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// newProtoFile returns the proto file of `pkg` using the file options of all
+// the files of the package.
+func newProtoFile(pkg *packages.Package, info *infov1.Information) (*protoFile, error) {
+	file := &protoFile{
+		name:    pkg.Name + ".proto",
+		pkg:     pkg.Name,
+		imports: make(map[string]bool),
+	}
+	var protoPkg Package
+	var goPkg GoPackage
+	for _, finfo := range info.Files {
+		if p, isDefined := optionOf[Package](finfo.Options(), _fileResource); isDefined {
+			if protoPkg != "" && protoPkg != p {
+				return nil, fmt.Errorf("package `%s` has different proto packages: %s and %s", pkg.PkgPath, protoPkg, p)
+			}
+			protoPkg = p
+		}
+		if p, isDefined := optionOf[GoPackage](finfo.Options(), _fileResource); isDefined {
+			if goPkg != "" && goPkg != p {
+				return nil, fmt.Errorf("package `%s` has different go packages: %s and %s", pkg.PkgPath, goPkg, p)
+			}
+			goPkg = p
+		}
+	}
+	if protoPkg != "" {
+		if err := protoPkg.validate(); err != nil {
+			return nil, err
+		}
+		file.pkg = string(protoPkg)
+	}
+	file.goPackage = string(goPkg)
+	return file, nil
+}
+
+// collect collects the messages and enums of `pkg`. A struct is a message if
+// it has at least one proto marker on itself or its fields. Named types with
+// constants are enums.
+func (b *builder) collect(pkg *packages.Package, info *infov1.Information, file *protoFile) error {
+	for obj, sinfo := range infov1.ByPos(pkg.Fset, info.Structs) {
+		if !isMessage(sinfo) {
+			continue
+		}
+		if exclude, _ := optionOf[Exclude](sinfo.Options(), _messageResource); exclude {
+			continue
+		}
+		ref := &typeRef{file: file, name: obj.Name(), pos: obj.Pos(), info: sinfo}
+		if name, isDefined := optionOf[Name](sinfo.Options(), _messageResource); isDefined {
+			if err := name.validate(); err != nil {
+				return fmt.Errorf("message `%s` is invalid: %w", obj.Name(), err)
+			}
+			ref.name = string(name)
+		}
+		b.messages[obj.(*types.TypeName)] = ref
+	}
+	for obj, ninfo := range infov1.ByPos(pkg.Fset, info.Named) {
+		if !hasConsts(obj.(*types.TypeName), info) {
+			continue
+		}
+		ref := &typeRef{file: file, name: obj.Name(), pos: obj.Pos(), named: ninfo, pkg: info}
+		if name, isDefined := optionOf[Name](ninfo.Options(), _enumResource); isDefined {
+			if err := name.validate(); err != nil {
+				return fmt.Errorf("enum `%s` is invalid: %w", obj.Name(), err)
+			}
+			ref.name = string(name)
+			ref.isUsed = true
+		}
+		b.enums[obj.(*types.TypeName)] = ref
+	}
+	return nil
+}
+
+// build returns the proto files which contain at least one message or enum.
+func (b *builder) build() ([]*protoFile, error) {
+	type positioned struct {
+		ref  *typeRef
+		decl decl
+	}
+	decls := make([]positioned, 0, len(b.messages)+len(b.enums))
+	for _, obj := range sortedObjs(b.messages) {
+		ref := b.messages[obj]
+		msg, err := b.message(obj, ref)
+		if err != nil {
+			return nil, fmt.Errorf("message `%s` is invalid: %w", obj.Name(), err)
+		}
+		decls = append(decls, positioned{ref: ref, decl: msg})
+	}
+	for _, obj := range sortedObjs(b.enums) {
+		ref := b.enums[obj]
+		if !ref.isUsed {
+			continue
+		}
+		e, err := b.enum(obj, ref)
+		if err != nil {
+			return nil, fmt.Errorf("enum `%s` is invalid: %w", obj.Name(), err)
+		}
+		decls = append(decls, positioned{ref: ref, decl: e})
+	}
+	slices.SortStableFunc(decls, func(a, b positioned) int {
+		return cmp.Compare(a.ref.pos, b.ref.pos)
+	})
+	// names of the messages, enums and enum values indexed by their proto
+	// package
+	scopes := make(map[string]map[string]string)
+	for _, d := range decls {
+		scope, isDefined := scopes[d.ref.file.pkg]
+		if !isDefined {
+			scope = make(map[string]string)
+			scopes[d.ref.file.pkg] = scope
+		}
+		if err := isUniqueName(scope, d.ref, d.decl); err != nil {
+			return nil, err
+		}
+	}
+	files := make([]*protoFile, 0)
+	for _, d := range decls {
+		d.ref.file.decls = append(d.ref.file.decls, d.decl)
+		if !slices.Contains(files, d.ref.file) {
+			files = append(files, d.ref.file)
+		}
+	}
+	slices.SortFunc(files, func(a, b *protoFile) int {
+		return cmp.Compare(a.name, b.name)
+	})
+	return files, nil
+}
+
+// isUniqueName checks that the names of the declaration `d` of `ref` are not
+// declared in `scope` of its proto package and adds them. The values of an
+// enum are siblings of the enum in proto3 and share the scope with messages
+// and enums.
+func isUniqueName(scope map[string]string, ref *typeRef, d decl) error {
+	names := make(map[string]string)
+	switch d := d.(type) {
+	case *message:
+		names[d.name] = fmt.Sprintf("message `%s` of struct `%s`", d.name, ref.info.Spec.Name.Name)
+	case *enum:
+		goName := ref.named.Spec.Name.Name
+		names[d.name] = fmt.Sprintf("enum `%s` of type `%s`", d.name, goName)
+		for _, value := range d.values {
+			if _, isDefined := names[value.name]; isDefined {
+				return fmt.Errorf("enum `%s` of type `%s` has the value `%s` twice", d.name, goName, value.name)
+			}
+			names[value.name] = fmt.Sprintf("value `%s` of enum `%s`", value.name, d.name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		if other, isDefined := scope[name]; isDefined {
+			return fmt.Errorf("%s and %s have the same name in the proto package `%s`", other, names[name], ref.file.pkg)
+		}
+		scope[name] = names[name]
+	}
+	return nil
+}
+
+func (b *builder) message(obj *types.TypeName, ref *typeRef) (*message, error) {
+	msg := &message{
+		name: ref.name,
+		doc:  source.SpecDocOf(ref.info.Decl, ref.info.Spec.Doc),
+	}
+	for _, reserved := range optionsOf[Reserved](ref.info.Options(), _messageResource) {
+		r, name, err := reserved.parse()
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			msg.reservedNums = append(msg.reservedNums, *r)
+			continue
+		}
+		msg.reservedNames = append(msg.reservedNames, name)
+	}
+	if _, isGeneric := obj.Type().(*types.Named); isGeneric && obj.Type().(*types.Named).TypeParams().Len() > 0 {
+		return nil, errors.New("generic structs cannot be messages")
+	}
+	strct := obj.Type().Underlying().(*types.Struct)
+	groups := make(map[string]*oneof)
+	for field := range strct.Fields() {
+		finfo := ref.info.Fields[field]
+		if finfo == nil || !field.Exported() {
+			continue
+		}
+		opts := finfo.Options()
+		if exclude, _ := optionOf[Exclude](opts, _fieldResource); exclude {
+			continue
+		}
+		f, err := b.field(ref.file, field, opts)
+		if err != nil {
+			return nil, fmt.Errorf("field `%s` is invalid: %w", field.Name(), err)
+		}
+		if finfo.Field != nil {
+			f.doc = source.DocOf(finfo.Field.Doc)
+		}
+		group, isOneof := optionOf[Oneof](opts, _fieldResource)
+		if !isOneof {
+			msg.fields = append(msg.fields, f)
+			continue
+		}
+		if !_identRegExp.MatchString(string(group)) {
+			return nil, fmt.Errorf("oneof is not a valid proto identifier: %s", group)
+		}
+		if f.isRepeated || strings.HasPrefix(f.typ, "map<") {
+			return nil, fmt.Errorf("field `%s` of oneof `%s` cannot be repeated or a map", field.Name(), group)
+		}
+		f.isOptional = false
+		if groups[string(group)] == nil {
+			groups[string(group)] = &oneof{name: string(group)}
+			msg.oneofs = append(msg.oneofs, groups[string(group)])
+		}
+		groups[string(group)].fields = append(groups[string(group)].fields, f)
+	}
+	return msg, isValidMessage(msg)
+}
+
+// isValidMessage checks that the numbers and names of the fields are unique
+// and not reserved.
+func isValidMessage(msg *message) error {
+	numbers := make(map[int64]*field)
+	names := make(map[string]*field)
+	for _, f := range msg.allFields() {
+		if other, isUsed := numbers[f.number]; isUsed {
+			return fmt.Errorf("fields `%s` and `%s` have the same number: %d", other.goName, f.goName, f.number)
+		}
+		numbers[f.number] = f
+		if other, isUsed := names[f.name]; isUsed {
+			return fmt.Errorf("fields `%s` and `%s` have the same name: %s", other.goName, f.goName, f.name)
+		}
+		names[f.name] = f
+		for _, r := range msg.reservedNums {
+			if r.contains(f.number) {
+				return fmt.Errorf("number of field `%s` is reserved: %d", f.goName, f.number)
+			}
+		}
+		if slices.Contains(msg.reservedNames, f.name) {
+			return fmt.Errorf("name of field `%s` is reserved: %s", f.goName, f.name)
+		}
+	}
+	for _, group := range msg.oneofs {
+		if _, isField := names[group.name]; isField {
+			return fmt.Errorf("oneof `%s` has the same name as a field", group.name)
+		}
+	}
+	return nil
+}
+
+func (b *builder) field(file *protoFile, v *types.Var, opts infov1.Options) (*field, error) {
+	number, isDefined := optionOf[Number](opts, _fieldResource)
+	if !isDefined {
+		return nil, fmt.Errorf("field has no number. Use +%s to define a stable number", identOf[Number](_fieldResource))
+	}
+	if err := number.validate(); err != nil {
+		return nil, err
+	}
+	f := &field{
+		name:   strcase.ToSnake(v.Name()),
+		number: int64(number),
+		goName: v.Name(),
+	}
+	if name, isDefined := optionOf[Name](opts, _fieldResource); isDefined {
+		if err := name.validate(); err != nil {
+			return nil, err
+		}
+		f.name = string(name)
+	}
+	typ := v.Type()
+	if ptr, isPointer := typ.Underlying().(*types.Pointer); isPointer {
+		f.isOptional = true
+		typ = ptr.Elem()
+	}
+	if elem, isList := listElemOf(typ); isList {
+		f.isRepeated = true
+		typ = elem
+		if ptr, isPointer := typ.Underlying().(*types.Pointer); isPointer {
+			typ = ptr.Elem()
+		}
+		if _, isList := listElemOf(typ); isList {
+			return nil, errors.New("nested lists cannot be represented in proto")
+		}
+	}
+	name, kind, err := b.typeOf(file, typ)
+	if err != nil {
+		return nil, err
+	}
+	if kind == mapKind && f.isRepeated {
+		return nil, errors.New("lists of maps cannot be represented in proto")
+	}
+	if kind == messageKind || kind == mapKind || f.isRepeated {
+		// messages have presence without the optional label
+		f.isOptional = false
+	}
+	f.typ = name
+	return f, nil
+}
+
+// typeKind is the kind of a type of a field.
+type typeKind int
+
+const (
+	scalarKind typeKind = iota + 1
+	messageKind
+	enumKind
+	mapKind
+)
+
+// typeOf returns the proto type of `typ`. Types of other proto files are
+// qualified by their package and imported.
+func (b *builder) typeOf(file *protoFile, typ types.Type) (string, typeKind, error) {
+	typ = types.Unalias(typ)
+	switch types.TypeString(typ, nil) {
+	case "time.Time":
+		file.imports["google/protobuf/timestamp.proto"] = true
+		return "google.protobuf.Timestamp", messageKind, nil
+	case "time.Duration":
+		file.imports["google/protobuf/duration.proto"] = true
+		return "google.protobuf.Duration", messageKind, nil
+	}
+	if isBytes(typ) {
+		return "bytes", scalarKind, nil
+	}
+	switch t := typ.(type) {
+	case *types.Named:
+		if t.TypeArgs().Len() > 0 {
+			return "", 0, fmt.Errorf("generic type cannot be represented in proto: %s", t)
+		}
+		if ref, isMessage := b.messages[t.Obj()]; isMessage {
+			return b.qualify(file, ref), messageKind, nil
+		}
+		if ref, isEnum := b.enums[t.Obj()]; isEnum {
+			ref.isUsed = true
+			return b.qualify(file, ref), enumKind, nil
+		}
+		if _, isStruct := t.Underlying().(*types.Struct); isStruct {
+			return "", 0, fmt.Errorf("struct `%s` is not a message. Add a proto marker to the struct or its fields", t.Obj().Name())
+		}
+		return b.typeOf(file, t.Underlying())
+	case *types.Basic:
+		name, err := scalarOf(t)
+		return name, scalarKind, err
+	case *types.Map:
+		key, ok := t.Key().Underlying().(*types.Basic)
+		if !ok || key.Info()&(types.IsInteger|types.IsString|types.IsBoolean) == 0 {
+			return "", 0, fmt.Errorf("key of map has to be an integer, string or bool: %s", t.Key())
+		}
+		keyName, err := scalarOf(key)
+		if err != nil {
+			return "", 0, err
+		}
+		elem := t.Elem()
+		if ptr, isPointer := elem.Underlying().(*types.Pointer); isPointer {
+			elem = ptr.Elem()
+		}
+		if _, isList := listElemOf(elem); isList {
+			return "", 0, errors.New("maps of lists cannot be represented in proto")
+		}
+		value, kind, err := b.typeOf(file, elem)
+		if err != nil {
+			return "", 0, err
+		}
+		if kind == mapKind {
+			return "", 0, errors.New("maps of maps cannot be represented in proto")
+		}
+		return fmt.Sprintf("map<%s, %s>", keyName, value), mapKind, nil
+	}
+	return "", 0, fmt.Errorf("type cannot be represented in proto: %s", typ)
+}
+
+// qualify returns the name of `ref` used in `file`.
+func (b *builder) qualify(file *protoFile, ref *typeRef) string {
+	if ref.file == file {
+		return ref.name
+	}
+	file.imports[ref.file.name] = true
+	return ref.file.pkg + "." + ref.name
+}
+
+func (b *builder) enum(obj *types.TypeName, ref *typeRef) (*enum, error) {
+	e := &enum{
+		name: ref.name,
+		doc:  source.SpecDocOf(ref.named.Decl, ref.named.Spec.Doc),
+	}
+	prefix := strcase.ToScreamingSnake(ref.name) + "_"
+	numbers := make(map[int64]string)
+	for c, cinfo := range infov1.ByPos(ref.pkg.Fset, ref.pkg.Consts) {
+		if !c.Exported() || !types.Identical(c.Type(), obj.Type()) {
+			continue
+		}
+		opts := cinfo.Options()
+		if exclude, _ := optionOf[Exclude](opts, _enumResource); exclude {
+			continue
+		}
+		value := &enumValue{
+			name: strcase.ToScreamingSnake(c.Name()),
+			doc:  source.DocOf(cinfo.Spec.Doc),
+		}
+		if !strings.HasPrefix(value.name, prefix) {
+			value.name = prefix + value.name
+		}
+		if name, isDefined := optionOf[Name](opts, _enumResource); isDefined {
+			if err := name.validate(); err != nil {
+				return nil, err
+			}
+			value.name = string(name)
+		}
+		number, err := enumNumberOf(c.(*types.Const), opts)
+		if err != nil {
+			return nil, err
+		}
+		if other, isUsed := numbers[number]; isUsed {
+			return nil, fmt.Errorf("constants `%s` and `%s` have the same number: %d", other, c.Name(), number)
+		}
+		numbers[number] = c.Name()
+		value.number = number
+		e.values = append(e.values, value)
+	}
+	// the first value of an enum has to be zero in proto3
+	idx := slices.IndexFunc(e.values, func(value *enumValue) bool { return value.number == 0 })
+	if idx == -1 {
+		e.values = slices.Insert(e.values, 0, &enumValue{name: prefix + "UNSPECIFIED"})
+	} else {
+		zero := e.values[idx]
+		e.values = slices.Insert(slices.Delete(e.values, idx, idx+1), 0, zero)
+	}
+	return e, nil
+}
+
+// enumNumberOf returns the number of the constant `c` of an enum.
+func enumNumberOf(c *types.Const, opts infov1.Options) (int64, error) {
+	number, isDefined := optionOf[Number](opts, _enumResource)
+	if !isDefined {
+		if c.Val().Kind() != constant.Int {
+			return 0, fmt.Errorf("constant `%s` needs a number. Use +%s", c.Name(), identOf[Number](_enumResource))
+		}
+		v, isExact := constant.Int64Val(c.Val())
+		if !isExact {
+			return 0, fmt.Errorf("value of constant `%s` is too large for an enum", c.Name())
+		}
+		number = Number(v)
+	}
+	if number < math.MinInt32 || number > math.MaxInt32 {
+		return 0, fmt.Errorf("number of constant `%s` has to be a 32-bit integer: %d", c.Name(), number)
+	}
+	return int64(number), nil
+}
+
+// isMessage reports whether the struct has a marker of the message or field
+// resource.
+func isMessage(info *infov1.StructInfo) bool {
+	if hasOptions(info.Options(), _messageResource) {
+		return true
+	}
+	for _, finfo := range info.Fields {
+		if hasOptions(finfo.Options(), _fieldResource) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasConsts reports whether exported constants of the named type `obj` are
+// declared in `info`.
+func hasConsts(obj *types.TypeName, info *infov1.Information) bool {
+	basic, isBasic := obj.Type().Underlying().(*types.Basic)
+	if !isBasic || basic.Info()&(types.IsString|types.IsInteger) == 0 {
+		return false
+	}
+	for c := range info.Consts {
+		if c.Exported() && types.Identical(c.Type(), obj.Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+func scalarOf(basic *types.Basic) (string, error) {
+	switch basic.Kind() {
+	case types.Bool:
+		return "bool", nil
+	case types.String:
+		return "string", nil
+	case types.Int, types.Int64:
+		return "int64", nil
+	case types.Int8, types.Int16, types.Int32:
+		return "int32", nil
+	case types.Uint, types.Uint64, types.Uintptr:
+		return "uint64", nil
+	case types.Uint8, types.Uint16, types.Uint32:
+		return "uint32", nil
+	case types.Float32:
+		return "float", nil
+	case types.Float64:
+		return "double", nil
+	}
+	return "", fmt.Errorf("type cannot be represented in proto: %s", basic)
+}
+
+// listElemOf returns the element of slices and arrays except []byte which is
+// a scalar.
+func listElemOf(typ types.Type) (types.Type, bool) {
+	if isBytes(typ) {
+		return nil, false
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Slice:
+		return t.Elem(), true
+	case *types.Array:
+		return t.Elem(), true
+	}
+	return nil, false
+}
+
+func isBytes(typ types.Type) bool {
+	slice, isSlice := typ.Underlying().(*types.Slice)
+	if !isSlice {
+		return false
+	}
+	basic, isBasic := slice.Elem().Underlying().(*types.Basic)
+	return isBasic && basic.Kind() == types.Uint8
+}
+
+func sortedObjs(refs map[*types.TypeName]*typeRef) []*types.TypeName {
+	return slices.SortedFunc(func(yield func(*types.TypeName) bool) {
+		for obj := range refs {
+			if !yield(obj) {
+				return
+			}
+		}
+	}, func(a, b *types.TypeName) int {
+		return cmp.Compare(a.Pos(), b.Pos())
+	})
+}
@@ -0,0 +1,460 @@
+package proto
+
+import (
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+func TestGenerator_Files(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		isValid bool
+		want    map[string]string
+	}{
+		{
+			name:    "messages",
+			isValid: true,
+			files: map[string]string{
+				"api/user.go": `// +proto:file:package="example.api.v1"
+// +proto:file:goPackage="loadertest/api;api"
+package api
+
+import "time"
+
+// User is a registered user.
+//
+// +proto:message:reserved="2"
+// +proto:message:reserved="5-7"
+// +proto:message:reserved="password"
+type User struct {
+	// ID of the user.
+	// +proto:field:number=1
+	ID string
+	// +proto:field:number=3
+	Nickname *string
+	// +proto:field:number=4
+	Roles []Role
+	// +proto:field:number=8
+	CreatedAt time.Time
+	// +proto:field:number=9
+	Labels map[string]string
+	// +proto:field:number=10
+	// +proto:field:oneof="contact"
+	Email string
+	// +proto:field:number=11
+	// +proto:field:oneof="contact"
+	Phone *string
+	// +proto:field:number=12
+	Address *Address
+	// +proto:field:exclude
+	Internal string
+}
+
+// +proto:message:name="PostalAddress"
+type Address struct {
+	// +proto:field:number=1
+	Street string
+	// +proto:field:number=2
+	Avatar []byte
+}
+
+type Role int
+
+const (
+	RoleAdmin Role = iota + 1
+	// +proto:enum:name="ROLE_MEMBER_DEFAULT"
+	RoleMember
+)
+
+type Status string
+
+const (
+	// +proto:enum:number=0
+	StatusActive Status = "active"
+	// +proto:enum:number=1
+	StatusBlocked Status = "blocked"
+)
+
+// +proto:message:exclude
+type Hidden struct {
+	// +proto:field:number=1
+	Name string
+}
+`,
+			},
+			want: map[string]string{
+				"api.proto": `// Code generated by codemark. DO NOT EDIT.
+
+syntax = "proto3";
+
+package example.api.v1;
+
+import "google/protobuf/timestamp.proto";
+
+option go_package = "loadertest/api;api";
+
+// User is a registered user.
+message User {
+  reserved 2, 5 to 7;
+  reserved "password";
+
+  // ID of the user.
+  string id = 1;
+  optional string nickname = 3;
+  repeated Role roles = 4;
+  google.protobuf.Timestamp created_at = 8;
+  map<string, string> labels = 9;
+  PostalAddress address = 12;
+  oneof contact {
+    string email = 10;
+    string phone = 11;
+  }
+}
+
+message PostalAddress {
+  string street = 1;
+  bytes avatar = 2;
+}
+
+enum Role {
+  ROLE_UNSPECIFIED = 0;
+  ROLE_ADMIN = 1;
+  ROLE_MEMBER_DEFAULT = 2;
+}
+`,
+			},
+		},
+		{
+			name:    "imports",
+			isValid: true,
+			files: map[string]string{
+				"api/user.go": `package api
+
+import "loadertest/common"
+
+type User struct {
+	// +proto:field:number=1
+	Status common.Status
+}
+`,
+				"common/status.go": `package common
+
+type Status string
+
+const (
+	// +proto:enum:number=1
+	StatusActive Status = "active"
+)
+`,
+			},
+			want: map[string]string{
+				"api.proto": `// Code generated by codemark. DO NOT EDIT.
+
+syntax = "proto3";
+
+package api;
+
+import "common.proto";
+
+message User {
+  common.Status status = 1;
+}
+`,
+				"common.proto": `// Code generated by codemark. DO NOT EDIT.
+
+syntax = "proto3";
+
+package common;
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+}
+`,
+			},
+		},
+		{
+			name:    "duplicate number",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +proto:field:number=1
+	ID string
+	// +proto:field:number=1
+	Name string
+}
+`,
+			},
+		},
+		{
+			name:    "reserved number",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+// +proto:message:reserved="1-3"
+type User struct {
+	// +proto:field:number=2
+	ID string
+}
+`,
+			},
+		},
+		{
+			name:    "missing number",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +proto:field:number=1
+	ID string
+	Name string
+}
+`,
+			},
+		},
+		{
+			name:    "missing enum number",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +proto:field:number=1
+	Status Status
+}
+
+type Status string
+
+const StatusActive Status = "active"
+`,
+			},
+		},
+		{
+			name:    "renamed messages with the same name",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+// +proto:message:name="Person"
+type User struct {
+	// +proto:field:number=1
+	ID string
+}
+
+type Person struct {
+	// +proto:field:number=1
+	Name string
+}
+`,
+			},
+		},
+		{
+			name:    "message and enum with the same name",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+// +proto:message:name="Status"
+type User struct {
+	// +proto:field:number=1
+	Status Status
+}
+
+type Status int
+
+const StatusActive Status = 1
+`,
+			},
+		},
+		{
+			name:    "enum values with the same name",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +proto:field:number=1
+	Role Role
+	// +proto:field:number=2
+	Kind Kind
+}
+
+type Role int
+
+// +proto:enum:name="DEFAULT"
+const RoleMember Role = 1
+
+type Kind int
+
+// +proto:enum:name="DEFAULT"
+const KindHuman Kind = 1
+`,
+			},
+		},
+		{
+			name:    "struct without message",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +proto:field:number=1
+	Address Address
+}
+
+type Address struct {
+	Street string
+}
+`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, tc.files, nil)
+			if !tc.isValid && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !tc.isValid {
+				return
+			}
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			got := make(map[string]string, len(artifacts))
+			for _, artifact := range artifacts {
+				data, err := io.ReadAll(artifact.Data)
+				if err != nil {
+					t.Fatalf("err occurred: %v", err)
+				}
+				got[artifact.Name] = string(data)
+			}
+			if !slices.Equal(slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(tc.want))) {
+				t.Fatalf("files are not equal. got: %v; want: %v", slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(tc.want)))
+			}
+			for name, want := range tc.want {
+				if got[name] != want {
+					t.Errorf("file %s is not equal\ngot:\n%s\nwant:\n%s", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Compare(t *testing.T) {
+	const existing = `syntax = "proto3";
+
+package api;
+
+message User {
+  string id = 1;
+  // email of the user
+  optional string email = 2;
+  map<string, string> labels = 3;
+  oneof contact {
+    string phone = 4;
+  }
+
+  message Nested {
+    string name = 1;
+  }
+}
+
+enum Role {
+  ROLE_UNSPECIFIED = 0;
+}
+`
+	tests := []struct {
+		name    string
+		user    string
+		isValid bool
+	}{
+		{
+			name:    "unchanged",
+			isValid: true,
+			user: `package api
+
+type User struct {
+	// +proto:field:number=1
+	ID string
+	// +proto:field:number=2
+	Email *string
+	// +proto:field:number=3
+	Labels map[string]string
+	// +proto:field:number=4
+	// +proto:field:oneof="contact"
+	Phone string
+	// +proto:field:number=5
+	Name string
+}
+`,
+		},
+		{
+			name:    "changed number",
+			isValid: false,
+			user: `package api
+
+type User struct {
+	// +proto:field:number=1
+	ID string
+	// +proto:field:number=5
+	Email *string
+}
+`,
+		},
+		{
+			name:    "reused number",
+			isValid: false,
+			user: `package api
+
+type User struct {
+	// +proto:field:number=1
+	ID string
+	// +proto:field:number=2
+	Nickname string
+}
+`,
+		},
+		{
+			name:    "renamed with name marker",
+			isValid: true,
+			user: `package api
+
+type User struct {
+	// +proto:field:number=1
+	ID string
+	// +proto:field:number=2
+	// +proto:field:name="email"
+	Mail *string
+}
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "api.proto"), []byte(existing), 0o600); err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			files := map[string]string{"api/user.go": tc.user}
+			_, err := loadertest.Generate(t, New, files, map[string]any{"dir": dir})
+			if tc.isValid && err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			if !tc.isValid && err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package typescript
+
+import (
+	"embed"
+
+	"github.com/goccy/go-yaml"
+)
+
+// _configSchemas contains config.json validating the declaration option of
+// `config`.
+//
+//go:embed schemas/*.json
+var _configSchemas embed.FS
+
+func newConfig(cfg map[string]any) (*config, error) {
+	c := config{
+		Declaration: false,
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &c)
+	return &c, err
+}
+
+// +openapi:schema:description="config options for the typescript generator"
+type config struct {
+	Declaration bool `yaml:"declaration"`
+}
+
+// ext returns the file extension of the modules.
+func (c *config) ext() string {
+	if c.Declaration {
+		return ".d.ts"
+	}
+	return ".ts"
+}
@@ -0,0 +1,93 @@
+package typescript
+
+import (
+	"fmt"
+	"go/constant"
+	"go/types"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+)
+
+// declKind is the kind of a TypeScript declaration.
+type declKind int
+
+const (
+	// interfaceDecl is an interface generated from a struct.
+	interfaceDecl declKind = iota + 1
+	// enumDecl is a union of literals generated from a named type with
+	// constants.
+	enumDecl
+	// aliasDecl is a type alias generated from a named type or an alias.
+	aliasDecl
+)
+
+// decl is a declaration of a TypeScript module.
+type decl struct {
+	kind   declKind
+	obj    *types.TypeName
+	name   string
+	module string
+	doc    string
+
+	// info is the info of the struct for interface declarations.
+	info *infov1.StructInfo
+	// values are the literals of enum declarations.
+	values []string
+}
+
+// newDecl returns the declaration of `obj` or nil if it is excluded.
+func newDecl(pkg string, obj *types.TypeName, info infov1.Info, doc string) (*decl, error) {
+	if !obj.Exported() {
+		return nil, nil
+	}
+	if exclude, _ := optionOf[Exclude](info.Options(), _typeResource); exclude {
+		return nil, nil
+	}
+	d := &decl{obj: obj, name: obj.Name(), module: pkg, doc: doc}
+	if name, isDefined := optionOf[Name](info.Options(), _typeResource); isDefined {
+		if err := name.validate(); err != nil {
+			return nil, fmt.Errorf("type `%s` is invalid: %w", obj.Name(), err)
+		}
+		d.name = string(name)
+	}
+	if module, isDefined := optionOf[Module](info.Options(), _typeResource); isDefined {
+		if err := module.validate(); err != nil {
+			return nil, fmt.Errorf("type `%s` is invalid: %w", obj.Name(), err)
+		}
+		d.module = string(module)
+	}
+	return d, nil
+}
+
+// enumValuesOf returns the literals of the exported constants of the named
+// type `obj` declared in `pkg`. Constants with the exclude marker are
+// skipped.
+func enumValuesOf(obj *types.TypeName, pkg *infov1.Information) []string {
+	basic, isBasic := obj.Type().Underlying().(*types.Basic)
+	if !isBasic || basic.Info()&(types.IsString|types.IsInteger|types.IsFloat) == 0 {
+		return nil
+	}
+	values := make([]string, 0)
+	for c, cinfo := range infov1.ByPos(pkg.Fset, pkg.Consts) {
+		if !c.Exported() || !types.Identical(c.Type(), obj.Type()) {
+			continue
+		}
+		if exclude, _ := optionOf[Exclude](cinfo.Options(), _typeResource); exclude {
+			continue
+		}
+		values = append(values, literalOf(c.(*types.Const).Val()))
+	}
+	return values
+}
+
+// literalOf returns the TypeScript literal of the constant value `val`.
+func literalOf(val constant.Value) string {
+	if val.Kind() == constant.String {
+		return quote(constant.StringVal(val))
+	}
+	if val.Kind() == constant.Float {
+		f, _ := constant.Float64Val(val)
+		return fmt.Sprint(f)
+	}
+	return val.ExactString()
+}
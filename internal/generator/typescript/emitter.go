@@ -0,0 +1,471 @@
+package typescript
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"maps"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	"github.com/naivary/codemark/internal/source"
+	"github.com/naivary/codemark/internal/wellknown"
+)
+
+// _header is written at the beginning of every module.
+const _header = "// Code generated by codemark. DO NOT EDIT.\n"
+
+// emitter emits the declarations of a single module.
+type emitter struct {
+	module string
+	decls  map[*types.TypeName]*decl
+	// structs are the infos of all the structs of the project which are
+	// needed for embedded structs.
+	structs map[*types.TypeName]*infov1.StructInfo
+	// imports are the names imported by the module indexed by the module
+	// they are imported from.
+	imports map[string]map[string]bool
+	// expanding are the types which are currently expanded to detect
+	// recursive types without declaration.
+	expanding map[types.Type]bool
+}
+
+func newEmitter(module string, decls map[*types.TypeName]*decl, structs map[*types.TypeName]*infov1.StructInfo) *emitter {
+	return &emitter{
+		module:    module,
+		decls:     decls,
+		structs:   structs,
+		imports:   make(map[string]map[string]bool),
+		expanding: make(map[types.Type]bool),
+	}
+}
+
+// emit returns the source of the module containing `decls`.
+func (e *emitter) emit(decls []*decl) ([]byte, error) {
+	var body bytes.Buffer
+	for i, d := range decls {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		body.WriteString(jsDoc(d.doc, ""))
+		src, err := e.declOf(d)
+		if err != nil {
+			return nil, fmt.Errorf("type `%s` is invalid: %w", d.obj.Name(), err)
+		}
+		body.WriteString(src)
+	}
+	var out bytes.Buffer
+	out.WriteString(_header)
+	out.WriteString("\n")
+	for _, module := range slices.Sorted(maps.Keys(e.imports)) {
+		names := slices.Sorted(maps.Keys(e.imports[module]))
+		fmt.Fprintf(&out, "import type { %s } from %s;\n", strings.Join(names, ", "), quote(e.importPath(module)))
+	}
+	if len(e.imports) > 0 {
+		out.WriteString("\n")
+	}
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+func (e *emitter) declOf(d *decl) (string, error) {
+	switch d.kind {
+	case enumDecl:
+		return fmt.Sprintf("export type %s = %s;\n", d.name, strings.Join(d.values, " | ")), nil
+	case aliasDecl:
+		var rhs types.Type = d.obj.Type().Underlying()
+		if alias, isAlias := d.obj.Type().(*types.Alias); isAlias {
+			rhs = alias.Rhs()
+		}
+		typ, err := e.typeOf(rhs)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("export type %s = %s;\n", d.name, typ), nil
+	}
+	named := d.obj.Type().(*types.Named)
+	params := make([]string, 0, named.TypeParams().Len())
+	for param := range named.TypeParams().TypeParams() {
+		params = append(params, param.Obj().Name())
+	}
+	props, extends, err := e.propertiesOf(named.Underlying().(*types.Struct), d.info)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("export interface " + d.name)
+	if len(params) > 0 {
+		b.WriteString("<" + strings.Join(params, ", ") + ">")
+	}
+	if len(extends) > 0 {
+		b.WriteString(" extends " + strings.Join(extends, ", "))
+	}
+	if len(props) == 0 {
+		b.WriteString(" {}\n")
+		return b.String(), nil
+	}
+	b.WriteString(" {\n")
+	for _, prop := range props {
+		b.WriteString(jsDoc(prop.doc, "  "))
+		b.WriteString("  " + prop.String() + ";\n")
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// property is a property of an interface or object type.
+type property struct {
+	name       string
+	typ        string
+	isOptional bool
+	doc        string
+}
+
+func (p property) String() string {
+	name := p.name
+	if !_identRegExp.MatchString(name) {
+		name = quote(name)
+	}
+	if p.isOptional {
+		name += "?"
+	}
+	return name + ": " + p.typ
+}
+
+// propertiesOf returns the properties of `strct` like they are encoded by
+// `encoding/json`. Embedded structs with a declaration are returned as
+// interfaces to extend. The fields of all other embedded structs are
+// promoted. `info` might be nil if the struct is not part of the project.
+func (e *emitter) propertiesOf(strct *types.Struct, info *infov1.StructInfo) ([]property, []string, error) {
+	props := make([]property, 0, strct.NumFields())
+	extends := make([]string, 0)
+	for i := range strct.NumFields() {
+		field := strct.Field(i)
+		var finfo *infov1.FieldInfo
+		if info != nil {
+			finfo = info.Fields[field]
+		}
+		if exclude, _ := optionOf[Exclude](fieldOptionsOf(finfo), _fieldResource); exclude {
+			continue
+		}
+		tagName, tagOpts := jsonTagOf(strct.Tag(i))
+		if tagName == "-" && len(tagOpts) == 0 {
+			continue
+		}
+		if field.Embedded() && tagName == "" {
+			extended, promoted, err := e.embeddedOf(field.Type())
+			if err != nil {
+				return nil, nil, err
+			}
+			if extended != "" {
+				extends = append(extends, extended)
+			}
+			props = append(props, promoted...)
+			continue
+		}
+		if !field.Exported() {
+			continue
+		}
+		prop, err := e.propertyOf(field, finfo, tagName, tagOpts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("field `%s` is invalid: %w", field.Name(), err)
+		}
+		props = append(props, prop)
+	}
+	return props, extends, nil
+}
+
+func (e *emitter) propertyOf(field *types.Var, finfo *infov1.FieldInfo, tagName string, tagOpts []string) (property, error) {
+	typ, err := e.typeOf(field.Type())
+	if err != nil {
+		return property{}, err
+	}
+	if slices.Contains(tagOpts, "string") && isStringable(field.Type()) {
+		typ = "string"
+	}
+	_, isPointer := field.Type().Underlying().(*types.Pointer)
+	prop := property{
+		name:       field.Name(),
+		typ:        typ,
+		isOptional: isPointer || slices.Contains(tagOpts, "omitempty") || slices.Contains(tagOpts, "omitzero"),
+	}
+	if tagName != "" {
+		prop.name = tagName
+	}
+	if name, isDefined := optionOf[Name](fieldOptionsOf(finfo), _fieldResource); isDefined {
+		prop.name = string(name)
+	}
+	if optional, isDefined := optionOf[Optional](fieldOptionsOf(finfo), _fieldResource); isDefined {
+		prop.isOptional = bool(optional)
+	}
+	if finfo != nil && finfo.Field != nil {
+		prop.doc = source.DocOf(finfo.Field.Doc)
+	}
+	return prop, nil
+}
+
+// fieldOptionsOf returns the options of `finfo` which is nil for fields of
+// structs which are not part of the project.
+func fieldOptionsOf(finfo *infov1.FieldInfo) infov1.Options {
+	if finfo == nil {
+		return nil
+	}
+	return finfo.Options()
+}
+
+// embeddedOf returns the interface to extend for the embedded field of type
+// `typ` or the promoted properties if the embedded struct has no
+// declaration.
+func (e *emitter) embeddedOf(typ types.Type) (string, []property, error) {
+	if ptr, isPointer := typ.(*types.Pointer); isPointer {
+		typ = ptr.Elem()
+	}
+	named, isNamed := types.Unalias(typ).(*types.Named)
+	if !isNamed {
+		return "", nil, nil
+	}
+	strct, isStruct := named.Underlying().(*types.Struct)
+	if !isStruct {
+		// embedded non-structs are encoded like a field named after the type
+		// which is not supported.
+		return "", nil, nil
+	}
+	if d, isDeclared := e.decls[named.Origin().Obj()]; isDeclared && d.kind == interfaceDecl {
+		name, err := e.referenceOf(d, named.TypeArgs())
+		return name, nil, err
+	}
+	props, extends, err := e.propertiesOf(strct, e.structs[named.Origin().Obj()])
+	if len(extends) > 0 {
+		return "", nil, fmt.Errorf("struct `%s` has no declaration but embeds a struct with a declaration", named.Obj().Name())
+	}
+	return "", props, err
+}
+
+// typeOf returns the TypeScript type of `typ`.
+func (e *emitter) typeOf(typ types.Type) (string, error) {
+	if name, isWellKnown := e.wellKnown(typ); isWellKnown {
+		return name, nil
+	}
+	switch t := typ.(type) {
+	case *types.Alias:
+		if d, isDeclared := e.decls[t.Obj()]; isDeclared {
+			return e.referenceOf(d, nil)
+		}
+		return e.typeOf(t.Rhs())
+	case *types.TypeParam:
+		return t.Obj().Name(), nil
+	case *types.Named:
+		if d, isDeclared := e.decls[t.Origin().Obj()]; isDeclared {
+			return e.referenceOf(d, t.TypeArgs())
+		}
+		if e.expanding[t] {
+			return "unknown", nil
+		}
+		e.expanding[t] = true
+		defer delete(e.expanding, t)
+		return e.typeOf(t.Underlying())
+	case *types.Basic:
+		return basicOf(t)
+	case *types.Pointer:
+		return e.typeOf(t.Elem())
+	case *types.Slice:
+		if basic, isBasic := t.Elem().Underlying().(*types.Basic); isBasic && basic.Kind() == types.Uint8 {
+			return "string", nil
+		}
+		return e.arrayOf(t.Elem())
+	case *types.Array:
+		return e.arrayOf(t.Elem())
+	case *types.Map:
+		key := "string"
+		if d, isDeclared := e.declOfType(t.Key()); isDeclared && d.kind == enumDecl {
+			name, err := e.referenceOf(d, nil)
+			if err != nil {
+				return "", err
+			}
+			key = name
+		}
+		value, err := e.typeOf(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Record<%s, %s>", key, value), nil
+	case *types.Interface:
+		return "unknown", nil
+	case *types.Struct:
+		props, extends, err := e.propertiesOf(t, nil)
+		if err != nil {
+			return "", err
+		}
+		members := make([]string, 0, len(props))
+		for _, prop := range props {
+			members = append(members, prop.String())
+		}
+		object := "{ " + strings.Join(members, "; ") + " }"
+		if len(members) == 0 {
+			object = "{}"
+		}
+		return strings.Join(append(extends, object), " & "), nil
+	}
+	return "", fmt.Errorf("type cannot be represented in TypeScript: %s", typ)
+}
+
+func (e *emitter) arrayOf(elem types.Type) (string, error) {
+	typ, err := e.typeOf(elem)
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(typ, " |&") {
+		typ = "(" + typ + ")"
+	}
+	return typ + "[]", nil
+}
+
+// referenceOf returns the reference to the declaration `d` instantiated with
+// `args` and imports it if it is declared in another module.
+func (e *emitter) referenceOf(d *decl, args *types.TypeList) (string, error) {
+	if d.module != e.module {
+		if e.imports[d.module] == nil {
+			e.imports[d.module] = make(map[string]bool)
+		}
+		e.imports[d.module][d.name] = true
+	}
+	if args.Len() == 0 {
+		return d.name, nil
+	}
+	names := make([]string, 0, args.Len())
+	for arg := range args.Types() {
+		name, err := e.typeOf(arg)
+		if err != nil {
+			return "", err
+		}
+		names = append(names, name)
+	}
+	return d.name + "<" + strings.Join(names, ", ") + ">", nil
+}
+
+func (e *emitter) declOfType(typ types.Type) (*decl, bool) {
+	switch t := typ.(type) {
+	case *types.Alias:
+		d, isDeclared := e.decls[t.Obj()]
+		return d, isDeclared
+	case *types.Named:
+		d, isDeclared := e.decls[t.Origin().Obj()]
+		return d, isDeclared
+	}
+	return nil, false
+}
+
+// wellKnown returns the TypeScript type of `typ` if it is a well-known type
+// or implements `encoding.TextMarshaler`.
+func (e *emitter) wellKnown(typ types.Type) (string, bool) {
+	if alias, isAlias := typ.(*types.Alias); isAlias {
+		if enc, isMapped := wellknown.Lookup(alias); isMapped {
+			return typeOfEncoding(enc), true
+		}
+	}
+	named, isNamed := types.Unalias(typ).(*types.Named)
+	if !isNamed {
+		return "", false
+	}
+	if enc, isMapped := wellknown.Lookup(named); isMapped {
+		return typeOfEncoding(enc), true
+	}
+	if _, isDeclared := e.decls[named.Origin().Obj()]; isDeclared {
+		return "", false
+	}
+	if wellknown.IsTextMarshaler(named) {
+		return "string", true
+	}
+	return "", false
+}
+
+// typeOfEncoding returns the TypeScript type of a well-known type with the
+// encoding `enc`.
+func typeOfEncoding(enc wellknown.Encoding) string {
+	switch enc {
+	case wellknown.Duration:
+		return "number"
+	case wellknown.Any:
+		return "unknown"
+	}
+	return "string"
+}
+
+// importPath returns the relative path of `module` from the module of the
+// emitter.
+func (e *emitter) importPath(module string) string {
+	rel, err := filepath.Rel(path.Dir(e.module), module)
+	if err != nil {
+		return "./" + module
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+func basicOf(basic *types.Basic) (string, error) {
+	info := basic.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return "boolean", nil
+	case info&types.IsString != 0:
+		return "string", nil
+	case info&(types.IsInteger|types.IsFloat) != 0:
+		return "number", nil
+	}
+	return "", fmt.Errorf("type cannot be represented in TypeScript: %s", basic)
+}
+
+// isStringable reports whether `typ` is encoded as string if the json struct
+// tag has the `string` option.
+func isStringable(typ types.Type) bool {
+	if ptr, isPointer := typ.Underlying().(*types.Pointer); isPointer {
+		typ = ptr.Elem()
+	}
+	basic, isBasic := typ.Underlying().(*types.Basic)
+	return isBasic && basic.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0
+}
+
+// jsonTagOf returns the name and the options of the json struct tag `tag`.
+func jsonTagOf(tag string) (string, []string) {
+	value, isDefined := reflect.StructTag(tag).Lookup("json")
+	if !isDefined {
+		return "", nil
+	}
+	name, opts, _ := strings.Cut(value, ",")
+	if opts == "" {
+		return name, nil
+	}
+	return name, strings.Split(opts, ",")
+}
+
+// jsDoc returns `doc` as JSDoc comment indented by `indent`.
+func jsDoc(doc, indent string) string {
+	if doc == "" {
+		return ""
+	}
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for line := range strings.SplitSeq(doc, "\n") {
+		b.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
+
+// quote returns `s` as string literal.
+func quote(s string) string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
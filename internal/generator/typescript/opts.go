@@ -0,0 +1,117 @@
+package typescript
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/optionutil"
+	"github.com/naivary/codemark/registry"
+)
+
+const (
+	_typeResource  = "type"
+	_fieldResource = "field"
+)
+
+const _unique = true
+
+// _identRegExp matches valid identifiers of TypeScript.
+var _identRegExp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// _moduleRegExp matches the relative path of a module without file
+// extension e.g. `api/users`.
+var _moduleRegExp = regexp.MustCompile(`^[A-Za-z0-9_\-]+(/[A-Za-z0-9_\-]+)*$`)
+
+func newRegistry() (regv1.Registry, error) {
+	opts := []*optionv1.Option{
+		mustMakeOpt(_typeResource, Name(""), _unique, optionv1.TargetStruct, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeResource, Module(""), _unique, optionv1.TargetStruct, optionv1.TargetNamed, optionv1.TargetAlias),
+		mustMakeOpt(_typeResource, Exclude(false), _unique, optionv1.TargetStruct, optionv1.TargetNamed, optionv1.TargetAlias, optionv1.TargetConst),
+		mustMakeOpt(_fieldResource, Name(""), _unique, optionv1.TargetField),
+		mustMakeOpt(_fieldResource, Exclude(false), _unique, optionv1.TargetField),
+		mustMakeOpt(_fieldResource, Optional(false), _unique, optionv1.TargetField),
+	}
+	reg := registry.InMemory()
+	for _, opt := range opts {
+		if err := reg.Define(opt); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+func mustMakeOpt(resource string, output any, isUnique bool, targets ...optionv1.Target) *optionv1.Option {
+	rtype := reflect.TypeOf(output)
+	doc := output.(openapi.Docer[docv1.Option]).Doc()
+	ident := fmt.Sprintf("%s:%s:%s", _domain, resource, openapi.CamelCase.Format(rtype.Name()))
+	opt := optionutil.MustMake(ident, rtype, &doc, isUnique, targets...)
+	return &opt
+}
+
+// optionOf returns the option of type `T` in `opts` which belongs to
+// `resource`.
+func optionOf[T any](opts infov1.Options, resource string) (T, bool) {
+	ident := fmt.Sprintf("%s:%s:%s", _domain, resource, openapi.CamelCase.Format(reflect.TypeFor[T]().Name()))
+	values, isDefined := opts.Get(ident)
+	if !isDefined {
+		var zero T
+		return zero, false
+	}
+	return values[0].(T), true
+}
+
+// Name renames the type or property in the generated TypeScript.
+type Name string
+
+func (n Name) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Name of the type or property in TypeScript. Defaults to the name of the go type and the name of the field in the json struct tag",
+	}
+}
+
+func (n Name) validate() error {
+	if !_identRegExp.MatchString(string(n)) {
+		return fmt.Errorf("name is not a valid TypeScript identifier: %s", n)
+	}
+	return nil
+}
+
+// Module is the module to which the type is written.
+type Module string
+
+func (m Module) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Module to which the type is written as relative path without file extension e.g. api/users. Defaults to the name of the package",
+	}
+}
+
+func (m Module) validate() error {
+	if !_moduleRegExp.MatchString(string(m)) {
+		return fmt.Errorf("module has to be a relative path without file extension e.g. api/users: %s", m)
+	}
+	return nil
+}
+
+// Exclude excludes a type, field or constant of an enum.
+type Exclude bool
+
+func (e Exclude) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Excludes the type, field or constant of an enum from the generated TypeScript. Fields of an excluded type inline its structure",
+	}
+}
+
+// Optional overwrites whether a property is optional.
+type Optional bool
+
+func (o Optional) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Marks the property as optional. Defaults to true for pointers and fields tagged with omitempty or omitzero in their json struct tag",
+	}
+}
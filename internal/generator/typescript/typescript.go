@@ -0,0 +1,174 @@
+package typescript
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/types"
+	"io/fs"
+	"maps"
+	"slices"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	genv1 "github.com/naivary/codemark/api/generator/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	configer "github.com/naivary/codemark/internal/config"
+	"github.com/naivary/codemark/internal/source"
+)
+
+const _domain = "typescript"
+
+var (
+	_ genv1.Generator     = (*typescriptGenerator)(nil)
+	_ genv1.ConfigSchemer = (*typescriptGenerator)(nil)
+)
+
+func New() (genv1.Generator, error) {
+	reg, err := newRegistry()
+	if err != nil {
+		return nil, err
+	}
+	configSchemas, err := configer.Schemas(_configSchemas)
+	if err != nil {
+		return nil, err
+	}
+	return &typescriptGenerator{reg: reg, configSchemas: configSchemas}, nil
+}
+
+type typescriptGenerator struct {
+	reg regv1.Registry
+
+	// configSchemas are the JSON Schemas of the config.
+	configSchemas fs.FS
+}
+
+func (g *typescriptGenerator) Domain() docv1.Domain {
+	return docv1.Domain{
+		Name: _domain,
+		Desc: "Generate TypeScript type definitions",
+	}
+}
+
+func (g *typescriptGenerator) ConfigSchema() (fs.FS, string) {
+	return g.configSchemas, configer.SchemaRoot
+}
+
+func (g *typescriptGenerator) Resources() map[string]*docv1.Resource {
+	return map[string]*docv1.Resource{
+		_typeResource:  {Desc: "Rename, exclude or move structs, named types, aliases and enums to another module"},
+		_fieldResource: {Desc: "Rename, exclude or mark the properties generated from fields as optional"},
+	}
+}
+
+func (g *typescriptGenerator) Registry() regv1.Registry {
+	return g.reg
+}
+
+func (g *typescriptGenerator) ConfigDoc() map[string]docv1.Config {
+	return map[string]docv1.Config{
+		"declaration": {
+			Default:     false,
+			Description: `Generates declaration files (.d.ts) instead of TypeScript modules (.ts). The content of the files is the same because only types are generated.`,
+		},
+	}
+}
+
+func (g *typescriptGenerator) Generate(proj infov1.Project, config map[string]any) ([]*genv1.Artifact, error) {
+	cfg, err := newConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	decls, err := declsOf(proj)
+	if err != nil {
+		return nil, err
+	}
+	byObj := make(map[*types.TypeName]*decl, len(decls))
+	modules := make(map[string][]*decl)
+	for _, d := range decls {
+		idx := slices.IndexFunc(modules[d.module], func(other *decl) bool { return other.name == d.name })
+		if idx != -1 {
+			other := modules[d.module][idx]
+			return nil, fmt.Errorf("types `%s.%s` and `%s.%s` have the same name in module `%s`: %s", other.obj.Pkg().Path(), other.obj.Name(), d.obj.Pkg().Path(), d.obj.Name(), d.module, d.name)
+		}
+		byObj[d.obj] = d
+		modules[d.module] = append(modules[d.module], d)
+	}
+	structs := structsOf(proj)
+	artifacts := make([]*genv1.Artifact, 0, len(modules))
+	for _, module := range slices.Sorted(maps.Keys(modules)) {
+		e := newEmitter(module, byObj, structs)
+		data, err := e.emit(modules[module])
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, &genv1.Artifact{
+			Name: module + cfg.ext(),
+			Data: bytes.NewBuffer(data),
+		})
+	}
+	return artifacts, nil
+}
+
+// declsOf returns the declarations of all the exported structs, named types,
+// enums and aliases of the project which are not excluded.
+func declsOf(proj infov1.Project) ([]*decl, error) {
+	all := make([]*decl, 0)
+	for _, pkg := range infov1.Packages(proj) {
+		decls := make([]*decl, 0)
+		info := proj[pkg]
+		for obj, sinfo := range infov1.ByPos(pkg.Fset, info.Structs) {
+			d, err := newDecl(pkg.Name, obj.(*types.TypeName), sinfo, source.SpecDocOf(sinfo.Decl, sinfo.Spec.Doc))
+			if err != nil {
+				return nil, err
+			}
+			if d == nil {
+				continue
+			}
+			d.kind, d.info = interfaceDecl, sinfo
+			decls = append(decls, d)
+		}
+		for obj, ninfo := range infov1.ByPos(pkg.Fset, info.Named) {
+			d, err := newDecl(pkg.Name, obj.(*types.TypeName), ninfo, source.SpecDocOf(ninfo.Decl, ninfo.Spec.Doc))
+			if err != nil {
+				return nil, err
+			}
+			if d == nil {
+				continue
+			}
+			d.kind = aliasDecl
+			if values := enumValuesOf(d.obj, info); len(values) > 0 {
+				d.kind, d.values = enumDecl, values
+			}
+			decls = append(decls, d)
+		}
+		for obj, ainfo := range infov1.ByPos(pkg.Fset, info.Aliases) {
+			d, err := newDecl(pkg.Name, obj.(*types.TypeName), ainfo, source.SpecDocOf(ainfo.Decl, ainfo.Spec.Doc))
+			if err != nil {
+				return nil, err
+			}
+			if d == nil {
+				continue
+			}
+			d.kind = aliasDecl
+			decls = append(decls, d)
+		}
+		// the declarations are emitted in the order of the source.
+		slices.SortStableFunc(decls, func(a, b *decl) int {
+			return cmp.Compare(a.obj.Pos(), b.obj.Pos())
+		})
+		all = append(all, decls...)
+	}
+	return all, nil
+}
+
+// structsOf returns the infos of all the structs of the project.
+func structsOf(proj infov1.Project) map[*types.TypeName]*infov1.StructInfo {
+	structs := make(map[*types.TypeName]*infov1.StructInfo)
+	for _, info := range proj {
+		for obj, sinfo := range info.Structs {
+			structs[obj.(*types.TypeName)] = sinfo
+		}
+	}
+	return structs
+}
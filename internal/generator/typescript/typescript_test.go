@@ -0,0 +1,224 @@
+package typescript
+
+import (
+	"io"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+func TestGenerator_Modules(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		cfg     map[string]any
+		isValid bool
+		want    map[string]string
+	}{
+		{
+			name:    "interfaces",
+			isValid: true,
+			files: map[string]string{
+				"api/user.go": `package api
+
+import "time"
+
+// User is a registered user.
+//
+// +typescript:type:module="models/user"
+type User struct {
+	// ID of the user.
+	ID        string     ` + "`json:\"id\"`" + `
+	Email     *string    ` + "`json:\"email\"`" + `
+	Nickname  string     ` + "`json:\"nickname,omitempty\"`" + `
+	Status    Status     ` + "`json:\"status\"`" + `
+	Roles     []Role     ` + "`json:\"roles\"`" + `
+	CreatedAt time.Time  ` + "`json:\"createdAt\"`" + `
+	Labels    map[string]string
+	// +typescript:field:exclude
+	Password  string
+	// +typescript:field:name="avatar_url"
+	// +typescript:field:optional
+	Avatar    string
+	secret    string
+}
+
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusBlocked Status = "blocked"
+	// +typescript:type:exclude
+	StatusMigrating Status = "migrating"
+)
+
+type Role int
+
+const (
+	RoleAdmin Role = iota
+	RoleMember
+)
+
+// +typescript:type:name="PageOf"
+type Page[T any] struct {
+	Items []T ` + "`json:\"items\"`" + `
+	Total int64 ` + "`json:\"total,string\"`" + `
+}
+
+type Users = Page[User]
+
+type Base struct {
+	Version int ` + "`json:\"version\"`" + `
+}
+
+type Event struct {
+	Base
+	Payload any ` + "`json:\"payload\"`" + `
+	Meta    struct {
+		Source string ` + "`json:\"source\"`" + `
+	} ` + "`json:\"meta\"`" + `
+}
+
+// +typescript:type:exclude
+type Internal struct{}
+`,
+			},
+			want: map[string]string{
+				"api.ts": `// Code generated by codemark. DO NOT EDIT.
+
+import type { User } from "./models/user";
+
+export type Status = "active" | "blocked";
+
+export type Role = 0 | 1;
+
+export interface PageOf<T> {
+  items: T[];
+  total: string;
+}
+
+export type Users = PageOf<User>;
+
+export interface Base {
+  version: number;
+}
+
+export interface Event extends Base {
+  payload: unknown;
+  meta: { source: string };
+}
+`,
+				"models/user.ts": `// Code generated by codemark. DO NOT EDIT.
+
+import type { Role, Status } from "../api";
+
+/**
+ * User is a registered user.
+ */
+export interface User {
+  /**
+   * ID of the user.
+   */
+  id: string;
+  email?: string;
+  nickname?: string;
+  status: Status;
+  roles: Role[];
+  createdAt: string;
+  Labels: Record<string, string>;
+  avatar_url?: string;
+}
+`,
+			},
+		},
+		{
+			name:    "declaration",
+			isValid: true,
+			cfg:     map[string]any{"declaration": true},
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	Name string
+}
+`,
+			},
+			want: map[string]string{
+				"api.d.ts": `// Code generated by codemark. DO NOT EDIT.
+
+export interface User {
+  Name: string;
+}
+`,
+			},
+		},
+		{
+			name:    "duplicate name",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct{}
+
+// +typescript:type:name="User"
+type Account struct{}
+`,
+			},
+		},
+		{
+			name:    "invalid module",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+// +typescript:type:module="../user"
+type User struct{}
+`,
+			},
+		},
+		{
+			name:    "unsupported type",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	Notify chan string
+}
+`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, tc.files, tc.cfg)
+			if !tc.isValid && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !tc.isValid {
+				return
+			}
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			got := make(map[string]string, len(artifacts))
+			for _, artifact := range artifacts {
+				data, err := io.ReadAll(artifact.Data)
+				if err != nil {
+					t.Fatalf("err occurred: %v", err)
+				}
+				got[artifact.Name] = string(data)
+			}
+			if !slices.Equal(slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(tc.want))) {
+				t.Fatalf("modules are not equal. got: %v; want: %v", slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(tc.want)))
+			}
+			for name, want := range tc.want {
+				if got[name] != want {
+					t.Errorf("module %s is not equal\ngot:\n%s\nwant:\n%s", name, got[name], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package validate
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	"github.com/naivary/codemark/internal/generator/openapi"
+)
+
+// targets are the structs of the project which get a generated Validate
+// method.
+type targets map[*types.TypeName]*infov1.StructInfo
+
+// collect returns the structs of `proj` which have at least one field with an
+// openapi schema constraint and are not excluded.
+func collect(proj infov1.Project, cfg *config) (targets, error) {
+	t := make(targets)
+	for _, pkg := range infov1.Packages(proj) {
+		for obj, sinfo := range infov1.ByPos(pkg.Fset, proj[pkg].Structs) {
+			if isExcluded(sinfo, _structResource) || !hasConstraints(sinfo) {
+				continue
+			}
+			tn := obj.(*types.TypeName)
+			if err := isValidateFree(pkg.Fset, tn, cfg); err != nil {
+				return nil, err
+			}
+			t[tn] = sinfo
+		}
+	}
+	return t, nil
+}
+
+// isValidateFree checks that `obj` has no Validate method beside the one of
+// a previous generation.
+func isValidateFree(fset *token.FileSet, obj *types.TypeName, cfg *config) error {
+	method, _, _ := types.LookupFieldOrMethod(types.NewPointer(obj.Type()), false, obj.Pkg(), "Validate")
+	if method == nil || filepath.Base(fset.Position(method.Pos()).Filename) == cfg.Filename {
+		return nil
+	}
+	return fmt.Errorf(
+		"struct `%s` already has a Validate method. Use +%s:%s:exclude to exclude it from the generation",
+		obj.Name(),
+		_domain,
+		_structResource,
+	)
+}
+
+// ofPkg returns the targets of `pkg` sorted by their position.
+func (t targets) ofPkg(pkg *types.Package) []*types.TypeName {
+	objs := make([]*types.TypeName, 0)
+	for obj := range t {
+		if obj.Pkg() == pkg {
+			objs = append(objs, obj)
+		}
+	}
+	slices.SortFunc(objs, func(a, b *types.TypeName) int {
+		return cmp.Compare(a.Pos(), b.Pos())
+	})
+	return objs
+}
+
+// hasValidate reports whether values of `typ` have a Validate method after
+// the generation.
+func (t targets) hasValidate(typ types.Type) bool {
+	named, isNamed := types.Unalias(typ).(*types.Named)
+	if !isNamed {
+		return false
+	}
+	if _, isTarget := t[named.Origin().Obj()]; isTarget {
+		return true
+	}
+	obj, _, _ := types.LookupFieldOrMethod(named, true, named.Obj().Pkg(), "Validate")
+	fn, isFunc := obj.(*types.Func)
+	if !isFunc {
+		return false
+	}
+	sig := fn.Signature()
+	return sig.Params().Len() == 0 && sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+// hasConstraints reports whether any field of `sinfo`, which is not excluded,
+// has an openapi schema constraint.
+func hasConstraints(sinfo *infov1.StructInfo) bool {
+	for _, finfo := range sinfo.Fields {
+		if isExcluded(finfo, _fieldResource) {
+			continue
+		}
+		for _, ident := range _constraints {
+			if _, isDefined := finfo.Options().Get(ident); isDefined {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// _constraints are the idents of the openapi schema options which are
+// translated into checks.
+var _constraints = []string{
+	schemaIdentOf[openapi.Required](),
+	schemaIdentOf[openapi.MinLength](),
+	schemaIdentOf[openapi.MaxLength](),
+	schemaIdentOf[openapi.Pattern](),
+	schemaIdentOf[openapi.Minimum](),
+	schemaIdentOf[openapi.Maximum](),
+	schemaIdentOf[openapi.ExclusiveMinimum](),
+	schemaIdentOf[openapi.ExclusiveMaximum](),
+	schemaIdentOf[openapi.MultipleOf](),
+	schemaIdentOf[openapi.Enum](),
+	schemaIdentOf[openapi.MinItems](),
+	schemaIdentOf[openapi.MaxItems](),
+}
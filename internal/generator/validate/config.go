@@ -0,0 +1,31 @@
+package validate
+
+import (
+	"embed"
+
+	"github.com/goccy/go-yaml"
+)
+
+// _configSchemas contains config.json which requires the filename option of
+// `config` to name a go file.
+//
+//go:embed schemas/*.json
+var _configSchemas embed.FS
+
+func newConfig(cfg map[string]any) (*config, error) {
+	c := config{
+		Filename: "zz_generated.validate.go",
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &c)
+	return &c, err
+}
+
+// +openapi:schema:description="config options for the validate generator"
+type config struct {
+	// +openapi:schema:pattern="^[a-zA-Z0-9_.]+\\.go$"
+	Filename string `yaml:"filename"`
+}
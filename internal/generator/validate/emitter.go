@@ -0,0 +1,476 @@
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/types"
+	"maps"
+	"math/big"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	"github.com/naivary/codemark/internal/generator/openapi"
+)
+
+// _sizes are the sizes used to check if the bound of a constraint can be
+// represented by the integer type of a field.
+var _sizes = types.SizesFor("gc", "amd64")
+
+// pattern is a package level variable of a compiled regular expression.
+type pattern struct {
+	name string
+	expr string
+}
+
+// emitter emits the Validate methods of the targets of a package.
+type emitter struct {
+	pkg     *types.Package
+	targets targets
+
+	body     bytes.Buffer
+	patterns []pattern
+	imports  map[string]bool
+}
+
+func newEmitter(pkg *types.Package, t targets) *emitter {
+	return &emitter{
+		pkg:     pkg,
+		targets: t,
+		imports: map[string]bool{"errors": true},
+	}
+}
+
+// source returns the formatted source code of the generated file.
+func (e *emitter) source() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by codemark. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", e.pkg.Name())
+	buf.WriteString("import (\n")
+	for _, path := range slices.Sorted(maps.Keys(e.imports)) {
+		fmt.Fprintf(&buf, "%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+	if len(e.patterns) > 0 {
+		buf.WriteString("var (\n")
+		for _, p := range e.patterns {
+			fmt.Fprintf(&buf, "%s = regexp.MustCompile(%s)\n", p.name, strconv.Quote(p.expr))
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(e.body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+func (e *emitter) printf(format string, args ...any) {
+	fmt.Fprintf(&e.body, format, args...)
+}
+
+func (e *emitter) emitStruct(obj *types.TypeName, sinfo *infov1.StructInfo) error {
+	named := obj.Type().(*types.Named)
+	recv := receiverOf(obj.Name())
+	typeParams := make([]string, 0, named.TypeParams().Len())
+	for tparam := range named.TypeParams().TypeParams() {
+		typeParams = append(typeParams, tparam.Obj().Name())
+	}
+	typ := obj.Name()
+	if len(typeParams) > 0 {
+		typ = fmt.Sprintf("%s[%s]", typ, strings.Join(typeParams, ", "))
+	}
+	e.printf("// Validate returns the violations of the constraints of the fields of\n// %s joined into one error.\n", obj.Name())
+	e.printf("func (%s %s) Validate() error {\nvar errs []error\n", recv, typ)
+	strct := named.Underlying().(*types.Struct)
+	for i := range strct.NumFields() {
+		field := strct.Field(i)
+		finfo := sinfo.Fields[field]
+		if finfo == nil || isExcluded(finfo, _fieldResource) {
+			continue
+		}
+		f := fieldOf(obj, recv, field, finfo, reflect.StructTag(strct.Tag(i)))
+		if err := e.emitField(f); err != nil {
+			return fmt.Errorf("field `%s` is invalid: %w", field.Name(), err)
+		}
+	}
+	e.printf("return errors.Join(errs...)\n}\n\n")
+	return nil
+}
+
+// field is a field of a struct which is validated.
+type field struct {
+	v    *types.Var
+	opts infov1.Options
+	// name of the field used in the error messages
+	name string
+	// access is the expression to access the field
+	access string
+	// isOmittable reports whether the zero value of the field is omitted in
+	// its json encoding.
+	isOmittable bool
+	// patternName is the name of the variable of the compiled pattern of the
+	// field.
+	patternName string
+}
+
+func fieldOf(obj *types.TypeName, recv string, v *types.Var, finfo *infov1.FieldInfo, tag reflect.StructTag) *field {
+	f := &field{
+		v:           v,
+		opts:        finfo.Options(),
+		name:        v.Name(),
+		access:      recv + "." + v.Name(),
+		patternName: fmt.Sprintf("_%s%sPattern", lowerFirst(obj.Name()), v.Name()),
+	}
+	name, tagOpts, _ := strings.Cut(tag.Get("json"), ",")
+	if name != "" && name != "-" {
+		f.name = name
+	}
+	for opt := range strings.SplitSeq(tagOpts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			f.isOmittable = true
+		}
+	}
+	return f
+}
+
+func (e *emitter) emitField(f *field) error {
+	required, _ := schemaOptionOf[openapi.Required](f.opts)
+	isRequired := bool(required)
+	typ := f.v.Type()
+	if isRequired {
+		if cond, isCheckable := zeroCheckOf(f.access, typ); isCheckable {
+			e.printf("if %s {\nerrs = append(errs, %s)\n}\n", cond, e.errorOf(f.name, _noIndex, "is required"))
+		}
+	}
+	value := f.access
+	guard := ""
+	if ptr, isPointer := typ.Underlying().(*types.Pointer); isPointer {
+		guard = f.access + " != nil"
+		value = "(*" + f.access + ")"
+		typ = ptr.Elem()
+	} else if f.isOmittable && !isRequired {
+		if cond, isCheckable := zeroCheckOf(f.access, typ); isCheckable {
+			guard = "!(" + cond + ")"
+		}
+	}
+	var checks bytes.Buffer
+	if err := e.checksOf(&checks, f, value, typ, _noIndex); err != nil {
+		return err
+	}
+	if checks.Len() == 0 {
+		return nil
+	}
+	if guard == "" {
+		e.body.Write(checks.Bytes())
+		return nil
+	}
+	e.printf("if %s {\n%s}\n", guard, checks.Bytes())
+	return nil
+}
+
+// The verbs formatting the index of an element in the error of a violation.
+const (
+	// _noIndex is used if the value is not an element.
+	_noIndex = ""
+	// _listIndex formats the index of an element of a list.
+	_listIndex = "%d"
+	// _mapIndex formats the key of a value of a map.
+	_mapIndex = "%v"
+)
+
+// checksOf writes the checks of the constraints of `f` for `value` of type
+// `typ` to `w`. `index` is the verb formatting the index `idx` if `value` is
+// an element of the list or map `f`. The constraints of a list or map apply to
+// its elements like the openapi generator applies them to the items or
+// additional properties.
+func (e *emitter) checksOf(w *bytes.Buffer, f *field, value string, typ types.Type, index string) error {
+	if index == _noIndex {
+		elem, elemIndex := listElemOf(typ), _listIndex
+		if m, isMap := typ.Underlying().(*types.Map); isMap {
+			elem, elemIndex = m.Elem(), _mapIndex
+		}
+		if elem != nil {
+			if elemIndex == _listIndex {
+				e.lengthChecksOf(w, f, value)
+			}
+			var elemChecks bytes.Buffer
+			if err := e.checksOf(&elemChecks, f, "elem", elem, elemIndex); err != nil {
+				return err
+			}
+			if elemChecks.Len() > 0 {
+				fmt.Fprintf(w, "for idx, elem := range %s {\n%s}\n", value, elemChecks.Bytes())
+			}
+			return nil
+		}
+	}
+	if ptr, isPointer := typ.Underlying().(*types.Pointer); isPointer && index != _noIndex {
+		var ptrChecks bytes.Buffer
+		if err := e.checksOf(&ptrChecks, f, "(*"+value+")", ptr.Elem(), index); err != nil {
+			return err
+		}
+		if ptrChecks.Len() > 0 {
+			fmt.Fprintf(w, "if %s != nil {\n%s}\n", value, ptrChecks.Bytes())
+		}
+		return nil
+	}
+	if e.targets.hasValidate(typ) {
+		fmt.Fprintf(w, "if err := %s.Validate(); err != nil {\nerrs = append(errs, %s)\n}\n", value, e.wrappedErrorOf(f.name, index))
+		return nil
+	}
+	basic, isBasic := typ.Underlying().(*types.Basic)
+	if !isBasic {
+		return nil
+	}
+	if enum, isDefined := schemaOptionOf[openapi.Enum](f.opts); isDefined {
+		if err := e.enumCheckOf(w, f, value, basic, index, enum); err != nil {
+			return err
+		}
+	}
+	switch {
+	case basic.Info()&types.IsString != 0:
+		return e.stringChecksOf(w, f, value, index)
+	case basic.Info()&types.IsNumeric != 0:
+		return e.numericChecksOf(w, f, value, basic, index)
+	}
+	return nil
+}
+
+func (e *emitter) stringChecksOf(w *bytes.Buffer, f *field, value, index string) error {
+	if minLength, isDefined := schemaOptionOf[openapi.MinLength](f.opts); isDefined {
+		e.imports["unicode/utf8"] = true
+		fmt.Fprintf(w, "if utf8.RuneCountInString(string(%s)) < %d {\nerrs = append(errs, %s)\n}\n",
+			value, minLength, e.errorOf(f.name, index, fmt.Sprintf("must have at least %d characters", minLength)))
+	}
+	if maxLength, isDefined := schemaOptionOf[openapi.MaxLength](f.opts); isDefined {
+		e.imports["unicode/utf8"] = true
+		fmt.Fprintf(w, "if utf8.RuneCountInString(string(%s)) > %d {\nerrs = append(errs, %s)\n}\n",
+			value, maxLength, e.errorOf(f.name, index, fmt.Sprintf("must have at most %d characters", maxLength)))
+	}
+	if expr, isDefined := schemaOptionOf[openapi.Pattern](f.opts); isDefined {
+		if _, err := regexp.Compile(string(expr)); err != nil {
+			return fmt.Errorf("pattern is not a valid regular expression: %w", err)
+		}
+		e.imports["regexp"] = true
+		e.patterns = append(e.patterns, pattern{name: f.patternName, expr: string(expr)})
+		fmt.Fprintf(w, "if !%s.MatchString(string(%s)) {\nerrs = append(errs, %s)\n}\n",
+			f.patternName, value, e.errorOf(f.name, index, fmt.Sprintf("must match the pattern %s", expr)))
+	}
+	return nil
+}
+
+func (e *emitter) numericChecksOf(w *bytes.Buffer, f *field, value string, basic *types.Basic, index string) error {
+	bounds := []struct {
+		ident string
+		op    string
+		msg   string
+	}{
+		{ident: schemaIdentOf[openapi.Minimum](), op: "<", msg: "must be greater than or equal to"},
+		{ident: schemaIdentOf[openapi.Maximum](), op: ">", msg: "must be less than or equal to"},
+		{ident: schemaIdentOf[openapi.ExclusiveMinimum](), op: "<=", msg: "must be greater than"},
+		{ident: schemaIdentOf[openapi.ExclusiveMaximum](), op: ">=", msg: "must be less than"},
+	}
+	for _, bound := range bounds {
+		values, isDefined := f.opts.Get(bound.ident)
+		if !isDefined {
+			continue
+		}
+		lit := fmt.Sprint(values[0])
+		operand, limit, err := numericOperandsOf(value, basic, lit)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "if %s %s %s {\nerrs = append(errs, %s)\n}\n",
+			operand, bound.op, limit, e.errorOf(f.name, index, fmt.Sprintf("%s %s", bound.msg, lit)))
+	}
+	multipleOf, isDefined := schemaOptionOf[openapi.MultipleOf](f.opts)
+	if !isDefined {
+		return nil
+	}
+	if n, _ := openapi.NumberOf(string(multipleOf)); n.Sign() <= 0 {
+		return fmt.Errorf("multipleOf must be greater than 0: %s", multipleOf)
+	}
+	msg := e.errorOf(f.name, index, "must be a multiple of "+string(multipleOf))
+	operand, limit, err := numericOperandsOf(value, basic, string(multipleOf))
+	if err != nil {
+		return err
+	}
+	if operand == value && basic.Info()&types.IsInteger != 0 {
+		fmt.Fprintf(w, "if %s%%%s != 0 {\nerrs = append(errs, %s)\n}\n", value, limit, msg)
+		return nil
+	}
+	e.imports["math"] = true
+	fmt.Fprintf(w, "if math.Mod(float64(%s), %s) != 0 {\nerrs = append(errs, %s)\n}\n", value, limit, msg)
+	return nil
+}
+
+// numericOperandsOf returns the operand of `value` and the literal of the
+// number `lit` to compare them. Integers are compared as integers if `lit` is
+// an integer which can be represented by the type of `value`. Otherwise both
+// are compared as float64 which requires `lit` to be exactly representable by
+// a float64.
+func numericOperandsOf(value string, basic *types.Basic, lit string) (string, string, error) {
+	n, isInt := openapi.NumberOf(lit)
+	if basic.Info()&types.IsInteger != 0 && isInt {
+		i, _ := n.Int(nil)
+		if isRepresentable(basic, i) {
+			return value, i.String(), nil
+		}
+	}
+	if !openapi.IsExactFloat(lit) {
+		return "", "", fmt.Errorf("%s cannot be represented exactly by a float64", lit)
+	}
+	if basic.Info()&types.IsFloat != 0 {
+		return value, lit, nil
+	}
+	return "float64(" + value + ")", lit, nil
+}
+
+// isRepresentable reports whether the integer `limit` can be represented by
+// the integer type `basic`.
+func isRepresentable(basic *types.Basic, limit *big.Int) bool {
+	bits := uint(_sizes.Sizeof(basic) * 8)
+	minInt, maxInt := new(big.Int), new(big.Int).Lsh(big.NewInt(1), bits)
+	if basic.Info()&types.IsUnsigned == 0 {
+		maxInt.Rsh(maxInt, 1)
+		minInt.Neg(maxInt)
+	}
+	maxInt.Sub(maxInt, big.NewInt(1))
+	return limit.Cmp(minInt) >= 0 && limit.Cmp(maxInt) <= 0
+}
+
+func (e *emitter) lengthChecksOf(w *bytes.Buffer, f *field, value string) {
+	if minItems, isDefined := schemaOptionOf[openapi.MinItems](f.opts); isDefined {
+		fmt.Fprintf(w, "if len(%s) < %d {\nerrs = append(errs, %s)\n}\n",
+			value, minItems, e.errorOf(f.name, _noIndex, fmt.Sprintf("must have at least %d items", minItems)))
+	}
+	if maxItems, isDefined := schemaOptionOf[openapi.MaxItems](f.opts); isDefined {
+		fmt.Fprintf(w, "if len(%s) > %d {\nerrs = append(errs, %s)\n}\n",
+			value, maxItems, e.errorOf(f.name, _noIndex, fmt.Sprintf("must have at most %d items", maxItems)))
+	}
+}
+
+// enumCheckOf writes a switch to `w` checking that `value` is one of the
+// values of `enum`. nil values of the enum are ignored because only non nil
+// values are checked.
+func (e *emitter) enumCheckOf(w *bytes.Buffer, f *field, value string, basic *types.Basic, index string, enum openapi.Enum) error {
+	lits := make([]string, 0, len(enum))
+	for _, v := range enum {
+		if v == nil || v == "nil" {
+			continue
+		}
+		lit, err := enumLiteralOf(v, basic)
+		if err != nil {
+			return err
+		}
+		lits = append(lits, lit)
+	}
+	if len(lits) == 0 {
+		return nil
+	}
+	msg := e.errorOf(f.name, index, "must be one of "+strings.Join(lits, ", "))
+	fmt.Fprintf(w, "switch %s {\ncase %s:\ndefault:\nerrs = append(errs, %s)\n}\n", value, strings.Join(lits, ", "), msg)
+	return nil
+}
+
+// enumLiteralOf returns the go literal of the enum value `v` for the type
+// `basic`.
+func enumLiteralOf(v any, basic *types.Basic) (string, error) {
+	info := basic.Info()
+	switch v := v.(type) {
+	case string:
+		if info&types.IsString != 0 {
+			return strconv.Quote(v), nil
+		}
+	case int64:
+		if info&types.IsNumeric != 0 {
+			return strconv.FormatInt(v, 10), nil
+		}
+	case float64:
+		if info&types.IsFloat != 0 {
+			return strconv.FormatFloat(v, 'g', -1, 64), nil
+		}
+	case bool:
+		return "", errors.New("an enum for a boolean is unnecessary")
+	}
+	return "", fmt.Errorf("enum value `%v` cannot be assigned to type %s", v, basic.Name())
+}
+
+// zeroCheckOf returns the condition which is true if `access` of type `typ`
+// is the zero value. Only types which are comparable with a literal are
+// checkable.
+func zeroCheckOf(access string, typ types.Type) (string, bool) {
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Map, *types.Chan, *types.Signature:
+		return access + " == nil", true
+	case *types.Slice:
+		return "len(" + access + ") == 0", true
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsString != 0:
+			return access + ` == ""`, true
+		case t.Info()&types.IsNumeric != 0:
+			return access + " == 0", true
+		}
+	}
+	return "", false
+}
+
+// listElemOf returns the element type of `typ` if it is a slice or an array
+// or nil otherwise. Byte slices are not lists.
+func listElemOf(typ types.Type) types.Type {
+	var elem types.Type
+	switch t := typ.Underlying().(type) {
+	case *types.Slice:
+		elem = t.Elem()
+	case *types.Array:
+		elem = t.Elem()
+	default:
+		return nil
+	}
+	if basic, isBasic := elem.Underlying().(*types.Basic); isBasic && basic.Kind() == types.Byte {
+		return nil
+	}
+	return elem
+}
+
+// errorOf returns the expression creating the error of a violation of the
+// field `name`. If `index` is not empty the error is about the element at the
+// index `idx` of the field which is formatted using `index`.
+func (e *emitter) errorOf(name string, index string, msg string) string {
+	if index == _noIndex {
+		return fmt.Sprintf("errors.New(%s)", strconv.Quote(name+" "+msg))
+	}
+	e.imports["fmt"] = true
+	return fmt.Sprintf("fmt.Errorf(%s, idx)", strconv.Quote(name+"["+index+"] "+strings.ReplaceAll(msg, "%", "%%")))
+}
+
+// wrappedErrorOf returns the expression wrapping the error `err` of the
+// Validate method of the field `name`. `index` is used like in errorOf.
+func (e *emitter) wrappedErrorOf(name string, index string) string {
+	e.imports["fmt"] = true
+	name = strings.ReplaceAll(name, "%", "%%")
+	if index == _noIndex {
+		return fmt.Sprintf("fmt.Errorf(%s, err)", strconv.Quote(name+": %w"))
+	}
+	return fmt.Sprintf("fmt.Errorf(%s, idx, err)", strconv.Quote(name+"["+index+"]: %w"))
+}
+
+// receiverOf returns the name of the receiver of the methods of the type
+// `name`.
+func receiverOf(name string) string {
+	r := []rune(name)[0]
+	if !unicode.IsLetter(r) {
+		return "x"
+	}
+	return string(unicode.ToLower(r))
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,36 @@
+package validate
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"testing"
+)
+
+// typeCheck type checks the package in `dir` consisting of the go files of
+// `files` in the directory of the package and the generated `artifact` which
+// replaces any previously generated file.
+func typeCheck(t *testing.T, files map[string]string, dir string, artifact string) error {
+	t.Helper()
+	fset := token.NewFileSet()
+	srcs := map[string]string{"zz_generated.validate.go": artifact}
+	for name, src := range files {
+		if path.Dir(name) == dir && path.Ext(name) == ".go" && path.Base(name) != "zz_generated.validate.go" {
+			srcs[name] = src
+		}
+	}
+	astFiles := make([]*ast.File, 0, len(srcs))
+	for name, src := range srcs {
+		file, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			return err
+		}
+		astFiles = append(astFiles, file)
+	}
+	cfg := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err := cfg.Check(dir, fset, astFiles, nil)
+	return err
+}
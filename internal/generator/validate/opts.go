@@ -0,0 +1,77 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	optionv1 "github.com/naivary/codemark/api/option/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/optionutil"
+	"github.com/naivary/codemark/registry"
+)
+
+const (
+	_structResource = "struct"
+	_fieldResource  = "field"
+)
+
+const _unique = true
+
+// _schemaDomain is the domain and resource of the openapi markers which are
+// translated into go checks.
+const _schemaDomain = "openapi:schema"
+
+func newRegistry() (regv1.Registry, error) {
+	opts := []*optionv1.Option{
+		mustMakeOpt(_structResource, Exclude(false), _unique, optionv1.TargetStruct),
+		mustMakeOpt(_fieldResource, Exclude(false), _unique, optionv1.TargetField),
+	}
+	reg := registry.InMemory()
+	for _, opt := range opts {
+		if err := reg.Define(opt); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+func mustMakeOpt(resource string, output any, isUnique bool, targets ...optionv1.Target) *optionv1.Option {
+	rtype := reflect.TypeOf(output)
+	doc := output.(openapi.Docer[docv1.Option]).Doc()
+	ident := fmt.Sprintf("%s:%s:%s", _domain, resource, openapi.CamelCase.Format(rtype.Name()))
+	opt := optionutil.MustMake(ident, rtype, &doc, isUnique, targets...)
+	return &opt
+}
+
+// isExcluded reports whether `info` has the exclude marker of `resource`.
+func isExcluded(info infov1.Info, resource string) bool {
+	values, isDefined := info.Options().Get(fmt.Sprintf("%s:%s:exclude", _domain, resource))
+	return isDefined && bool(values[0].(Exclude))
+}
+
+// schemaIdentOf returns the ident of the openapi schema option of type `T`.
+func schemaIdentOf[T any]() string {
+	return fmt.Sprintf("%s:%s", _schemaDomain, openapi.CamelCase.Format(reflect.TypeFor[T]().Name()))
+}
+
+// schemaOptionOf returns the openapi schema option of type `T` in `opts`.
+func schemaOptionOf[T any](opts infov1.Options) (T, bool) {
+	values, isDefined := opts.Get(schemaIdentOf[T]())
+	if !isDefined {
+		var zero T
+		return zero, false
+	}
+	return values[0].(T), true
+}
+
+// Exclude excludes a struct or field from the validation.
+type Exclude bool
+
+func (e Exclude) Doc() docv1.Option {
+	return docv1.Option{
+		Desc: "Excludes the struct or field from the generated validation. Excluded structs get no Validate method",
+	}
+}
@@ -0,0 +1,109 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	docv1 "github.com/naivary/codemark/api/doc/v1"
+	genv1 "github.com/naivary/codemark/api/generator/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	configer "github.com/naivary/codemark/internal/config"
+	"github.com/naivary/codemark/internal/source"
+)
+
+const _domain = "validate"
+
+var (
+	_ genv1.Generator     = (*validateGenerator)(nil)
+	_ genv1.ConfigSchemer = (*validateGenerator)(nil)
+)
+
+func New() (genv1.Generator, error) {
+	reg, err := newRegistry()
+	if err != nil {
+		return nil, err
+	}
+	configSchemas, err := configer.Schemas(_configSchemas)
+	if err != nil {
+		return nil, err
+	}
+	return &validateGenerator{reg: reg, configSchemas: configSchemas}, nil
+}
+
+type validateGenerator struct {
+	reg regv1.Registry
+
+	// configSchemas are the JSON Schemas of the config.
+	configSchemas fs.FS
+}
+
+func (g *validateGenerator) Domain() docv1.Domain {
+	return docv1.Domain{
+		Name: _domain,
+		Desc: "Generate Validate methods for structs from the openapi schema markers",
+	}
+}
+
+func (g *validateGenerator) ConfigSchema() (fs.FS, string) {
+	return g.configSchemas, configer.SchemaRoot
+}
+
+func (g *validateGenerator) Resources() map[string]*docv1.Resource {
+	return map[string]*docv1.Resource{
+		_structResource: {Desc: "Exclude a struct from the generated validation"},
+		_fieldResource:  {Desc: "Exclude a field from the generated validation"},
+	}
+}
+
+func (g *validateGenerator) Registry() regv1.Registry {
+	return g.reg
+}
+
+func (g *validateGenerator) ConfigDoc() map[string]docv1.Config {
+	return map[string]docv1.Config{
+		"filename": {
+			Default:     "zz_generated.validate.go",
+			Description: `Name of the file generated in the directory of every package containing structs with openapi schema markers. Validate methods declared in any other file of the package are an error.`,
+		},
+	}
+}
+
+func (g *validateGenerator) Generate(proj infov1.Project, config map[string]any) ([]*genv1.Artifact, error) {
+	cfg, err := newConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := collect(proj, cfg)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := make([]*genv1.Artifact, 0)
+	for _, pkg := range infov1.Packages(proj) {
+		structs := targets.ofPkg(pkg.Types)
+		if len(structs) == 0 {
+			continue
+		}
+		dir, err := source.PackageDirOf(pkg)
+		if err != nil {
+			return nil, err
+		}
+		e := newEmitter(pkg.Types, targets)
+		for _, obj := range structs {
+			if err := e.emitStruct(obj, targets[obj]); err != nil {
+				return nil, fmt.Errorf("struct `%s` is invalid: %w", obj.Name(), err)
+			}
+		}
+		src, err := e.source()
+		if err != nil {
+			return nil, fmt.Errorf("generated code of package `%s` is invalid: %w", pkg.PkgPath, err)
+		}
+		artifacts = append(artifacts, &genv1.Artifact{
+			Name: filepath.ToSlash(filepath.Join(dir, cfg.Filename)),
+			Data: bytes.NewBuffer(src),
+		})
+	}
+	return artifacts, nil
+}
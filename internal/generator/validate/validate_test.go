@@ -0,0 +1,448 @@
+package validate
+
+import (
+	"io"
+	"path"
+	"testing"
+
+	"github.com/naivary/codemark/internal/generator/openapi"
+	"github.com/naivary/codemark/loader/loadertest"
+)
+
+func TestGenerator_Files(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		isValid bool
+		// wantName is the name of the generated artifact
+		wantName string
+		want     string
+	}{
+		{
+			name:     "constraints",
+			isValid:  true,
+			wantName: "api/zz_generated.validate.go",
+			files: map[string]string{
+				"api/user.go": `package api
+
+type Role string
+
+type User struct {
+	// +openapi:schema:required
+	// +openapi:schema:minLength=3
+	// +openapi:schema:maxLength=20
+	// +openapi:schema:pattern="^[a-z]+$"
+	Name string ` + "`json:\"name\"`" + `
+	// +openapi:schema:minimum=18
+	// +openapi:schema:exclusiveMaximum=150.5
+	Age int ` + "`json:\"age,omitempty\"`" + `
+	// +openapi:schema:multipleOf=0.5
+	Score float64
+	// +openapi:schema:enum=["admin", "member"]
+	Role *Role ` + "`json:\"role\"`" + `
+	// +openapi:schema:minItems=1
+	// +openapi:schema:enum=["a", "b"]
+	Tags []string ` + "`json:\"tags\"`" + `
+	Address *Address ` + "`json:\"address\"`" + `
+	Friends []User ` + "`json:\"friends\"`" + `
+	// +openapi:schema:minLength=100
+	// +validate:field:exclude
+	Ignored string
+}
+
+type Address struct {
+	// +openapi:schema:required
+	Street string
+}
+
+// +validate:struct:exclude
+type Hidden struct {
+	// +openapi:schema:required
+	Name string
+}
+`,
+			},
+			want: `// Code generated by codemark. DO NOT EDIT.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	_userNamePattern = regexp.MustCompile("^[a-z]+$")
+)
+
+// Validate returns the violations of the constraints of the fields of
+// User joined into one error.
+func (u User) Validate() error {
+	var errs []error
+	if u.Name == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if utf8.RuneCountInString(string(u.Name)) < 3 {
+		errs = append(errs, errors.New("name must have at least 3 characters"))
+	}
+	if utf8.RuneCountInString(string(u.Name)) > 20 {
+		errs = append(errs, errors.New("name must have at most 20 characters"))
+	}
+	if !_userNamePattern.MatchString(string(u.Name)) {
+		errs = append(errs, errors.New("name must match the pattern ^[a-z]+$"))
+	}
+	if !(u.Age == 0) {
+		if u.Age < 18 {
+			errs = append(errs, errors.New("age must be greater than or equal to 18"))
+		}
+		if float64(u.Age) >= 150.5 {
+			errs = append(errs, errors.New("age must be less than 150.5"))
+		}
+	}
+	if math.Mod(float64(u.Score), 0.5) != 0 {
+		errs = append(errs, errors.New("Score must be a multiple of 0.5"))
+	}
+	if u.Role != nil {
+		switch *u.Role {
+		case "admin", "member":
+		default:
+			errs = append(errs, errors.New("role must be one of \"admin\", \"member\""))
+		}
+	}
+	if len(u.Tags) < 1 {
+		errs = append(errs, errors.New("tags must have at least 1 items"))
+	}
+	for idx, elem := range u.Tags {
+		switch elem {
+		case "a", "b":
+		default:
+			errs = append(errs, fmt.Errorf("tags[%d] must be one of \"a\", \"b\"", idx))
+		}
+	}
+	if u.Address != nil {
+		if err := (*u.Address).Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("address: %w", err))
+		}
+	}
+	for idx, elem := range u.Friends {
+		if err := elem.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("friends[%d]: %w", idx, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate returns the violations of the constraints of the fields of
+// Address joined into one error.
+func (a Address) Validate() error {
+	var errs []error
+	if a.Street == "" {
+		errs = append(errs, errors.New("Street is required"))
+	}
+	return errors.Join(errs...)
+}
+`,
+		},
+		{
+			name:     "generic",
+			isValid:  true,
+			wantName: "zz_generated.validate.go",
+			files: map[string]string{
+				"page.go": `package page
+
+type Page[T any] struct {
+	// +openapi:schema:maxItems=100
+	Items []T
+	// +openapi:schema:minimum=0
+	// +openapi:schema:multipleOf=10
+	Offset uint8
+	// +openapi:schema:maximum=1000
+	Limit uint8
+}
+`,
+			},
+			want: `// Code generated by codemark. DO NOT EDIT.
+
+package page
+
+import (
+	"errors"
+)
+
+// Validate returns the violations of the constraints of the fields of
+// Page joined into one error.
+func (p Page[T]) Validate() error {
+	var errs []error
+	if len(p.Items) > 100 {
+		errs = append(errs, errors.New("Items must have at most 100 items"))
+	}
+	if p.Offset < 0 {
+		errs = append(errs, errors.New("Offset must be greater than or equal to 0"))
+	}
+	if p.Offset%10 != 0 {
+		errs = append(errs, errors.New("Offset must be a multiple of 10"))
+	}
+	if float64(p.Limit) > 1000 {
+		errs = append(errs, errors.New("Limit must be less than or equal to 1000"))
+	}
+	return errors.Join(errs...)
+}
+`,
+		},
+		{
+			name:     "elements",
+			isValid:  true,
+			wantName: "api/zz_generated.validate.go",
+			files: map[string]string{
+				"api/order.go": `package api
+
+type Order struct {
+	// +openapi:schema:maxItems=10
+	// +openapi:schema:minLength=2
+	// +openapi:schema:pattern="^[a-z]+$"
+	Tags []string
+	// +openapi:schema:minimum=1
+	// +openapi:schema:multipleOf=5
+	Quantities []*int
+	// +openapi:schema:maximum=0.5
+	Discounts map[string]float64
+	Items map[string]Item
+}
+
+type Item struct {
+	// +openapi:schema:required
+	Name string
+}
+`,
+			},
+			want: `// Code generated by codemark. DO NOT EDIT.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	_orderTagsPattern = regexp.MustCompile("^[a-z]+$")
+)
+
+// Validate returns the violations of the constraints of the fields of
+// Order joined into one error.
+func (o Order) Validate() error {
+	var errs []error
+	if len(o.Tags) > 10 {
+		errs = append(errs, errors.New("Tags must have at most 10 items"))
+	}
+	for idx, elem := range o.Tags {
+		if utf8.RuneCountInString(string(elem)) < 2 {
+			errs = append(errs, fmt.Errorf("Tags[%d] must have at least 2 characters", idx))
+		}
+		if !_orderTagsPattern.MatchString(string(elem)) {
+			errs = append(errs, fmt.Errorf("Tags[%d] must match the pattern ^[a-z]+$", idx))
+		}
+	}
+	for idx, elem := range o.Quantities {
+		if elem != nil {
+			if (*elem) < 1 {
+				errs = append(errs, fmt.Errorf("Quantities[%d] must be greater than or equal to 1", idx))
+			}
+			if (*elem)%5 != 0 {
+				errs = append(errs, fmt.Errorf("Quantities[%d] must be a multiple of 5", idx))
+			}
+		}
+	}
+	for idx, elem := range o.Discounts {
+		if elem > 0.5 {
+			errs = append(errs, fmt.Errorf("Discounts[%v] must be less than or equal to 0.5", idx))
+		}
+	}
+	for idx, elem := range o.Items {
+		if err := elem.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Items[%v]: %w", idx, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate returns the violations of the constraints of the fields of
+// Item joined into one error.
+func (i Item) Validate() error {
+	var errs []error
+	if i.Name == "" {
+		errs = append(errs, errors.New("Name is required"))
+	}
+	return errors.Join(errs...)
+}
+`,
+		},
+		{
+			name:     "exact integer bounds",
+			isValid:  true,
+			wantName: "api/zz_generated.validate.go",
+			files: map[string]string{
+				"api/id.go": `package api
+
+type Record struct {
+	// +openapi:schema:minimum=9007199254740993
+	// +openapi:schema:multipleOf=9007199254740993
+	ID int64
+	// +openapi:schema:maximum=1e3
+	Size int
+}
+`,
+			},
+			want: `// Code generated by codemark. DO NOT EDIT.
+
+package api
+
+import (
+	"errors"
+)
+
+// Validate returns the violations of the constraints of the fields of
+// Record joined into one error.
+func (r Record) Validate() error {
+	var errs []error
+	if r.ID < 9007199254740993 {
+		errs = append(errs, errors.New("ID must be greater than or equal to 9007199254740993"))
+	}
+	if r.ID%9007199254740993 != 0 {
+		errs = append(errs, errors.New("ID must be a multiple of 9007199254740993"))
+	}
+	if r.Size > 1000 {
+		errs = append(errs, errors.New("Size must be less than or equal to 1e3"))
+	}
+	return errors.Join(errs...)
+}
+`,
+		},
+		{
+			name:     "regeneration",
+			isValid:  true,
+			wantName: "api/zz_generated.validate.go",
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +openapi:schema:required
+	Name *string
+}
+`,
+				"api/zz_generated.validate.go": `package api
+
+func (u User) Validate() error { return nil }
+`,
+			},
+			want: `// Code generated by codemark. DO NOT EDIT.
+
+package api
+
+import (
+	"errors"
+)
+
+// Validate returns the violations of the constraints of the fields of
+// User joined into one error.
+func (u User) Validate() error {
+	var errs []error
+	if u.Name == nil {
+		errs = append(errs, errors.New("Name is required"))
+	}
+	return errors.Join(errs...)
+}
+`,
+		},
+		{
+			name:    "existing validate method",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +openapi:schema:required
+	Name string
+}
+
+func (u User) Validate() error { return nil }
+`,
+			},
+		},
+		{
+			name:    "invalid pattern",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +openapi:schema:pattern="[a-z"
+	Name string
+}
+`,
+			},
+		},
+		{
+			name:    "inexact float bound",
+			isValid: false,
+			files: map[string]string{
+				"api/price.go": `package api
+
+type Price struct {
+	// +openapi:schema:maximum=9007199254740993
+	Amount float64
+}
+`,
+			},
+		},
+		{
+			name:    "enum of wrong type",
+			isValid: false,
+			files: map[string]string{
+				"api/user.go": `package api
+
+type User struct {
+	// +openapi:schema:enum=[1, 2]
+	Name string
+}
+`,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts, err := loadertest.Generate(t, New, tc.files, nil, openapi.New)
+			if !tc.isValid && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !tc.isValid {
+				return
+			}
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			if len(artifacts) != 1 {
+				t.Fatalf("expected one artifact. got: %d", len(artifacts))
+			}
+			if artifacts[0].Name != tc.wantName {
+				t.Fatalf("name is not equal. got: %s; want: %s", artifacts[0].Name, tc.wantName)
+			}
+			data, err := io.ReadAll(artifacts[0].Data)
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			if string(data) != tc.want {
+				t.Errorf("file is not equal\ngot:\n%s\nwant:\n%s", data, tc.want)
+			}
+			dir := path.Dir(tc.wantName)
+			if err := typeCheck(t, tc.files, dir, string(data)); err != nil {
+				t.Errorf("generated code does not compile: %v", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package loader
 
 import (
 	"errors"
+	"go/token"
 	"go/types"
 
 	"golang.org/x/tools/go/packages"
@@ -20,8 +21,9 @@ type Loader interface {
 	Load(patterns ...string) (map[*packages.Package]*infov1.Information, error)
 }
 
-func newInformation() *infov1.Information {
+func newInformation(fset *token.FileSet) *infov1.Information {
 	return &infov1.Information{
+		Fset:    fset,
 		Structs: make(map[types.Object]*infov1.StructInfo),
 		Ifaces:  make(map[types.Object]*infov1.IfaceInfo),
 		Aliases: make(map[types.Object]*infov1.AliasInfo),
@@ -1,9 +1,7 @@
-package loader
+package loader_test
 
 import (
 	randv2 "math/rand/v2"
-	"os"
-	"path"
 	"reflect"
 	"strconv"
 	"strings"
@@ -85,6 +83,7 @@ func (p *project) randomize() {
 			if !found {
 				break
 			}
+			imp = randImportDecl()
 		}
 		p.Imports[imp.PackagePath] = imp
 		p.Vars[imp.Use.Ident] = imp.Use
@@ -100,18 +99,15 @@ func (p *project) randomize() {
 	}
 }
 
-func (p *project) parse(glob string) (string, error) {
+// render executes the templates matching `glob` and returns the generated
+// files indexed by their filename.
+func (p *project) render(glob string) (map[string]string, error) {
 	funcs := template.FuncMap{
 		"join": strings.Join,
 	}
 	tmpl, err := template.New("").Funcs(funcs).ParseGlob(glob)
 	if err != nil {
-		return "", err
-	}
-	tmpDir := os.TempDir()
-	dir, err := os.MkdirTemp(tmpDir, "cm-project")
-	if err != nil {
-		return "", err
+		return nil, err
 	}
 	for _, t := range tmpl.Templates() {
 		filename, _ := strings.CutSuffix(t.Name(), ".tmpl")
@@ -120,18 +116,16 @@ func (p *project) parse(glob string) (string, error) {
 		}
 		p.Files[filename] = File{Markers: randMarkers(_defaultNum)}
 	}
+	files := make(map[string]string, len(tmpl.Templates()))
 	for _, t := range tmpl.Templates() {
 		filename, _ := strings.CutSuffix(t.Name(), ".tmpl")
-		path := path.Join(dir, filename)
-		file, err := os.Create(path)
-		if err != nil {
-			return "", err
-		}
-		if err := t.Execute(file, p); err != nil {
-			return "", err
+		var b strings.Builder
+		if err := t.Execute(&b, p); err != nil {
+			return nil, err
 		}
+		files[filename] = b.String()
 	}
-	return dir, nil
+	return files, nil
 }
 
 func randAliasDecl(rhs string) AliasDecl {
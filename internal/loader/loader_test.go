@@ -1,48 +1,358 @@
-package loader
+package loader_test
 
 import (
+	"context"
 	"fmt"
 	"go/types"
+	"iter"
+	"maps"
+	"path/filepath"
+	"slices"
 	"testing"
 
-	"golang.org/x/tools/go/packages"
-
 	infov1 "github.com/naivary/codemark/api/info/v1"
-	"github.com/naivary/codemark/converter"
 	"github.com/naivary/codemark/internal/rand"
+	"github.com/naivary/codemark/loader"
+	"github.com/naivary/codemark/loader/loadertest"
 	"github.com/naivary/codemark/marker"
+	"github.com/naivary/codemark/marker/markertest"
 	"github.com/naivary/codemark/registry/registrytest"
 )
 
-// TODO: add methods over multiple files in same package
 func TestLoader_Local(t *testing.T) {
 	proj := newProject()
 	addCustomDecls(proj)
-	dir, err := proj.parse("tmpl/*")
+	files, err := proj.render("tmpl/*")
 	if err != nil {
 		t.Errorf("unexpected error occured: %s", err)
 	}
-	cfg := &packages.Config{
-		Dir: dir,
-	}
 	reg, err := registrytest.NewRegistry(registrytest.NewOptsSet())
 	if err != nil {
 		t.Errorf("err occured: %s", err)
 	}
-	mngr, err := converter.NewManager(reg)
-	if err != nil {
-		t.Errorf("err occured: %s", err)
-	}
-	l := New(mngr, cfg)
-	information, err := l.Load(".")
+	information, err := loadertest.Load(t, files, reg, nil)
 	if err != nil {
 		t.Errorf("err occured: %s", err)
 	}
 	for _, info := range information {
 		if err := isValid(proj, info); err != nil {
-			t.Errorf("err occured while reading %s: %s", dir, err)
+			t.Errorf("err occured: %s", err)
+		}
+	}
+}
+
+func TestLoader_BuildFlags(t *testing.T) {
+	files := map[string]string{
+		"api.go":        "package codemark\n\ntype Public struct{}\n",
+		"enterprise.go": "//go:build enterprise\n\npackage codemark\n\ntype Enterprise struct{}\n",
+		"linux.go":      "//go:build linux\n\npackage codemark\n\ntype Linux struct{}\n",
+		"api_test.go":   "package codemark\n\ntype Fixture struct{}\n",
+	}
+	tests := []struct {
+		name string
+		cfg  *loader.Config
+		want []string
+	}{
+		{
+			name: "default",
+			cfg:  &loader.Config{GOOS: "linux"},
+			want: []string{"Public", "Linux"},
+		},
+		{
+			name: "build tags",
+			cfg:  &loader.Config{GOOS: "windows", BuildTags: []string{"enterprise"}},
+			want: []string{"Public", "Enterprise"},
+		},
+		{
+			name: "goos",
+			cfg:  &loader.Config{GOOS: "windows"},
+			want: []string{"Public"},
+		},
+		{
+			name: "tests",
+			cfg:  &loader.Config{GOOS: "windows", Tests: true},
+			want: []string{"Public", "Fixture"},
+		},
+	}
+	reg, err := registrytest.NewRegistry(registrytest.NewOptsSet())
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			proj, err := loadertest.Load(t, files, reg, tc.cfg)
+			if err != nil {
+				t.Fatalf("err occured: %s", err)
+			}
+			if len(proj) != 1 {
+				t.Fatalf("expected exactly one package. got: %d", len(proj))
+			}
+			for _, info := range proj {
+				if len(info.Structs) != len(tc.want) {
+					t.Errorf("quantity not equal for structs. got: %d; want: %d", len(info.Structs), len(tc.want))
+				}
+				for _, name := range tc.want {
+					if !hasStruct(info, name) {
+						t.Errorf("struct not found: %s", name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoader_Overlay(t *testing.T) {
+	files := map[string]string{
+		"api.go": "package codemark\n\ntype Saved struct{}\n",
+	}
+	dir := loadertest.NewModule(t, files)
+	reg, err := registrytest.NewRegistry(registrytest.NewOptsSet())
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	cfg := &loader.Config{
+		Dir: dir,
+		Overlay: map[string][]byte{
+			filepath.Join(dir, "api.go"):     []byte("package codemark\n\ntype Unsaved struct{}\n"),
+			filepath.Join(dir, "new_api.go"): []byte("package codemark\n\ntype New struct{}\n"),
+		},
+	}
+	proj, err := loader.LoadWithConfig(reg, nil, cfg, ".")
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	for _, info := range proj {
+		for _, name := range []string{"Unsaved", "New"} {
+			if !hasStruct(info, name) {
+				t.Errorf("struct not found: %s", name)
+			}
+		}
+		if hasStruct(info, "Saved") {
+			t.Errorf("struct on disk should be overwritten by the overlay")
+		}
+	}
+}
+
+func TestLoader_Canceled(t *testing.T) {
+	dir := loadertest.NewModule(t, map[string]string{"api.go": "package codemark\n"})
+	reg, err := registrytest.NewRegistry(registrytest.NewOptsSet())
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = loader.LoadWithConfig(reg, nil, &loader.Config{Dir: dir, Context: ctx}, ".")
+	if err == nil {
+		t.Errorf("expected an error because the context is canceled")
+	}
+}
+
+func TestLoader_Order(t *testing.T) {
+	files := map[string]string{
+		"b.go": "package codemark\n\ntype Zeta struct {\n\tC, B int\n\tA string\n}\n\nfunc Alpha() {}\n",
+		"a.go": "package codemark\n\nconst Omega = 1\n\ntype Beta struct{}\n",
+	}
+	reg, err := registrytest.NewRegistry(registrytest.NewOptsSet())
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	proj, err := loadertest.Load(t, files, reg, nil)
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	pkg := infov1.Packages(proj)[0]
+	info := proj[pkg]
+	want := []string{"Omega", "Beta", "Zeta", "Alpha"}
+	if got := names(info.All()); !slices.Equal(got, want) {
+		t.Errorf("infos not in order of position. got: %v; want: %v", got, want)
+	}
+	want = []string{"Beta", "Zeta"}
+	if got := names(infov1.ByName(info.Structs)); !slices.Equal(got, want) {
+		t.Errorf("structs not in order of name. got: %v; want: %v", got, want)
+	}
+	for obj, s := range info.Structs {
+		if obj.Name() != "Zeta" {
+			continue
+		}
+		want := []string{"C", "B", "A"}
+		if got := names(infov1.ByPos(info.Fset, s.Fields)); !slices.Equal(got, want) {
+			t.Errorf("fields not in order of position. got: %v; want: %v", got, want)
+		}
+		want = []string{"A", "B", "C"}
+		if got := names(infov1.ByName(s.Fields)); !slices.Equal(got, want) {
+			t.Errorf("fields not in order of name. got: %v; want: %v", got, want)
+		}
+	}
+}
+
+func TestLoader_Methods(t *testing.T) {
+	files := map[string]string{
+		"types.go": `package codemark
+
+type Page[T any] struct {
+	Items []T
+}
+
+type Celsius float64
+
+type Temp = Celsius
+`,
+		"methods.go": `package codemark
+
+func (p *Page[T]) Len() int { return len(p.Items) }
+
+func (p Page[T]) First() T { return p.Items[0] }
+
+func (c *Temp) Set(v float64) { *c = Temp(v) }
+
+func (c Celsius) String() string { return "" }
+`,
+	}
+	reg, err := registrytest.NewRegistry(registrytest.NewOptsSet())
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	proj, err := loadertest.Load(t, files, reg, nil)
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	info := proj[infov1.Packages(proj)[0]]
+	for obj, s := range info.Structs {
+		want := []string{"First", "Len"}
+		if got := names(infov1.ByName(s.Methods)); !slices.Equal(got, want) {
+			t.Errorf("methods of %s not found. got: %v; want: %v", obj.Name(), got, want)
+		}
+	}
+	for obj, n := range info.Named {
+		want := []string{"Set", "String"}
+		if got := names(infov1.ByName(n.Methods)); !slices.Equal(got, want) {
+			t.Errorf("methods of %s not found. got: %v; want: %v", obj.Name(), got, want)
+		}
+	}
+}
+
+func TestLoader_Params(t *testing.T) {
+	files := map[string]string{
+		"func.go": `package codemark
+
+// Get returns the user with the given id.
+func Get(
+	// +codemark:testing:string="path"
+	id string,
+	verbose, dry bool,
+) (
+	// +codemark:testing:int=200
+	user string,
+	err error,
+) {
+	return "", nil
+}
+
+type Service struct{}
+
+func (s *Service) Do(
+	// +codemark:testing:bool=true
+	force bool,
+) error {
+	return nil
+}
+`,
+	}
+	reg, err := registrytest.NewRegistry(registrytest.NewOptsSet())
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	proj, err := loadertest.Load(t, files, reg, nil)
+	if err != nil {
+		t.Fatalf("err occured: %s", err)
+	}
+	info := proj[infov1.Packages(proj)[0]]
+	tests := []struct {
+		name    string
+		fn      *infov1.FuncInfo
+		params  []string
+		results []string
+		opts    map[string]string
+	}{
+		{
+			name:    "func",
+			fn:      funcInfoByName(info.Funcs, "Get"),
+			params:  []string{"id", "verbose", "dry"},
+			results: []string{"user", "err"},
+			opts: map[string]string{
+				"id":   markertest.NewIdent("string"),
+				"user": markertest.NewIdent("int"),
+			},
+		},
+		{
+			name:    "method",
+			fn:      funcInfoByName(info.Structs[lookup(info.Structs, "Service")].Methods, "Do"),
+			params:  []string{"force"},
+			results: []string{""},
+			opts: map[string]string{
+				"force": markertest.NewIdent("bool"),
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.fn == nil {
+				t.Fatalf("function not found")
+			}
+			if got := names(infov1.ByPos(info.Fset, tc.fn.Params)); !slices.Equal(got, tc.params) {
+				t.Errorf("params not equal. got: %v; want: %v", got, tc.params)
+			}
+			if got := names(infov1.ByPos(info.Fset, tc.fn.Results)); !slices.Equal(got, tc.results) {
+				t.Errorf("results not equal. got: %v; want: %v", got, tc.results)
+			}
+			params := maps.Clone(tc.fn.Params)
+			maps.Copy(params, tc.fn.Results)
+			for obj, param := range params {
+				ident, hasOpt := tc.opts[obj.Name()]
+				if !hasOpt && len(param.Opts) > 0 {
+					t.Errorf("unexpected options for %s: %v", obj.Name(), param.Opts)
+				}
+				if hasOpt && len(param.Opts[ident]) == 0 {
+					t.Errorf("option %s not found for %s", ident, obj.Name())
+				}
+			}
+		})
+	}
+}
+
+func funcInfoByName(infos map[types.Object]*infov1.FuncInfo, name string) *infov1.FuncInfo {
+	for obj, info := range infos {
+		if obj.Name() == name {
+			return info
+		}
+	}
+	return nil
+}
+
+func lookup[V any](infos map[types.Object]V, name string) types.Object {
+	for obj := range infos {
+		if obj.Name() == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+func names[V any](seq iter.Seq2[types.Object, V]) []string {
+	var res []string
+	for obj := range seq {
+		res = append(res, obj.Name())
+	}
+	return res
+}
+
+func hasStruct(info *infov1.Information, name string) bool {
+	for obj := range info.Structs {
+		if obj.Name() == name {
+			return true
 		}
 	}
+	return false
 }
 
 func isValid(p *project, info *infov1.Information) error {
@@ -19,6 +19,11 @@ type parseMarkers = func(doc string, target optionv1.Target) (infov1.Options, er
 
 var _ Loader = (*loader)(nil)
 
+type methodDecl struct {
+	decl     *ast.FuncDecl
+	comments ast.CommentMap
+}
+
 type loader struct {
 	mngr *converter.Manager
 
@@ -33,7 +38,8 @@ func New(mngr *converter.Manager, cfg *packages.Config) Loader {
 	l := &loader{
 		mngr: mngr,
 		cfg: &packages.Config{
-			Mode: packages.NeedTypesInfo | packages.NeedSyntax | packages.NeedTypes | packages.NeedImports | packages.NeedName,
+			Mode: packages.NeedTypesInfo | packages.NeedSyntax | packages.NeedTypes | packages.NeedImports | packages.NeedName |
+				packages.NeedForTest | packages.NeedModule,
 			ParseFile: func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
 				return parser.ParseFile(fset, filename, src, parser.ParseComments)
 			},
@@ -57,11 +63,17 @@ func (l *loader) Load(args ...string) (infov1.Project, error) {
 	if packages.PrintErrors(pkgs) > 0 {
 		return nil, ErrBadLoadRequest
 	}
+	if l.cfg.Tests {
+		pkgs = withoutTestDuplicates(pkgs)
+	}
 	if len(pkgs) == 0 {
 		return nil, ErrPkgsEmpty
 	}
 	proj := make(map[*packages.Package]*infov1.Information, len(pkgs))
 	for _, pkg := range pkgs {
+		if err := l.canceled(); err != nil {
+			return nil, err
+		}
 		info, err := extractInfos(pkg, l.mngr.ParseMarkers)
 		if err != nil {
 			return nil, err
@@ -71,6 +83,14 @@ func (l *loader) Load(args ...string) (infov1.Project, error) {
 	return proj, nil
 }
 
+// canceled returns the error of the context if the loading has been canceled.
+func (l *loader) canceled() error {
+	if l.cfg.Context == nil {
+		return nil
+	}
+	return l.cfg.Context.Err()
+}
+
 func objectOf(pkg *packages.Package, ident *ast.Ident) (types.Object, error) {
 	obj := pkg.TypesInfo.ObjectOf(ident)
 	if obj == nil {
@@ -88,12 +108,13 @@ func typeOf(pkg *packages.Package, expr ast.Expr) (types.Type, error) {
 }
 
 func extractInfos(pkg *packages.Package, parse parseMarkers) (*infov1.Information, error) {
-	info := newInformation()
-	methodDecls := make([]*ast.FuncDecl, 0)
+	info := newInformation(pkg.Fset)
+	methodDecls := make([]methodDecl, 0)
 	for _, file := range pkg.Syntax {
 		if err := extractFileInfo(pkg, parse, file, info); err != nil {
 			return nil, err
 		}
+		comments := ast.NewCommentMap(pkg.Fset, file, file.Comments)
 		for _, decl := range file.Decls {
 			// this part of the code is only responsible for finding the correct
 			// extract function.
@@ -101,11 +122,11 @@ func extractInfos(pkg *packages.Package, parse parseMarkers) (*infov1.Informatio
 			var isTypeToken bool
 			funcDecl, isFunc := decl.(*ast.FuncDecl)
 			if isFunc && isMethod(funcDecl) {
-				methodDecls = append(methodDecls, funcDecl)
+				methodDecls = append(methodDecls, methodDecl{decl: funcDecl, comments: comments})
 				continue
 			}
 			if isFunc && !isMethod(funcDecl) {
-				err = extractFuncInfo(pkg, parse, funcDecl, info)
+				err = extractFuncInfo(pkg, parse, funcDecl, comments, info)
 			}
 			if err != nil {
 				return nil, err
@@ -163,8 +184,10 @@ func extractInfos(pkg *packages.Package, parse parseMarkers) (*infov1.Informatio
 			}
 		}
 	}
-	for _, methodDecl := range methodDecls {
-		if err := extractMethodInfo(pkg, parse, methodDecl, info); err != nil {
+	// methods are extracted after all types of the package are known because
+	// they can be declared in a different file than their receiver.
+	for _, method := range methodDecls {
+		if err := extractMethodInfo(pkg, parse, method.decl, method.comments, info); err != nil {
 			return nil, err
 		}
 	}
@@ -187,43 +210,58 @@ func extractFileInfo(pkg *packages.Package, parse parseMarkers, file *ast.File,
 	return nil
 }
 
-func extractMethodInfo(pkg *packages.Package, parse parseMarkers, decl *ast.FuncDecl, infos *infov1.Information) error {
+func extractMethodInfo(pkg *packages.Package, parse parseMarkers, decl *ast.FuncDecl, comments ast.CommentMap, infos *infov1.Information) error {
 	doc := decl.Doc.Text()
 	opts, err := parse(doc, optionv1.TargetMethod)
 	if err != nil {
 		return err
 	}
-	info := infov1.FuncInfo{
-		Decl: decl,
-		Opts: opts,
+	methObj, err := objectOf(pkg, decl.Name)
+	if err != nil {
+		return err
 	}
-	rec := decl.Recv.List[0].Type
-	recObj, err := objectOf(pkg, ident(rec))
+	info, err := funcInfoOf(parse, decl, methObj, comments, opts)
 	if err != nil {
 		return err
 	}
-	methObj, err := objectOf(pkg, decl.Name)
+	recObj, err := receiverOf(methObj)
 	if err != nil {
 		return err
 	}
 	return addMethodToType(recObj, methObj, info, infos)
 }
 
-func addMethodToType(receiver, method types.Object, methodInfo infov1.FuncInfo, info *infov1.Information) error {
+// receiverOf returns the object of the type declaring the method. Pointer
+// receivers, receivers using an alias and generic receivers e.g. `func (p
+// *Page[T])` are resolved to the declared type.
+func receiverOf(method types.Object) (types.Object, error) {
+	recv := method.Type().(*types.Signature).Recv()
+	typ := types.Unalias(recv.Type())
+	if ptr, isPtr := typ.(*types.Pointer); isPtr {
+		typ = types.Unalias(ptr.Elem())
+	}
+	named, isNamed := typ.(*types.Named)
+	if !isNamed {
+		return nil, fmt.Errorf("receiver is not a named type: %v", recv.Type())
+	}
+	return named.Origin().Obj(), nil
+}
+
+func addMethodToType(receiver, method types.Object, methodInfo *infov1.FuncInfo, info *infov1.Information) error {
 	named, isNamed := info.Named[receiver]
 	if isNamed {
-		named.Methods[method] = &methodInfo
+		named.Methods[method] = methodInfo
 		return nil
 	}
 	strct, isStruct := info.Structs[receiver]
 	if !isStruct {
 		return fmt.Errorf("type is not extracted yet: %v", receiver)
 	}
-	strct.Methods[method] = &methodInfo
+	strct.Methods[method] = methodInfo
 	return nil
 }
 
-func extractFuncInfo(pkg *packages.Package, parse parseMarkers, decl *ast.FuncDecl, infos *infov1.Information) error {
+func extractFuncInfo(pkg *packages.Package, parse parseMarkers, decl *ast.FuncDecl, comments ast.CommentMap, infos *infov1.Information) error {
 	doc := decl.Doc.Text()
 	opts, err := parse(doc, optionv1.TargetFunc)
 	if err != nil {
@@ -233,14 +271,71 @@ func extractFuncInfo(pkg *packages.Package, parse parseMarkers, decl *ast.FuncDe
 	if err != nil {
 		return err
 	}
-	info := infov1.FuncInfo{
-		Decl: decl,
-		Opts: opts,
+	info, err := funcInfoOf(parse, decl, obj, comments, opts)
+	if err != nil {
+		return err
 	}
-	infos.Funcs[obj] = &info
+	infos.Funcs[obj] = info
 	return nil
 }
 
+func funcInfoOf(parse parseMarkers, decl *ast.FuncDecl, obj types.Object, comments ast.CommentMap, opts infov1.Options) (*infov1.FuncInfo, error) {
+	sig := obj.Type().(*types.Signature)
+	params, err := paramInfoOf(parse, decl.Type.Params, sig.Params(), comments, optionv1.TargetFuncParam)
+	if err != nil {
+		return nil, err
+	}
+	results, err := paramInfoOf(parse, decl.Type.Results, sig.Results(), comments, optionv1.TargetFuncResult)
+	if err != nil {
+		return nil, err
+	}
+	info := infov1.FuncInfo{
+		Decl:    decl,
+		Opts:    opts,
+		Params:  params,
+		Results: results,
+	}
+	return &info, nil
+}
+
+// paramInfoOf returns the information of the parameters or results in
+// `fields`. The go parser is not attaching any documentation to parameters so
+// the comments directly in front of a parameter are used as documentation.
+func paramInfoOf(
+	parse parseMarkers,
+	fields *ast.FieldList,
+	vars *types.Tuple,
+	comments ast.CommentMap,
+	target optionv1.Target,
+) (map[types.Object]*infov1.ParamInfo, error) {
+	params := make(map[types.Object]*infov1.ParamInfo, vars.Len())
+	if fields == nil {
+		return params, nil
+	}
+	i := 0
+	for _, field := range fields.List {
+		opts, err := parse(docOf(comments, field), target)
+		if err != nil {
+			return nil, err
+		}
+		names := field.Names
+		if len(names) == 0 {
+			// unnamed parameter or result
+			names = []*ast.Ident{nil}
+		}
+		for _, name := range names {
+			info := infov1.ParamInfo{
+				Field: field,
+				Ident: name,
+				Opts:  opts,
+			}
+			params[vars.At(i)] = &info
+			i++
+		}
+	}
+	return params, nil
+}
+
 func extractVarInfo(pkg *packages.Package, parse parseMarkers, decl *ast.GenDecl, infos *infov1.Information) error {
 	specs := convertSpecs[*ast.ValueSpec](decl.Specs)
 	for _, spec := range specs {
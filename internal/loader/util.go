@@ -2,6 +2,9 @@ package loader
 
 import (
 	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
 )
 
 func _map[T, V any](ts []T, fn func(T) V) []V {
@@ -26,15 +29,41 @@ func isMethod(fn *ast.FuncDecl) bool {
 	return fn.Recv != nil
 }
 
-func ident(expr ast.Expr) *ast.Ident {
-	switch x := expr.(type) {
-	case *ast.Ident:
-		return x
-	case *ast.StarExpr:
-		return ident(x.X)
-	case *ast.SelectorExpr:
-		return ident(x.X)
-	default:
-		return nil
+// docOf returns the text of all comments associated with `node` which are
+// positioned before it.
+func docOf(comments ast.CommentMap, node ast.Node) string {
+	var b strings.Builder
+	for _, group := range comments[node] {
+		if group.End() > node.Pos() {
+			continue
+		}
+		b.WriteString(group.Text())
+	}
+	return b.String()
+}
+
+// withoutTestDuplicates removes all packages which are loaded twice because of
+// the test files. If tests are included the go command reports a package once
+// on its own, once compiled together with its test files and a generated test
+// binary. Only the variant including the test files will be kept so every
+// declaration is extracted exactly once.
+func withoutTestDuplicates(pkgs []*packages.Package) []*packages.Package {
+	hasTestVariant := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		if pkg.ForTest == pkg.PkgPath {
+			hasTestVariant[pkg.PkgPath] = true
+		}
+	}
+	res := make([]*packages.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		isTestBinary := pkg.Name == "main" && strings.HasSuffix(pkg.ID, ".test")
+		if isTestBinary {
+			continue
+		}
+		if pkg.ForTest == "" && hasTestVariant[pkg.PkgPath] {
+			continue
+		}
+		res = append(res, pkg)
 	}
+	return res
 }
@@ -1,8 +1,10 @@
 package outputer
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/pflag"
 
@@ -58,7 +60,15 @@ func (o *fsOutputer) output(artifact *genv1.Artifact, args ...string) error {
 	if err != nil {
 		return err
 	}
-	filePath := filepath.Join(o.path, artifact.Name)
+	filePath, err := o.filePath(artifact.Name)
+	if err != nil {
+		return err
+	}
+	// artifacts can be located in sub directories e.g. the package of the
+	// generated code.
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
 	file, err := os.Create(filePath)
 	if err != nil {
 		return err
@@ -67,3 +77,20 @@ func (o *fsOutputer) output(artifact *genv1.Artifact, args ...string) error {
 	_, err = file.ReadFrom(artifact.Data)
 	return err
 }
+
+// filePath returns the path of the artifact `name` in the output directory.
+// Artifacts cannot be written outside of the output directory.
+func (o *fsOutputer) filePath(name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return "", fmt.Errorf("artifact name is absolute: %s", name)
+	}
+	filePath := filepath.Join(o.path, name)
+	rel, err := filepath.Rel(o.path, filePath)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("artifact is outside of the output directory `%s`: %s", o.path, name)
+	}
+	return filePath, nil
+}
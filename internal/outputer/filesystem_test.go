@@ -0,0 +1,63 @@
+package outputer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	genv1 "github.com/naivary/codemark/api/generator/v1"
+)
+
+func TestFsOutputer_Output(t *testing.T) {
+	out := t.TempDir()
+	tests := []struct {
+		name     string
+		artifact string
+		want     string
+		isValid  bool
+	}{
+		{
+			name:     "relative",
+			artifact: "api/user.json",
+			want:     filepath.Join(out, "api", "user.json"),
+			isValid:  true,
+		},
+		{
+			name:     "absolute",
+			artifact: filepath.Join(t.TempDir(), "zz_generated.validate.go"),
+			isValid:  false,
+		},
+		{
+			name:     "outside",
+			artifact: "../user.json",
+			isValid:  false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := NewFsOutputer()
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			artifact := &genv1.Artifact{Name: tc.artifact, Data: bytes.NewBufferString(tc.name)}
+			err = o.Output([]*genv1.Artifact{artifact}, "--fs.path="+out)
+			if !tc.isValid && err == nil {
+				t.Fatalf("expected an error for artifact: %s", tc.artifact)
+			}
+			if !tc.isValid {
+				return
+			}
+			if err != nil {
+				t.Fatalf("err occurred: %v", err)
+			}
+			data, err := os.ReadFile(tc.want)
+			if err != nil {
+				t.Fatalf("artifact not written to %s: %v", tc.want, err)
+			}
+			if string(data) != tc.name {
+				t.Errorf("content is not equal. got: %s; want: %s", data, tc.name)
+			}
+		})
+	}
+}
@@ -171,6 +171,7 @@ func parseInt(p *parser, t lexer.Token) (parseFunc, bool) {
 	}
 	p.m.Kind = marker.INT
 	p.m.Value = rvalue
+	p.m.Literal = t.Value
 	return parseEOF, _next
 }
 
@@ -186,6 +187,7 @@ func parseFloat(p *parser, t lexer.Token) (parseFunc, bool) {
 	}
 	p.m.Kind = marker.FLOAT
 	p.m.Value = reflect.ValueOf(val)
+	p.m.Literal = t.Value
 	return parseEOF, _next
 }
 
@@ -0,0 +1,65 @@
+// Package source contains helpers to read the doc comments and the location of
+// the loaded go packages which are shared by the generators.
+package source
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/naivary/codemark/internal/lexer"
+	"github.com/naivary/codemark/internal/lexer/token"
+)
+
+// DocOf returns the text of the comment groups without the lines of markers.
+// Lines starting with a "+" which are not markers e.g. "+1 for this" are
+// kept.
+func DocOf(groups ...*ast.CommentGroup) string {
+	lines := make([]string, 0)
+	for _, group := range groups {
+		for line := range strings.SplitSeq(group.Text(), "\n") {
+			if isMarker(line) {
+				continue
+			}
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// SpecDocOf returns the doc text of a type spec with the doc comment `doc`
+// declared by `decl`. The doc of the declaration is only used if it declares
+// a single type.
+func SpecDocOf(decl *ast.GenDecl, doc *ast.CommentGroup) string {
+	if decl != nil && len(decl.Specs) == 1 {
+		return DocOf(decl.Doc, doc)
+	}
+	return DocOf(doc)
+}
+
+// isMarker reports whether `line` is a marker which is the case if the lexer
+// recognizes a valid identifier after the plus.
+func isMarker(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "+") {
+		return false
+	}
+	l := lexer.Lex(line)
+	return l.NextToken().Kind == token.PLUS && l.NextToken().Kind == token.IDENT
+}
+
+// PackageDirOf returns the slash separated directory of `pkg` relative to the
+// root of its module.
+func PackageDirOf(pkg *packages.Package) (string, error) {
+	if pkg.Module == nil || pkg.Module.Dir == "" {
+		return "", fmt.Errorf("module of package `%s` not found", pkg.PkgPath)
+	}
+	rel, err := filepath.Rel(pkg.Module.Dir, pkg.Dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
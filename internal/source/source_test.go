@@ -0,0 +1,41 @@
+package source
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func TestDocOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []string
+		want     string
+	}{
+		{
+			name:     "markers",
+			comments: []string{"// User is a user.", "// +openapi:schema:required", "// +docs:struct:page"},
+			want:     "User is a user.",
+		},
+		{
+			name:     "plus which is not a marker",
+			comments: []string{"// Counter counts.", "// +1 is added on every call.", "// + is the operator."},
+			want:     "Counter counts.\n+1 is added on every call.\n+ is the operator.",
+		},
+		{
+			name:     "invalid identifier",
+			comments: []string{"// +foo bar"},
+			want:     "+foo bar",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			group := &ast.CommentGroup{}
+			for _, text := range tc.comments {
+				group.List = append(group.List, &ast.Comment{Text: text})
+			}
+			if got := DocOf(group); got != tc.want {
+				t.Errorf("doc is not equal. got: %q; want: %q", got, tc.want)
+			}
+		})
+	}
+}
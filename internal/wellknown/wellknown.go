@@ -0,0 +1,98 @@
+// Package wellknown contains the go types which are encoded in a well-known
+// way e.g. time.Time as an RFC 3339 string. Generators translate the encoding
+// into their own type system.
+package wellknown
+
+import (
+	"go/token"
+	"go/types"
+	"maps"
+)
+
+// Encoding is the JSON encoding of a well-known type.
+type Encoding int
+
+const (
+	// DateTime is an RFC 3339 string.
+	DateTime Encoding = iota + 1
+	// Duration is an integer of nanoseconds.
+	Duration
+	// Bytes is a base64 encoded string.
+	Bytes
+	// IP is a string of an IPv4 or IPv6 address.
+	IP
+	// URI is a string of an URI.
+	URI
+	// UUID is a string of an UUID.
+	UUID
+	// Any is any JSON value.
+	Any
+)
+
+// _types maps well-known go types to their encoding. The keys are the fully
+// qualified names of the types e.g. `time.Time` or
+// `github.com/google/uuid.UUID`.
+var _types = map[string]Encoding{
+	"time.Time":                   DateTime,
+	"time.Duration":               Duration,
+	"[]byte":                      Bytes,
+	"net.IP":                      IP,
+	"net/url.URL":                 URI,
+	"github.com/google/uuid.UUID": UUID,
+	"encoding/json.RawMessage":    Any,
+}
+
+// _textMarshaler is the `encoding.TextMarshaler` interface. Types implementing
+// it are encoded as JSON strings.
+var _textMarshaler = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(token.NoPos, nil, "MarshalText", types.NewSignatureType(
+		nil, nil, nil,
+		nil,
+		types.NewTuple(
+			types.NewVar(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte])),
+			types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type()),
+		),
+		false,
+	)),
+}, nil).Complete()
+
+// Types returns a copy of the table of well-known types indexed by their key
+// (see KeyOf).
+func Types() map[string]Encoding {
+	return maps.Clone(_types)
+}
+
+// Lookup returns the encoding of `typ` if it is a well-known type. An alias
+// can be well-known itself e.g. `encoding/json.RawMessage` which is an alias
+// of `encoding/json/jsontext.Value` in newer go versions.
+func Lookup(typ types.Type) (Encoding, bool) {
+	if alias, isAlias := typ.(*types.Alias); isAlias {
+		if enc, isMapped := _types[types.TypeString(alias, nil)]; isMapped {
+			return enc, true
+		}
+	}
+	enc, isMapped := _types[KeyOf(typ)]
+	return enc, isMapped
+}
+
+// KeyOf returns the key of `typ` in the table of well-known types.
+func KeyOf(typ types.Type) string {
+	typ = types.Unalias(typ)
+	if slice, isSlice := typ.(*types.Slice); isSlice {
+		// []byte and []uint8 are the same type but formatted differently.
+		if basic, isBasic := slice.Elem().(*types.Basic); isBasic && basic.Kind() == types.Uint8 {
+			return "[]byte"
+		}
+	}
+	return types.TypeString(typ, nil)
+}
+
+// IsTextMarshaler reports whether `named` or a pointer to it implements
+// `encoding.TextMarshaler` which encodes its values as JSON strings.
+// Interfaces are never reported because their dynamic type is encoded.
+func IsTextMarshaler(named *types.Named) bool {
+	if _, isIface := named.Underlying().(*types.Interface); isIface {
+		return false
+	}
+	return types.Implements(named, _textMarshaler) || types.Implements(types.NewPointer(named), _textMarshaler)
+}
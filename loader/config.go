@@ -0,0 +1,80 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Config is controlling how and which files of the matched packages are
+// loaded. The zero value is loading the packages the same way `go build` would
+// for the current platform from the current working directory.
+type Config struct {
+	// Tests defines whether the test files of the packages should be loaded
+	// too. If a package has test files the package will be loaded including
+	// them instead of being loaded twice.
+	Tests bool `yaml:"tests"`
+
+	// BuildTags are the additional build tags to consider while selecting the
+	// files of a package e.g. `enterprise` for files with the constraint
+	// `//go:build enterprise`.
+	BuildTags []string `yaml:"buildTags"`
+
+	// GOOS is the target operating system to load the packages for. If empty
+	// the value of the environment will be used.
+	GOOS string `yaml:"goos"`
+
+	// GOARCH is the target architecture to load the packages for. If empty
+	// the value of the environment will be used.
+	GOARCH string `yaml:"goarch"`
+
+	// Dir is the directory in which the patterns are evaluated. If empty the
+	// current working directory will be used.
+	Dir string `yaml:"-"`
+
+	// Overlay maps absolute file paths to their content. The content will be
+	// used instead of the content on disk which allows to load unsaved
+	// buffers of an editor. A file which does not exist on disk will be
+	// treated as if it would exist in its directory.
+	Overlay map[string][]byte `yaml:"-"`
+
+	// Context allows to cancel the loading of the packages. If nil the
+	// loading cannot be canceled.
+	Context context.Context `yaml:"-"`
+
+	// Logf is used to log debug information of the loading process. If nil
+	// no debug information will be logged.
+	Logf func(format string, args ...any) `yaml:"-"`
+}
+
+// packagesConfig returns the configuration for `packages.Load` representing
+// c. The mode is set by the internal loader.
+func (c *Config) packagesConfig() *packages.Config {
+	if c == nil {
+		return nil
+	}
+	cfg := &packages.Config{
+		Tests:   c.Tests,
+		Dir:     c.Dir,
+		Overlay: c.Overlay,
+		Context: c.Context,
+		Logf:    c.Logf,
+	}
+	if len(c.BuildTags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(c.BuildTags, ",")}
+	}
+	env := make([]string, 0, 2)
+	if c.GOOS != "" {
+		env = append(env, "GOOS="+c.GOOS)
+	}
+	if c.GOARCH != "" {
+		env = append(env, "GOARCH="+c.GOARCH)
+	}
+	if len(env) > 0 {
+		// later values take precedence over the inherited environment.
+		cfg.Env = append(os.Environ(), env...)
+	}
+	return cfg
+}
@@ -15,11 +15,18 @@ import (
 // Load is extracting all the type informations including, while parsing the
 // found markers.
 func Load(reg regv1.Registry, convs []convv1.Converter, patterns ...string) (map[*packages.Package]*infov1.Information, error) {
+	return LoadWithConfig(reg, convs, nil, patterns...)
+}
+
+// LoadWithConfig is like Load but allows to control which files of the
+// packages are loaded e.g. test files or files behind build tags. `cfg` might
+// be nil.
+func LoadWithConfig(reg regv1.Registry, convs []convv1.Converter, cfg *Config, patterns ...string) (map[*packages.Package]*infov1.Information, error) {
 	mngr, err := converter.NewManager(reg, convs...)
 	if err != nil {
 		return nil, err
 	}
-	l := loader.New(mngr, nil)
+	l := loader.New(mngr, cfg.packagesConfig())
 	if len(patterns) == 0 {
 		return nil, fmt.Errorf("patterns cannot be empty because no projects can be loaded")
 	}
@@ -0,0 +1,107 @@
+package loadertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	convv1 "github.com/naivary/codemark/api/converter/v1"
+	genv1 "github.com/naivary/codemark/api/generator/v1"
+	infov1 "github.com/naivary/codemark/api/info/v1"
+	regv1 "github.com/naivary/codemark/api/registry/v1"
+	"github.com/naivary/codemark/internal/config"
+	"github.com/naivary/codemark/loader"
+	"github.com/naivary/codemark/registry"
+)
+
+const _goMod = "go.mod"
+
+// DefaultGoMod is the go.mod used by NewModule if none is provided.
+const DefaultGoMod = "module loadertest\n\ngo 1.24\n"
+
+// NewModule writes `files` into a throwaway directory which is removed after
+// the test finished and returns the path of the directory. `files` maps the
+// slash separated filename, relative to the module root, to its source. If no
+// go.mod is included DefaultGoMod will be used. The test fails if a file
+// cannot be written.
+func NewModule(tb testing.TB, files map[string]string) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	if _, found := files[_goMod]; !found {
+		if err := os.WriteFile(filepath.Join(dir, _goMod), []byte(DefaultGoMod), 0o600); err != nil {
+			tb.Fatalf("go.mod cannot be written: %v", err)
+		}
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			tb.Fatalf("directory of `%s` cannot be created: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+			tb.Fatalf("`%s` cannot be written: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// Load builds a throwaway module from `files` using NewModule and loads all of
+// its packages. The options of `cfg` are respected beside the directory which
+// is always the root of the module. `cfg` might be nil.
+func Load(
+	tb testing.TB,
+	files map[string]string,
+	reg regv1.Registry,
+	cfg *loader.Config,
+	convs ...convv1.Converter,
+) (infov1.Project, error) {
+	tb.Helper()
+	c := loader.Config{}
+	if cfg != nil {
+		c = *cfg
+	}
+	c.Dir = NewModule(tb, files)
+	return loader.LoadWithConfig(reg, convs, &c, "./...")
+}
+
+// Generate builds a throwaway module from `files` using NewModule and returns
+// the artifacts generated for all of its packages by the generator created
+// using `newGen` e.g. the `New` function of the generator. The config is
+// validated like the generator manager does. The markers of the generators
+// created using `deps` are loaded too e.g. of another generator whose markers
+// are used by the generator.
+func Generate(
+	tb testing.TB,
+	newGen func() (genv1.Generator, error),
+	files map[string]string,
+	cfg map[string]any,
+	deps ...func() (genv1.Generator, error),
+) ([]*genv1.Artifact, error) {
+	tb.Helper()
+	gen, err := newGen()
+	if err != nil {
+		tb.Fatalf("generator cannot be created: %v", err)
+	}
+	regs := []regv1.Registry{gen.Registry()}
+	for _, newDep := range deps {
+		dep, err := newDep()
+		if err != nil {
+			tb.Fatalf("generator cannot be created: %v", err)
+		}
+		regs = append(regs, dep.Registry())
+	}
+	if schemer, isSchemer := gen.(genv1.ConfigSchemer); isSchemer {
+		fsys, root := schemer.ConfigSchema()
+		if err := config.Validate(fsys, root, cfg); err != nil {
+			return nil, err
+		}
+	}
+	reg, err := registry.Merge(regs...)
+	if err != nil {
+		return nil, err
+	}
+	proj, err := Load(tb, files, reg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return gen.Generate(proj, cfg)
+}
@@ -21,6 +21,10 @@ type Marker struct {
 	Ident string
 	Kind  Kind
 	Value reflect.Value
+
+	// Literal is the value of an INT or FLOAT marker as written in the
+	// comment e.g. `0.950`. It is only set by the parser.
+	Literal string
 }
 
 // NewMarker returns a new Marker WITHOUT any validations. If you want to create